@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/parser"
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+
+// batchJob - conversion of one inteltool log in the batch mode
+// input  : path to the inteltool log
+// output : path to the generated file
+// err    : conversion error
+// diags  : problems found in the inteltool log
+type batchJob struct {
+	input  string
+	output string
+	err    error
+	diags  bytes.Buffer
+}
+
+// convert - converts the inteltool log to the generated file. The parser
+// messages are discarded, because the jobs are running at the same time.
+// opts : converter settings
+func (job *batchJob) convert(opts *config.Options) {
+	input, err := os.Open(job.input)
+	if err != nil {
+		job.err = err
+		return
+	}
+	defer input.Close()
+	data := parser.NewParserData(opts, nil)
+	if job.err = data.Parse(input); job.err != nil {
+		return
+	}
+	problems := data.DiagnosticsFprint(&job.diags, job.input)
+	if problems != 0 && opts.IsStrict() {
+		job.err = fmt.Errorf("%d problems found (-strict)", problems)
+		return
+	}
+	if data.PadsNumGet() == 0 {
+		job.err = fmt.Errorf("no pads found, check the platform and template")
+		return
+	}
+	output, err := os.Create(job.output)
+	if err != nil {
+		job.err = err
+		return
+	}
+	if job.err = generateOutputFile(data, output, opts); job.err != nil {
+		output.Close()
+		return
+	}
+	job.err = output.Close()
+}
+
+// batchInputsGet - returns the list of inteltool logs
+// pattern : directory with the logs or glob pattern
+// return
+//     sorted list of files and error
+func batchInputsGet(pattern string) ([]string, error) {
+	var inputs []string
+	if info, err := os.Stat(pattern); err == nil && info.IsDir() {
+		files, err := ioutil.ReadDir(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if file.Mode().IsRegular() && !strings.HasPrefix(file.Name(), ".") {
+				inputs = append(inputs, filepath.Join(pattern, file.Name()))
+			}
+		}
+	} else {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && info.Mode().IsRegular() {
+				inputs = append(inputs, match)
+			}
+		}
+	}
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("no files match %s", pattern)
+	}
+	sort.Strings(inputs)
+	return inputs, nil
+}
+
+// batchRootGet - returns the deepest directory that contains all inteltool logs
+// inputs : list of inteltool logs
+// return
+//     absolute path to the directory and error
+func batchRootGet(inputs []string) (string, error) {
+	var root string
+	for _, input := range inputs {
+		path, err := filepath.Abs(input)
+		if err != nil {
+			return "", err
+		}
+		if root == "" {
+			root = filepath.Dir(path)
+		}
+		for {
+			rel, err := filepath.Rel(root, path)
+			if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				break
+			}
+			root = filepath.Dir(root)
+		}
+	}
+	return root, nil
+}
+
+// batchNamesGet - returns the names of the generated files without the
+// extension. The file is named after the log: board.log -> board. If several
+// logs have the same name, the path relative to the directory with all logs
+// is used: boards/board1/inteltool.log -> board1_inteltool, and if the names
+// are still the same, the extension of the log is kept: board.log -> board_log
+// inputs : list of inteltool logs
+// return
+//     names of the generated files and error if the logs can not get unique
+//     names, e.g. the same log is given twice
+func batchNamesGet(inputs []string) ([]string, error) {
+	root, err := batchRootGet(inputs)
+	if err != nil {
+		return nil, err
+	}
+	relGet := func(input string) string {
+		path, _ := filepath.Abs(input)
+		rel, _ := filepath.Rel(root, path)
+		return rel
+	}
+	flatten := func(path string) string {
+		return strings.Replace(path, string(filepath.Separator), "_", -1)
+	}
+	namers := []func(input string) string{
+		func(input string) string {
+			base := filepath.Base(input)
+			return strings.TrimSuffix(base, filepath.Ext(base))
+		},
+		func(input string) string {
+			rel := relGet(input)
+			return flatten(strings.TrimSuffix(rel, filepath.Ext(rel)))
+		},
+		func(input string) string {
+			return flatten(strings.Replace(relGet(input), ".", "_", -1))
+		},
+	}
+	names := make([]string, len(inputs))
+	for i, input := range inputs {
+		names[i] = namers[0](input)
+	}
+	for _, namer := range namers[1:] {
+		count := make(map[string]int)
+		for _, name := range names {
+			count[name]++
+		}
+		for i, input := range inputs {
+			if count[names[i]] > 1 {
+				names[i] = namer(input)
+			}
+		}
+	}
+	owners := make(map[string]string)
+	for i, name := range names {
+		if owner, clash := owners[name]; clash {
+			return nil, fmt.Errorf("%s and %s are converted to the same file %s",
+				owner, inputs[i], name)
+		}
+		owners[name] = inputs[i]
+	}
+	return names, nil
+}
+
+// batchJobsGet - creates a job for each inteltool log, the generated files are
+// named with batchNamesGet()
+// inputs : list of inteltool logs
+// outdir : directory for the generated files
+// ext    : extension of the generated files
+// return
+//     jobs and error if the logs can not get unique names
+func batchJobsGet(inputs []string, outdir string, ext string) ([]batchJob, error) {
+	names, err := batchNamesGet(inputs)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]batchJob, len(inputs))
+	for i, input := range inputs {
+		jobs[i] = batchJob{input: input, output: filepath.Join(outdir, names[i]+ext)}
+	}
+	return jobs, nil
+}
+
+// batchCommand - converts all inteltool logs from the directory or matching
+// the glob pattern in parallel and prints a summary of the failures
+// args : directory or glob pattern and optional output directory
+// opts : converter settings
+// return exit status
+func batchCommand(args []string, opts *config.Options) int {
+	if len(args) < 1 || len(args) > 2 {
+		fmt.Printf("Error! Usage: intelp2m [options] batch <dir|glob> [<output dir>]\n")
+		return 1
+	}
+	outdir := "generate"
+	if len(args) == 2 {
+		outdir = args[1]
+	}
+	inputs, err := batchInputsGet(args[0])
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+	if err := os.MkdirAll(outdir, os.ModePerm); err != nil {
+		fmt.Printf("Error! Can not create a directory for the generated files!\n")
+		return 1
+	}
+	ext := ".h"
+	if opts.IsJsonFormat() {
+		ext = ".json"
+	} else if opts.IsAslFormat() {
+		ext = ".asl"
+	} else if opts.IsSblStyleMacro() {
+		ext = ".yaml"
+	}
+	jobs, err := batchJobsGet(inputs, outdir, ext)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+
+	queue := make(chan *batchJob)
+	var wg sync.WaitGroup
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				job.convert(opts)
+			}
+		}()
+	}
+	for i := range jobs {
+		queue <- &jobs[i]
+	}
+	close(queue)
+	wg.Wait()
+
+	var failures int
+	for i := range jobs {
+		jobs[i].diags.WriteTo(os.Stderr)
+		if jobs[i].err != nil {
+			failures++
+		}
+	}
+	fmt.Printf("Converted %d of %d files to %s\n", len(jobs)-failures, len(jobs), outdir)
+	if failures == 0 {
+		return 0
+	}
+	fmt.Printf("Failed:\n")
+	for i := range jobs {
+		if job := &jobs[i]; job.err != nil {
+			fmt.Printf("\t%s: %v\n", job.input, job.err)
+		}
+	}
+	return 1
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+
+func TestBatchNamesGet(t *testing.T) {
+	tests := []struct {
+		inputs []string
+		names  []string
+	}{
+		{[]string{"logs/board1.log", "logs/board2.log"},
+			[]string{"board1", "board2"}},
+		{[]string{"boards/a/inteltool.log", "boards/b/inteltool.log", "boards/c.log"},
+			[]string{"a_inteltool", "b_inteltool", "c"}},
+		{[]string{"a/x/inteltool.log", "b/x/inteltool.log"},
+			[]string{"a_x_inteltool", "b_x_inteltool"}},
+		{[]string{"dir/board.log", "dir/board.txt"},
+			[]string{"board_log", "board_txt"}},
+		{[]string{"dir/board"}, []string{"board"}},
+	}
+	for _, test := range tests {
+		names, err := batchNamesGet(test.inputs)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", test.inputs, err)
+			continue
+		}
+		if !reflect.DeepEqual(names, test.names) {
+			t.Errorf("%v: got %v, want %v", test.inputs, names, test.names)
+		}
+	}
+
+	_, err := batchNamesGet([]string{"dir/board.log", "dir/board.log"})
+	want := "dir/board.log and dir/board.log are converted to the same file board_log"
+	if err == nil || err.Error() != want {
+		t.Errorf("same log: got error %v, want %s", err, want)
+	}
+}
+
+func TestBatchJobsGet(t *testing.T) {
+	jobs, err := batchJobsGet([]string{"a/x/inteltool.log", "b/board.log"}, "out", ".h")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{filepath.Join("out", "inteltool.h"), filepath.Join("out", "board.h")}
+	for i, job := range jobs {
+		if job.output != want[i] {
+			t.Errorf("%s: got %s, want %s", job.input, job.output, want[i])
+		}
+	}
+}
+
+func TestBatchInputsGet(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.log", "a.log", ".hidden.log", "c.txt"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		pattern string
+		inputs  []string
+	}{
+		{dir, []string{"a.log", "b.log", "c.txt"}},
+		{filepath.Join(dir, "*.log"), []string{".hidden.log", "a.log", "b.log"}},
+	}
+	for _, test := range tests {
+		inputs, err := batchInputsGet(test.pattern)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.pattern, err)
+			continue
+		}
+		var names []string
+		for _, input := range inputs {
+			names = append(names, filepath.Base(input))
+		}
+		if !reflect.DeepEqual(names, test.inputs) {
+			t.Errorf("%s: got %v, want %v", test.pattern, names, test.inputs)
+		}
+	}
+	if _, err := batchInputsGet(filepath.Join(dir, "*.json")); err == nil {
+		t.Errorf("no files: no error")
+	}
+}
+
+// TestBatchCommand - the logs are converted and the log without pads fails
+func TestBatchCommand(t *testing.T) {
+	dir := t.TempDir()
+	logs := map[string]string{
+		"board.log": strings.Join([]string{
+			"============= GPIO =============",
+			"------- GPIO Group GPP_A -------",
+			"0x0400: 0x0000001840000400 GPP_A0   RCIN#",
+		}, "\n"),
+		"empty.log": "",
+	}
+	for name, log := range logs {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(log), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	outdir := filepath.Join(dir, "generate")
+	opts := &config.Options{}
+	if status := batchCommand([]string{filepath.Join(dir, "*.log"), outdir}, opts); status != 1 {
+		t.Errorf("exit status %d, want 1", status)
+	}
+	gpio, err := ioutil.ReadFile(filepath.Join(outdir, "board.h"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(gpio), "PAD_CFG_NF(GPP_A0, NONE, DEEP, NF1),") {
+		t.Errorf("board.h:\n%s", gpio)
+	}
+	if _, err := os.Stat(filepath.Join(outdir, "empty.h")); err == nil {
+		t.Errorf("empty.h is generated for the log without pads")
+	}
+}
@@ -1,121 +1,248 @@
 package config
 
-import "os"
+import "strings"
+
+// Options - settings of the pad configuration converter. The zero value
+// contains the default settings: Sunrise platform, inteltool.log template,
+// high-level macros and gpio.h output. Options are passed explicitly to the
+// parser and to the macro generator, so several conversions with different
+// settings can be performed in one process.
+// platform      : platform type
+// template      : template type of the input file
+// fldstyle      : bit fields macros style
+// infolevel     : information level in the comments
+// ignoredFields : exclude ignored fields from advanced macros
+// nonChecking   : generate macros without checking
+// format        : output format
+// suppressed    : suppressed lint rules
+// detect        : detect the platform from the inteltool log header
+// strict        : fail on any warning in the input file
+// imageTable    : number of the GPIO table candidate in the firmware image
+type Options struct {
+	platform      uint8
+	detect        bool
+	strict        bool
+	template      int
+	fldstyle      uint8
+	infolevel     uint8
+	ignoredFields bool
+	nonChecking   bool
+	format        uint8
+	suppressed    map[string]bool
+	imageTable    int
+}
 
 const (
 	TempInteltool  int  = 0
 	TempGpioh      int  = 1
 	TempSpec       int  = 2
+	TempFsp        int  = 3
+	TempPinctrl    int  = 4
+	TempImage      int  = 5
 )
 
-var template int = 0
-
-func TemplateSet(temp int) bool {
-	if temp > TempSpec {
+func (opts *Options) TemplateSet(temp int) bool {
+	if temp > TempImage {
 		return false
 	} else {
-		template = temp
+		opts.template = temp
 		return true
 	}
 }
 
-func TemplateGet() int {
-	return template
+func (opts *Options) TemplateGet() int {
+	return opts.template
 }
 
 const (
 	SunriseType   uint8  = 0
 	LewisburgType uint8  = 1
 	ApolloType    uint8  = 2
+	CannonType    uint8  = 3
+	TigerType     uint8  = 4
+	AlderType     uint8  = 5
 )
 
-var key uint8 = SunriseType
-
 var platform = map[string]uint8{
 	"snr": SunriseType,
 	"lbg": LewisburgType,
-	"apl": ApolloType}
-func PlatformSet(name string) int {
+	"apl": ApolloType,
+	"cnl": CannonType,
+	"tgl": TigerType,
+	"adl": AlderType}
+
+// PlatformAuto - the platform is detected from the inteltool log header,
+// Sunrise is used if the header does not contain known hardware
+const PlatformAuto = "auto"
+
+func (opts *Options) PlatformSet(name string) int {
+	if name == PlatformAuto {
+		opts.platform = SunriseType
+		opts.detect = true
+		return 0
+	}
 	if platformType, valid := platform[name]; valid {
-		key = platformType
+		opts.platform = platformType
 		return 0
 	}
 	return -1
 }
-func PlatformGet() uint8 {
-	return key
+func (opts *Options) PlatformGet() uint8 {
+	return opts.platform
+}
+func (opts *Options) PlatformNameGet() string {
+	for name, platformType := range platform {
+		if platformType == opts.platform {
+			return name
+		}
+	}
+	return ""
 }
-func IsPlatform(platformType uint8) bool {
-	return platformType == key
+func (opts *Options) IsPlatformAuto() bool {
+	return opts.detect
 }
-func IsPlatformApollo() bool {
-	return IsPlatform(ApolloType)
+func (opts *Options) IsPlatform(platformType uint8) bool {
+	return platformType == opts.platform
 }
-func IsPlatformSunrise() bool {
-	return IsPlatform(SunriseType)
+func (opts *Options) IsPlatformApollo() bool {
+	return opts.IsPlatform(ApolloType)
 }
-func IsPlatformLewisburg() bool {
-	return IsPlatform(LewisburgType)
+func (opts *Options) IsPlatformSunrise() bool {
+	return opts.IsPlatform(SunriseType)
+}
+func (opts *Options) IsPlatformLewisburg() bool {
+	return opts.IsPlatform(LewisburgType)
+}
+func (opts *Options) IsPlatformCannon() bool {
+	return opts.IsPlatform(CannonType)
+}
+func (opts *Options) IsPlatformTiger() bool {
+	return opts.IsPlatform(TigerType)
+}
+func (opts *Options) IsPlatformAlder() bool {
+	return opts.IsPlatform(AlderType)
 }
 
-var InputRegDumpFile *os.File = nil
-var OutputGenFile *os.File = nil
+func (opts *Options) StrictFlagSet(flag bool) {
+	opts.strict = flag
+}
+func (opts *Options) IsStrict() bool {
+	return opts.strict
+}
 
-var ignoredFieldsFormat bool = false
-func IgnoredFieldsFlagSet(flag bool) {
-	ignoredFieldsFormat = flag
+// ImageTableSet - selects the GPIO table candidate in the firmware image,
+// 1 is the most plausible one
+func (opts *Options) ImageTableSet(number int) bool {
+	if number < 1 {
+		return false
+	}
+	opts.imageTable = number
+	return true
 }
-func AreFieldsIgnored() bool {
-	return ignoredFieldsFormat
+func (opts *Options) ImageTableGet() int {
+	if opts.imageTable == 0 {
+		return 1
+	}
+	return opts.imageTable
 }
 
-var nonCheckingFlag bool = false
-func NonCheckingFlagSet(flag bool) {
-	nonCheckingFlag = flag
+func (opts *Options) IgnoredFieldsFlagSet(flag bool) {
+	opts.ignoredFields = flag
 }
-func IsNonCheckingFlagUsed() bool {
-	return nonCheckingFlag
+func (opts *Options) AreFieldsIgnored() bool {
+	return opts.ignoredFields
 }
 
-var infolevel uint8 = 0
-func InfoLevelSet(lvl uint8) {
-	infolevel = lvl
+func (opts *Options) NonCheckingFlagSet(flag bool) {
+	opts.nonChecking = flag
 }
-func InfoLevelGet() uint8 {
-	return infolevel
+func (opts *Options) IsNonCheckingFlagUsed() bool {
+	return opts.nonChecking
+}
+
+func (opts *Options) InfoLevelSet(lvl uint8) {
+	opts.infolevel = lvl
+}
+func (opts *Options) InfoLevelGet() uint8 {
+	return opts.infolevel
 }
 
-var fldstyle uint8 = CbFlds
 const (
 	NoFlds  uint8  = 0
 	CbFlds  uint8  = 1 // coreboot style
 	FspFlds uint8  = 2 // FSP/edk2 style
 	RawFlds uint8  = 3 // raw DW0/1 values
+	SblFlds uint8  = 4 // Slim Bootloader GPIO_CFG_DATA
 )
 var fldstylemap = map[string]uint8{
 	"none" : NoFlds,
 	"cb"   : CbFlds,
 	"fsp"  : FspFlds,
-	"raw"  : RawFlds}
-func FldStyleSet(name string) int {
+	"raw"  : RawFlds,
+	"sbl"  : SblFlds}
+func (opts *Options) FldStyleSet(name string) int {
 	if style, valid := fldstylemap[name]; valid {
-		fldstyle = style
+		opts.fldstyle = style
+		return 0
+	}
+	return -1
+}
+func (opts *Options) FldStyleGet() uint8 {
+	return opts.fldstyle
+}
+func (opts *Options) IsFieldsMacroUsed() bool {
+	return opts.FldStyleGet() != NoFlds
+}
+func (opts *Options) IsCbStyleMacro() bool {
+	return opts.FldStyleGet() == CbFlds
+}
+func (opts *Options) IsFspStyleMacro() bool {
+	return opts.FldStyleGet() == FspFlds
+}
+func (opts *Options) IsRawFields() bool {
+	return opts.FldStyleGet() == RawFlds
+}
+func (opts *Options) IsSblStyleMacro() bool {
+	return opts.FldStyleGet() == SblFlds
+}
+
+const (
+	CFormat    uint8 = 0 // gpio.h with pad configuration macros
+	JsonFormat uint8 = 1 // decoded pads in JSON
+	AslFormat  uint8 = 2 // ACPI resources for the interrupts and GPIOs
+)
+var formatmap = map[string]uint8{
+	"c"    : CFormat,
+	"json" : JsonFormat,
+	"asl"  : AslFormat}
+func (opts *Options) OutputFormatSet(name string) int {
+	if format, valid := formatmap[name]; valid {
+		opts.format = format
 		return 0
 	}
 	return -1
 }
-func FldStyleGet() uint8 {
-	return fldstyle
+func (opts *Options) OutputFormatGet() uint8 {
+	return opts.format
 }
-func IsFieldsMacroUsed() bool {
-	return FldStyleGet() != NoFlds
+func (opts *Options) IsJsonFormat() bool {
+	return opts.OutputFormatGet() == JsonFormat
 }
-func IsCbStyleMacro() bool {
-	return FldStyleGet() == CbFlds
+func (opts *Options) IsAslFormat() bool {
+	return opts.OutputFormatGet() == AslFormat
 }
-func IsFspStyleMacro() bool {
-	return FldStyleGet() == FspFlds
+
+// LintSuppressSet - sets the lint rules that should not be reported
+// ids : comma-separated list of rule IDs. <rule>:<pad> suppresses the rule
+//       only for the pad, e.g. gpo-trig,edge-no-route:GPP_A1
+func (opts *Options) LintSuppressSet(ids string) {
+	opts.suppressed = make(map[string]bool)
+	for _, id := range strings.Split(ids, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			opts.suppressed[id] = true
+		}
+	}
 }
-func IsRawFields() bool {
-	return FldStyleGet() == RawFlds
+func (opts *Options) IsLintRuleSuppressed(rule string, pad string) bool {
+	return opts.suppressed[rule] || opts.suppressed[rule+":"+pad]
 }
@@ -1,7 +1,6 @@
 package cb
 
-import "../../config"
-import "../../platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
 
 type FieldMacros struct {}
 
@@ -19,9 +18,9 @@ type field struct {
 }
 
 // generate - wrapper for generating bitfield macros string
+// macro  : macro object
 // fileds : field structure
-func generate(fileds ...*field) {
-	macro := common.GetMacro()
+func generate(macro *common.Macro, fileds ...*field) {
 	var allhidden bool = true
 	for _, field := range fileds {
 		if field.unhide {
@@ -44,13 +43,12 @@ func generate(fileds ...*field) {
 }
 
 // DecodeDW0 - decode value of DW0 register
-func (FieldMacros) DecodeDW0() {
-	macro := common.GetMacro()
+func (FieldMacros) DecodeDW0(macro *common.Macro) {
 	dw0 := macro.Register(common.PAD_CFG_DW0)
-	generate(
+	generate(macro,
 		&field {
 			prefix : "PAD_FUNC",
-			unhide : config.InfoLevelGet() <= 3 || dw0.GetPadMode() != 0,
+			unhide : macro.Options.InfoLevelGet() <= 3 || dw0.GetPadMode() != 0,
 			configurator : func() { macro.Padfn() },
 		},
 
@@ -125,10 +123,9 @@ func (FieldMacros) DecodeDW0() {
 }
 
 // DecodeDW1 - decode value of DW1 register
-func (FieldMacros) DecodeDW1() {
-	macro := common.GetMacro()
+func (FieldMacros) DecodeDW1(macro *common.Macro) {
 	dw1 := macro.Register(common.PAD_CFG_DW1)
-	generate(
+	generate(macro,
 		&field {
 			name   : "PAD_CFG1_TOL_1V8",
 			unhide : dw1.GetPadTol() != 0,
@@ -160,12 +157,37 @@ func (FieldMacros) DecodeDW1() {
 	)
 }
 
+// DecodeDW2 - decode value of DW2 register
+func (FieldMacros) DecodeDW2(macro *common.Macro) {
+	dw2 := macro.Register(common.PAD_CFG_DW2)
+	generate(macro,
+		&field {
+			name   : "PAD_CFG2_DEBEN",
+			unhide : dw2.GetDebounceEnable() != 0,
+		},
+
+		&field {
+			unhide : dw2.GetDebounceDuration() != 0,
+			configurator : func() { macro.Debounce() },
+		},
+	)
+}
+
 // GenerateString - generates the entire string of bitfield macros.
-func (bitfields FieldMacros) GenerateString() {
-	macro := common.GetMacro()
-	macro.Add("_PAD_CFG_STRUCT(").Id().Add(", ")
-	bitfields.DecodeDW0()
+func (bitfields FieldMacros) GenerateString(macro *common.Macro) {
+	if macro.Register(common.PAD_CFG_DW2).ValueGet() == 0 {
+		macro.Add("_PAD_CFG_STRUCT(").Id().Add(", ")
+		bitfields.DecodeDW0(macro)
+		macro.Add(", ")
+		bitfields.DecodeDW1(macro)
+		macro.Add("),")
+		return
+	}
+	macro.Add("_PAD_CFG_STRUCT_3(").Id().Add(", ")
+	bitfields.DecodeDW0(macro)
+	macro.Add(", ")
+	bitfields.DecodeDW1(macro)
 	macro.Add(", ")
-	bitfields.DecodeDW1()
+	bitfields.DecodeDW2(macro)
 	macro.Add("),")
 }
@@ -1,20 +1,23 @@
 package fields
 
-import "../config"
-import "../platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
 
-import "./fsp"
-import "./cb"
-import "./raw"
+import "review.coreboot.org/coreboot.git/util/intelp2m/fields/fsp"
+import "review.coreboot.org/coreboot.git/util/intelp2m/fields/cb"
+import "review.coreboot.org/coreboot.git/util/intelp2m/fields/raw"
+import "review.coreboot.org/coreboot.git/util/intelp2m/fields/sbl"
 
-// InterfaceSet - set the interface for decoding configuration
+// InterfaceGet - returns the interface for decoding configuration
 // registers DW0 and DW1.
-func InterfaceGet() common.Fields {
+// opts : converter settings with the bit fields macros style
+func InterfaceGet(opts *config.Options) common.Fields {
 	var fldstylemap = map[uint8]common.Fields{
 		config.NoFlds  : cb.FieldMacros{}, // analyze fields using cb macros
 		config.CbFlds  : cb.FieldMacros{},
 		config.FspFlds : fsp.FieldMacros{},
 		config.RawFlds : raw.FieldMacros{},
+		config.SblFlds : sbl.FieldMacros{},
 	}
-	return fldstylemap[config.FldStyleGet()]
+	return fldstylemap[opts.FldStyleGet()]
 }
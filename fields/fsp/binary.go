@@ -0,0 +1,197 @@
+package fsp
+
+import "strings"
+
+// Config - values of the fields of the binary GPIO_CONFIG structure, see
+// GpioConfig.h in edk2-platforms. The fields are indexed in the same order as
+// in the GPIO_INIT_CONFIG entry.
+type Config [lockConfigField + 1]uint32
+
+// Values of the GPIO_CONFIG fields in the binary structure. Zero keeps the
+// hardware default in all fields.
+var padModeValues = map[uint32]string{
+	0x1: "GpioPadModeGpio",
+	0x3: "GpioPadModeNative1",
+	0x5: "GpioPadModeNative2",
+	0x7: "GpioPadModeNative3",
+	0x9: "GpioPadModeNative4",
+	0xb: "GpioPadModeNative5",
+}
+
+var hostSoftPadOwnValues = map[uint32]string{
+	0x1: "GpioHostOwnAcpi",
+	0x3: "GpioHostOwnGpio",
+}
+
+var directionValues = map[uint32]string{
+	0x09: "GpioDirInOut",
+	0x19: "GpioDirInInvOut",
+	0x0b: "GpioDirIn",
+	0x1b: "GpioDirInInv",
+	0x05: "GpioDirOut",
+	0x07: "GpioDirNone",
+}
+
+var outputStateValues = map[uint32]string{
+	0x1: "GpioOutLow",
+	0x3: "GpioOutHigh",
+}
+
+// interruptRouteValues - bits 4:0 of InterruptConfig, bit 0 is always set
+var interruptRouteValues = map[uint32]string{
+	0x02: "GpioIntNmi",
+	0x04: "GpioIntSmi",
+	0x08: "GpioIntSci",
+	0x10: "GpioIntApic",
+}
+
+// interruptTrigValues - bits 8:5 of InterruptConfig
+var interruptTrigValues = map[uint32]string{
+	0x1: "GpioIntLevel",
+	0x3: "GpioIntEdge",
+	0x5: "GpioIntLvlEdgDis",
+	0x7: "GpioIntBothEdge",
+}
+
+var powerConfigValues = map[uint32]string{
+	0x01: "GpioResumeReset",
+	0x03: "GpioHostDeepReset",
+	0x05: "GpioPlatformReset",
+	0x07: "GpioDswReset",
+	0x09: "GpioResetPwrGood",
+	0x0b: "GpioResetDeep",
+	0x0d: "GpioResetNormal",
+	0x0f: "GpioResetResume",
+}
+
+// terminationValues - bits 4:0 of ElectricalConfig
+var terminationValues = map[uint32]string{
+	0x01: "GpioTermNone",
+	0x05: "GpioTermWpd5K",
+	0x09: "GpioTermWpd20K",
+	0x13: "GpioTermWpu1K",
+	0x17: "GpioTermWpu2K",
+	0x15: "GpioTermWpu5K",
+	0x19: "GpioTermWpu20K",
+	0x1b: "GpioTermWpu1K2K",
+	0x1f: "GpioTermNative",
+}
+
+// toleranceValues - bits 6:5 of ElectricalConfig
+var toleranceValues = map[uint32]string{
+	0x1: "GpioNoTolerance1v8",
+	0x3: tolerance1v8,
+}
+
+// padLockValues - bits 1:0 of LockConfig
+var padLockValues = map[uint32]string{
+	0x1: "GpioPadConfigLock",
+	0x3: "GpioPadConfigUnlock",
+}
+
+// outputLockValues - bits 3:2 of LockConfig
+var outputLockValues = map[uint32]string{
+	0x1: "GpioOutputStateLock",
+	0x3: "GpioOutputStateUnlock",
+}
+
+// ConfigUnpack - unpacks the binary GPIO_CONFIG structure of FSP/edk2
+// dw0 : PadMode, HostSoftPadOwn, Direction, OutputState, InterruptConfig and
+//       PowerConfig
+// dw1 : ElectricalConfig, LockConfig, OtherSettings and reserved bits
+// return
+//     GPIO_CONFIG fields
+//     false if the reserved bits are set
+func ConfigUnpack(dw0 uint32, dw1 uint32) (Config, bool) {
+	var cfg Config
+	cfg[padModeField] = dw0 & 0x1f
+	cfg[hostSoftPadOwnField] = (dw0 >> 5) & 0x3
+	cfg[directionField] = (dw0 >> 7) & 0x3f
+	cfg[outputStateField] = (dw0 >> 13) & 0x3
+	cfg[interruptConfigField] = (dw0 >> 15) & 0x1ff
+	cfg[powerConfigField] = dw0 >> 24
+	cfg[electricalConfigField] = dw1 & 0x1ff
+	cfg[lockConfigField] = (dw1 >> 9) & 0xf
+	// OtherSettings in bits 21:13 are not supported
+	return cfg, dw1 >> 22 == 0
+}
+
+// valueNameGet - returns the name of the field value, the zero value keeps the
+// hardware default and has no name
+// table : names of the field values
+// value : field value
+// return false if the value is unknown
+func valueNameGet(table map[uint32]string, value uint32) (string, bool) {
+	if value == 0 {
+		return "", true
+	}
+	name, valid := table[value]
+	return name, valid
+}
+
+// ConfigNamesGet - returns the names of the GPIO_CONFIG values in the same
+// form as in the GPIO_INIT_CONFIG entry, see ConfigEncode()
+// cfg : GPIO_CONFIG fields
+// return
+//     GPIO_CONFIG fields, an empty string for the default value
+//     false if any value is unknown
+func ConfigNamesGet(cfg Config) ([]string, bool) {
+	names := make([]string, len(cfg))
+	var valid bool
+	for i, table := range map[int]map[uint32]string{
+		padModeField:        padModeValues,
+		hostSoftPadOwnField: hostSoftPadOwnValues,
+		directionField:      directionValues,
+		outputStateField:    outputStateValues,
+		powerConfigField:    powerConfigValues,
+	} {
+		if names[i], valid = valueNameGet(table, cfg[i]); !valid {
+			return nil, false
+		}
+	}
+
+	// the combined fields
+	var parts []string
+	join := func(table map[uint32]string, value uint32) bool {
+		name, valid := valueNameGet(table, value)
+		if name != "" {
+			parts = append(parts, name)
+		}
+		return valid
+	}
+
+	route := cfg[interruptConfigField] & 0x1f
+	if route != 0 && route&0x1 == 0 {
+		return nil, false
+	}
+	if route == 0x1 {
+		parts = append(parts, "GpioIntDis")
+	}
+	for bit := uint32(0x2); bit <= 0x10; bit <<= 1 {
+		if route&bit != 0 {
+			parts = append(parts, interruptRouteValues[bit])
+		}
+	}
+	if !join(interruptTrigValues, cfg[interruptConfigField]>>5) {
+		return nil, false
+	}
+	names[interruptConfigField], parts = strings.Join(parts, " | "), nil
+
+	electrical := cfg[electricalConfigField]
+	if electrical>>7 != 0 || !join(terminationValues, electrical&0x1f) ||
+		!join(toleranceValues, (electrical>>5)&0x3) {
+		return nil, false
+	}
+	names[electricalConfigField], parts = strings.Join(parts, " | "), nil
+
+	lock := cfg[lockConfigField]
+	if !join(padLockValues, lock&0x3) || !join(outputLockValues, lock>>2) {
+		return nil, false
+	}
+	names[lockConfigField] = strings.Join(parts, " | ")
+	if lock == 0xf {
+		// GpioPadConfigUnlock | GpioOutputStateUnlock
+		names[lockConfigField] = "GpioPadUnlock"
+	}
+	return names, true
+}
@@ -0,0 +1,59 @@
+package fsp_test
+
+import (
+	"reflect"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/fields/fsp"
+
+func TestConfigUnpack(t *testing.T) {
+	tests := []struct {
+		dw0   uint32
+		dw1   uint32
+		names []string
+	}{
+		// GpioPadModeNative1, GpioHostOwnAcpi, GpioDirNone, GpioOutLow,
+		// GpioIntDis | GpioIntLvlEdgDis, GpioResetDeep, GpioTermWpu20K,
+		// GpioPadConfigLock
+		{0x3 | 0x1<<5 | 0x07<<7 | 0x1<<13 | (0x1|0x5<<5)<<15 | 0x0b<<24, 0x19 | 0x1<<9,
+			[]string{"GpioPadModeNative1", "GpioHostOwnAcpi", "GpioDirNone", "GpioOutLow",
+				"GpioIntDis | GpioIntLvlEdgDis", "GpioResetDeep", "GpioTermWpu20K",
+				"GpioPadConfigLock"}},
+		// GpioPadModeGpio, GpioHostOwnGpio, GpioDirInInv, default output,
+		// GpioIntApic | GpioIntLevel, GpioPlatformReset,
+		// GpioTermNone | GpioTolerance1v8, GpioPadConfigUnlock | GpioOutputStateLock
+		{0x1 | 0x3<<5 | 0x1b<<7 | (0x11|0x1<<5)<<15 | 0x05<<24, 0x01 | 0x3<<5 | (0x3|0x1<<2)<<9,
+			[]string{"GpioPadModeGpio", "GpioHostOwnGpio", "GpioDirInInv", "",
+				"GpioIntApic | GpioIntLevel", "GpioPlatformReset",
+				"GpioTermNone | GpioTolerance1v8",
+				"GpioPadConfigUnlock | GpioOutputStateLock"}},
+		// GpioPadUnlock is the short form of the unlocked pad and output state
+		{0x1, 0x3<<9 | 0x3<<11, []string{"GpioPadModeGpio", "", "", "", "", "", "",
+			"GpioPadUnlock"}},
+		// all fields keep the hardware defaults
+		{0x0, 0x0, []string{"", "", "", "", "", "", "", ""}},
+		// unknown pad mode
+		{0x1f, 0x0, nil},
+		// route without bit 0
+		{0x1 | 0x08<<15, 0x0, nil},
+		// unknown termination
+		{0x1, 0x03, nil},
+	}
+	for _, test := range tests {
+		cfg, valid := fsp.ConfigUnpack(test.dw0, test.dw1)
+		if !valid {
+			t.Errorf("0x%08x 0x%08x: reserved bits", test.dw0, test.dw1)
+			continue
+		}
+		names, valid := fsp.ConfigNamesGet(cfg)
+		if valid != (test.names != nil) || !reflect.DeepEqual(names, test.names) {
+			t.Errorf("0x%08x 0x%08x: got %q, want %q", test.dw0, test.dw1, names,
+				test.names)
+		}
+	}
+
+	if _, valid := fsp.ConfigUnpack(0x1, 1<<22); valid {
+		t.Errorf("reserved bits are not checked")
+	}
+}
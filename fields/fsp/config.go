@@ -0,0 +1,176 @@
+package fsp
+
+import (
+	"fmt"
+	"strings"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// GPIO_CONFIG fields in the GPIO_INIT_CONFIG entry
+const (
+	padModeField = iota
+	hostSoftPadOwnField
+	directionField
+	outputStateField
+	interruptConfigField
+	powerConfigField
+	electricalConfigField
+	lockConfigField
+)
+
+// logicalReset - reset names of the newer edk2 platforms and the corresponding
+// logical reset sources. The platform converts them to the PADRSTCFG values.
+var logicalReset = map[string]uint8{
+	"GpioHostDeepReset": common.RST_DEEP,
+	"GpioPlatformReset": common.RST_PLTRST,
+	"GpioResumeReset":   common.RST_RSMRST,
+	"GpioDswReset":      common.RST_PWROK,
+}
+
+// lockNames - GPIO_LOCK_CONFIG values and the lock bits that they set. The
+// unlock values have no bits, but they make the lock state explicit.
+var lockNames = map[string]uint8{
+	"GpioPadConfigLock":     common.PAD_LOCK_CONFIG,
+	"GpioOutputStateLock":   common.PAD_LOCK_TX,
+	"GpioPadLock":           common.PAD_LOCK_FULL,
+	"GpioPadConfigUnlock":   0,
+	"GpioOutputStateUnlock": 0,
+	"GpioPadUnlock":         0,
+}
+
+// keyGet - returns the key of the table element with the specified name
+// table : table with names of the field values
+// name  : name of the field value
+func keyGet(table map[uint8]string, name string) (uint8, bool) {
+	for key, value := range table {
+		if value == name {
+			return key, true
+		}
+	}
+	return 0, false
+}
+
+// isDefault - returns true if the value keeps the hardware default, e.g.
+// GpioOutDefault or GpioHardwareDefault. Such fields are left zero.
+func isDefault(name string) bool {
+	return name == "" || name == "GpioHardwareDefault" || strings.HasSuffix(name, "Default")
+}
+
+// ConfigFieldsGet - splits the GPIO_INIT_CONFIG entry into the pad name and
+// the GPIO_CONFIG fields
+// line : string with the entry, e.g.
+//        { GPIO_SKL_H_GPP_A12, { GpioPadModeGpio, GpioHostOwnAcpi, ... } },
+// return
+//     pad name, e.g. GPIO_SKL_H_GPP_A12
+//     GPIO_CONFIG fields
+//     error
+func ConfigFieldsGet(line string) (string, []string, error) {
+	if start := strings.Index(line, "//"); start >= 0 {
+		line = line[:start]
+	}
+	if start := strings.Index(line, "/*"); start >= 0 {
+		line = line[:start]
+	}
+	if strings.Count(line, "{") != 2 || strings.Count(line, "}") != 2 {
+		return "", nil, fmt.Errorf("GPIO_INIT_CONFIG entry not found")
+	}
+	var fields []string
+	for _, field := range strings.FieldsFunc(line, func(c rune) bool {
+		return c == '{' || c == '}' || c == ','
+	}) {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) < 2 {
+		return "", nil, fmt.Errorf("GPIO_INIT_CONFIG entry not found")
+	}
+	return fields[0], fields[1:], nil
+}
+
+// ConfigEncode - converts the GPIO_CONFIG fields from the FSP/edk2
+// GPIO_INIT_CONFIG entry into the DW0/DW1 register values. The fields with the
+// default values are left zero.
+// platform : platform-specific encoder interface
+// id       : pad id string
+// fields   : GPIO_CONFIG fields, see ConfigFieldsGet()
+// return
+//     pad configuration
+//     pad lock state
+//     error
+func ConfigEncode(platform common.EncoderSpecific, id string, fields []string) (*common.PadConfig, uint8, error) {
+	pad := &common.PadConfig{Id: id}
+	var dw0, dw1 uint32
+	var lock uint8 = common.PAD_LOCK_DEFAULT
+	invalid := func(value string) error {
+		return fmt.Errorf("%s: invalid GPIO_CONFIG value %s", id, value)
+	}
+	for i, field := range fields {
+		for _, value := range strings.Split(field, "|") {
+			value = strings.TrimSpace(value)
+			if isDefault(value) {
+				continue
+			}
+			key, valid := uint8(0), false
+			switch i {
+			case padModeField:
+				if key, valid = keyGet(padMode, value); valid {
+					dw0 |= uint32(key) << common.PadModeShift
+				}
+			case hostSoftPadOwnField:
+				key, valid = keyGet(hostSoftPadOwn, value)
+				pad.Ownership = key
+			case directionField:
+				if key, valid = keyGet(direction, value); valid {
+					dw0 |= uint32(key>>4) << common.RxInvertShift
+					dw0 |= uint32(key&0x3) << common.RxTxBufDisableShift
+				}
+			case outputStateField:
+				if key, valid = keyGet(outputState, value); valid {
+					dw0 |= uint32(key) & common.TxStateMask
+				}
+			case interruptConfigField:
+				if value == "GpioIntDis" {
+					valid = true
+				} else if key, valid = keyGet(interruptRoute, value); valid {
+					dw0 |= uint32(key) << common.InputRouteNMIShift
+				} else if key, valid = keyGet(interruptTrig, value); valid {
+					dw0 |= uint32(key) << common.RxLevelEdgeConfigurationShift
+				}
+			case powerConfigField:
+				if key, valid = keyGet(powerConfig, value); !valid {
+					if rst, known := logicalReset[value]; known {
+						key, valid = platform.RstSrcEncode(pad.Id, rst)
+					}
+				}
+				dw0 |= uint32(key) << common.PadRstCfgShift
+			case electricalConfigField:
+				if value == tolerance1v8 {
+					dw1 |= common.PadTolMask
+					valid = true
+				} else if value == "GpioNoTolerance1v8" {
+					valid = true
+				} else if key, valid = keyGet(termination, value); valid {
+					dw1 |= uint32(key) << common.TermShift
+				}
+			case lockConfigField:
+				if key, valid = lockNames[value]; valid {
+					lock = lock&common.PAD_LOCK_FULL | key
+					if lock == common.PAD_LOCK_DEFAULT {
+						lock = common.PAD_UNLOCK
+					}
+				}
+			default:
+				// OtherSettings are not supported
+				valid = true
+			}
+			if !valid {
+				return nil, 0, invalid(value)
+			}
+		}
+	}
+	pad.Register(common.PAD_CFG_DW0).ValueSet(dw0)
+	pad.Register(common.PAD_CFG_DW1).ValueSet(dw1)
+	return pad, lock, nil
+}
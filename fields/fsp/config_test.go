@@ -0,0 +1,93 @@
+package fsp_test
+
+import (
+	"reflect"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/fields/fsp"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/snr"
+
+func TestConfigFieldsGet(t *testing.T) {
+	tests := []struct {
+		line   string
+		name   string
+		fields []string
+		valid  bool
+	}{
+		{"  { GPIO_SKL_H_GPP_A12, { GpioPadModeGpio, GpioHostOwnAcpi, GpioDirInInv } },",
+			"GPIO_SKL_H_GPP_A12",
+			[]string{"GpioPadModeGpio", "GpioHostOwnAcpi", "GpioDirInInv"}, true},
+		{"{GPIO_CNL_LP_GPP_C8, {GpioPadModeGpio, GpioHostOwnGpio, GpioDirInInv, " +
+			"GpioOutDefault, GpioIntLevel | GpioIntApic}}, //TOUCHPAD_INT {x}",
+			"GPIO_CNL_LP_GPP_C8",
+			[]string{"GpioPadModeGpio", "GpioHostOwnGpio", "GpioDirInInv",
+				"GpioOutDefault", "GpioIntLevel | GpioIntApic"}, true},
+		{"{GPIO_CNL_LP_GPP_A0, {GpioPadModeNative1}}, /* RCIN# */",
+			"GPIO_CNL_LP_GPP_A0", []string{"GpioPadModeNative1"}, true},
+		{"GPIO_INIT_CONFIG mGpioTable[] = {", "", nil, false},
+		{"{GPIO_CNL_LP_GPP_A0, {}},", "", nil, false},
+		{"//{GPIO_CNL_LP_GPP_A0, {GpioPadModeNative1}},", "", nil, false},
+	}
+	for _, test := range tests {
+		name, fields, err := fsp.ConfigFieldsGet(test.line)
+		if (err == nil) != test.valid {
+			t.Errorf("%s: got error %v", test.line, err)
+			continue
+		}
+		if name != test.name || !reflect.DeepEqual(fields, test.fields) {
+			t.Errorf("%s: got %s %q, want %s %q", test.line, name, fields, test.name,
+				test.fields)
+		}
+	}
+}
+
+func TestConfigEncode(t *testing.T) {
+	tests := []struct {
+		fields    []string
+		dw0       uint32
+		dw1       uint32
+		ownership uint8
+		lock      uint8
+	}{
+		{[]string{"GpioPadModeNative1", "GpioHostOwnAcpi", "GpioDirNone", "GpioOutDefault",
+			"GpioIntDis", "GpioResetDeep", "GpioTermWpu20K"},
+			0x40000700, 0x00003000, common.PAD_OWN_ACPI, common.PAD_LOCK_DEFAULT},
+		{[]string{"GpioPadModeGpio", "GpioHostOwnGpio", "GpioDirInInv", "GpioOutDefault",
+			"GpioIntLevel | GpioIntApic", "GpioPlatformReset", "GpioTermNone"},
+			0x80900100, 0x00000000, common.PAD_OWN_DRIVER, common.PAD_LOCK_DEFAULT},
+		{[]string{"GpioPadModeGpio", "GpioHostOwnAcpi", "GpioDirOut", "GpioOutHigh",
+			"GpioIntLvlEdgDis", "GpioResetNormal", "GpioTermNone | GpioTolerance1v8",
+			"GpioOutputStateLock"},
+			0x84000201, 0x02000000, common.PAD_OWN_ACPI, common.PAD_LOCK_TX},
+		{[]string{"GpioHardwareDefault", "GpioHostOwnDefault", "GpioDirDefault",
+			"GpioOutDefault", "GpioIntDefault", "GpioResetDefault", "GpioTermDefault",
+			"GpioPadConfigUnlock"},
+			0x00000000, 0x00000000, common.PAD_OWN_ACPI, common.PAD_UNLOCK},
+	}
+	for _, test := range tests {
+		cfg, lock, err := fsp.ConfigEncode(snr.PlatformSpecific{}, "GPP_A1", test.fields)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", test.fields, err)
+			continue
+		}
+		dw0 := cfg.Register(common.PAD_CFG_DW0).ValueGet()
+		dw1 := cfg.Register(common.PAD_CFG_DW1).ValueGet()
+		if dw0 != test.dw0 || dw1 != test.dw1 || cfg.Ownership != test.ownership ||
+			lock != test.lock {
+			t.Errorf("%v: got 0x%08x 0x%08x %d %d, want 0x%08x 0x%08x %d %d", test.fields,
+				dw0, dw1, cfg.Ownership, lock, test.dw0, test.dw1, test.ownership, test.lock)
+		}
+	}
+
+	for _, fields := range [][]string{
+		{"GpioPadModeNative9"},
+		{"GpioPadModeGpio", "GpioHostOwnAcpi", "GpioDirSideways"},
+		{"GpioPadModeGpio", "GpioHostOwnAcpi", "GpioDirIn", "GpioOutLow", "GpioIntFoo"},
+	} {
+		if _, _, err := fsp.ConfigEncode(snr.PlatformSpecific{}, "GPP_A1", fields); err == nil {
+			t.Errorf("%v: no error", fields)
+		}
+	}
+}
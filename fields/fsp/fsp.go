@@ -1,6 +1,6 @@
 package fsp
 
-import "../../platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
 
 type FieldMacros struct {}
 
@@ -15,9 +15,9 @@ type field struct {
 }
 
 // generate - wrapper for generating bitfield macros string
+// macro  : macro object
 // fileds : field structure
-func generate(fileds ...*field) {
-	macro := common.GetMacro()
+func generate(macro *common.Macro, fileds ...*field) {
 	for _, field := range fileds {
 		if field.override != nil {
 			// override if necessary
@@ -34,9 +34,76 @@ func generate(fileds ...*field) {
 	}
 }
 
+// Names of the GPIO_CONFIG field values in FSP/edk2. The tables are shared by
+// the bit field macros generator and the GPIO_INIT_CONFIG parser.
+var padMode = map[uint8]string{
+	0: "GpioPadModeGpio",
+	1: "GpioPadModeNative1",
+	2: "GpioPadModeNative2",
+	3: "GpioPadModeNative3",
+	4: "GpioPadModeNative4",
+	5: "GpioPadModeNative5",
+}
+
+var hostSoftPadOwn = map[uint8]string{
+	common.PAD_OWN_ACPI:   "GpioHostOwnAcpi",
+	common.PAD_OWN_DRIVER: "GpioHostOwnGpio",
+}
+
+// direction - RX invert << 4 | RX/TX buffer disable
+var direction = map[uint8]string{
+	0:          "GpioDirInOut",
+	1:          "GpioDirIn",
+	2:          "GpioDirOut",
+	3:          "GpioDirNone",
+	1 << 4 | 0: "GpioDirInInvOut",
+	1 << 4 | 1: "GpioDirInInv",
+}
+
+var outputState = map[uint8]string{
+	0: "GpioOutLow",
+	1: "GpioOutHigh",
+}
+
+// interruptRoute - IOxAPIC << 3 | SCI << 2 | SMI << 1 | NMI
+var interruptRoute = map[uint8]string{
+	1 << 0: "GpioIntNmi",
+	1 << 1: "GpioIntSmi",
+	1 << 2: "GpioIntSci",
+	1 << 3: "GpioIntApic",
+}
+
+var interruptTrig = map[uint8]string{
+	common.TRIG_LEVEL:       "GpioIntLevel",
+	common.TRIG_EDGE_SINGLE: "GpioIntEdge",
+	common.TRIG_OFF:         "GpioIntLvlEdgDis",
+	common.TRIG_EDGE_BOTH:   "GpioIntBothEdge",
+}
+
+// powerConfig - Pad Reset Config (PADRSTCFG) field values
+var powerConfig = map[uint8]string{
+	0: "GpioResetPwrGood",
+	1: "GpioResetDeep",
+	2: "GpioResetNormal",
+	3: "GpioResetResume",
+}
+
+var termination = map[uint8]string{
+	0x0: "GpioTermNone",
+	0x2: "GpioTermWpd5K",
+	0x4: "GpioTermWpd20K",
+	0x9: "GpioTermWpu1K",
+	0xa: "GpioTermWpu5K",
+	0xb: "GpioTermWpu2K",
+	0xc: "GpioTermWpu20K",
+	0xd: "GpioTermWpu1K2K",
+	0xf: "GpioTermNative",
+}
+
+const tolerance1v8 = "GpioTolerance1v8"
+
 // DecodeDW0 - decode value of DW0 register
-func (FieldMacros) DecodeDW0() {
-	macro := common.GetMacro()
+func (FieldMacros) DecodeDW0(macro *common.Macro) {
 	dw0 := macro.Register(common.PAD_CFG_DW0)
 
 	ownershipStatus := func() uint8 {
@@ -44,54 +111,29 @@ func (FieldMacros) DecodeDW0() {
 		return 0
 	}
 
-	generate(
+	generate(macro,
 		&field {
-			configmap : map[uint8]string{
-				0: "GpioPadModeGpio",
-				1: "GpioPadModeNative1",
-				2: "GpioPadModeNative2",
-				3: "GpioPadModeNative3",
-				4: "GpioPadModeNative4",
-				5: "GpioPadModeNative5",
-			},
+			configmap : padMode,
 			value : dw0.GetPadMode(),
 		},
 
 		&field {
-			configmap : map[uint8]string {
-				0: "GpioHostOwnAcpi",
-				1: "GpioHostOwnGpio",
-			},
+			configmap : hostSoftPadOwn,
 			value : ownershipStatus(),
 		},
 
 		&field {
-			configmap : map[uint8]string {
-				0:          "GpioDirInOut",
-				1:          "GpioDirIn",
-				2:          "GpioDirOut",
-				3:          "GpioDirNone",
-				1 << 4 | 0: "GpioDirInInvOut",
-				1 << 4 | 1: "GpioDirInInv",
-			},
-			value : dw0.GetRxInvert() << 4 | dw0.GetRXLevelEdgeConfiguration(),
+			configmap : direction,
+			value : dw0.GetRxInvert() << 4 | dw0.GetGPIORxTxDisableStatus(),
 		},
 
 		&field {
-			configmap : map[uint8]string {
-				0: "GpioOutLow",
-				1: "GpioOutHigh",
-			},
+			configmap : outputState,
 			value : dw0.GetGPIOTXState(),
 		},
 
 		&field {
-			configmap : map[uint8]string {
-				1 << 0: "GpioIntNmi",
-				1 << 1: "GpioIntSmi",
-				1 << 2: "GpioIntSci",
-				1 << 3: "GpioIntApic",
-			},
+			configmap : interruptRoute,
 			override : func(configmap map[uint8]string, value uint8) {
 				mask := dw0.GetGPIOInputRouteIOxAPIC() << 3 |
 							dw0.GetGPIOInputRouteSCI() << 2 |
@@ -101,71 +143,64 @@ func (FieldMacros) DecodeDW0() {
 					macro.Add("GpioIntDis | ")
 					return
 				}
-				for bit, fieldmacro := range configmap {
+				for bit := uint8(1); bit <= 1 << 3; bit <<= 1 {
 					if mask & bit != 0 {
-						macro.Add(fieldmacro).Add(" | ")
+						macro.Add(configmap[bit]).Add(" | ")
 					}
 				}
 			},
 		},
 
 		&field {
-			configmap : map[uint8]string {
-				0: "GpioIntLevel",
-				1: "GpioIntEdge",
-				2: "GpioIntLvlEdgDis",
-				3: "GpioIntBothEdge",
-			},
-			value : dw0.GetResetConfig(),
+			configmap : interruptTrig,
+			value : dw0.GetRXLevelEdgeConfiguration(),
 		},
 
 		&field {
-			configmap : map[uint8]string {
-				0: "GpioResetPwrGood",
-				1: "GpioResetDeep",
-				2: "GpioResetNormal",
-				3: "GpioResetResume",
-			},
+			configmap : powerConfig,
 			value : dw0.GetResetConfig(),
 		},
 	)
 }
 
 // DecodeDW1 - decode value of DW1 register
-func (FieldMacros) DecodeDW1() {
-	macro := common.GetMacro()
+func (FieldMacros) DecodeDW1(macro *common.Macro) {
 	dw1 := macro.Register(common.PAD_CFG_DW1)
-	generate(
+	generate(macro,
 		&field {
 			override : func(configmap map[uint8]string, value uint8) {
 				if dw1.GetPadTol() != 0 {
-					macro.Add("GpioTolerance1v8 | ")
+					macro.Add(tolerance1v8).Add(" | ")
 				}
 			},
 		},
 
 		&field {
-			configmap : map[uint8]string {
-				0x0: "GpioTermNone",
-				0x2: "GpioTermWpd5K",
-				0x4: "GpioTermWpd20K",
-				0x9: "GpioTermWpu1K",
-				0xa: "GpioTermWpu5K",
-				0xb: "GpioTermWpu2K",
-				0xc: "GpioTermWpu20K",
-				0xd: "GpioTermWpu1K2K",
-				0xf: "GpioTermNative",
-			},
+			configmap : termination,
 			value : dw1.GetTermination(),
 		},
 	)
 }
 
+// DecodeDW2 - decode value of DW2 register
+func (FieldMacros) DecodeDW2(macro *common.Macro) {
+	// GPIO_CONFIG has no debounce settings, the fields are ignored
+}
+
+// lockConfig - GPIO_LOCK_CONFIG values for the pad lock states. If the lock
+// registers are not in the dump, the pad configuration is locked.
+var lockConfig = map[uint8]string{
+	common.PAD_LOCK_DEFAULT: "GpioPadConfigLock",
+	common.PAD_LOCK_CONFIG:  "GpioPadConfigLock | GpioOutputStateUnlock",
+	common.PAD_LOCK_TX:      "GpioPadConfigUnlock | GpioOutputStateLock",
+	common.PAD_LOCK_FULL:    "GpioPadLock",
+	common.PAD_UNLOCK:       "GpioPadUnlock",
+}
+
 // GenerateString - generates the entire string of bitfield macros.
-func (bitfields FieldMacros) GenerateString() {
-	macro := common.GetMacro()
+func (bitfields FieldMacros) GenerateString(macro *common.Macro) {
 	macro.Add("{ GPIO_SKL_H_").Id().Add(", { ")
-	bitfields.DecodeDW0()
-	bitfields.DecodeDW1()
-	macro.Add(" GpioPadConfigLock } },") // TODO: configure GpioPadConfigLock
+	bitfields.DecodeDW0(macro)
+	bitfields.DecodeDW1(macro)
+	macro.Add(" ").Add(lockConfig[macro.LockGet()]).Add(" } },")
 }
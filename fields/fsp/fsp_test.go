@@ -0,0 +1,83 @@
+package fsp_test
+
+import (
+	"strings"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/fields/fsp"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/snr"
+
+// fspMacroGet - generates the FSP-style macro of the Sunrise Point pad
+func fspMacroGet(t *testing.T, id string, dw0 uint32, dw1 uint32) string {
+	opts := &config.Options{}
+	opts.PlatformSet("snr")
+	if opts.FldStyleSet("fsp") != 0 {
+		t.Fatalf("fsp style is not supported")
+	}
+	return snr.PlatformSpecific{}.GenMacro(id, dw0, dw1, 0, common.PAD_OWN_ACPI,
+		common.PAD_LOCK_DEFAULT, opts)
+}
+
+// TestDirectionTrigger - the direction is taken from the RX/TX buffer disable
+// and RX invert fields, the trigger from RXEVCFG. They were taken from RXEVCFG
+// and PADRSTCFG, so 0x44000702 got GpioDirOut and GpioIntEdge.
+func TestDirectionTrigger(t *testing.T) {
+	tests := []struct {
+		dw0       uint32
+		direction string
+		trigger   string
+	}{
+		{0x44000702, "GpioDirNone", "GpioIntLvlEdgDis"},
+		{0x44000502, "GpioDirIn", "GpioIntLvlEdgDis"},
+		{0x84000201, "GpioDirOut", "GpioIntLvlEdgDis"},
+		{0x40000000, "GpioDirInOut", "GpioIntLevel"},
+		{0x80880102, "GpioDirInInv", "GpioIntSci | GpioIntLevel"},
+		{0x82900102, "GpioDirInInv", "GpioIntApic | GpioIntEdge"},
+		{0x86000100, "GpioDirIn", "GpioIntDis | GpioIntBothEdge"},
+	}
+	for _, test := range tests {
+		macro := fspMacroGet(t, "GPP_A0", test.dw0, 0)
+		if !strings.Contains(macro, " "+test.direction+",") {
+			t.Errorf("0x%08x: %s has no %s", test.dw0, macro, test.direction)
+		}
+		if !strings.Contains(macro, " "+test.trigger+",") {
+			t.Errorf("0x%08x: %s has no %s", test.dw0, macro, test.trigger)
+		}
+	}
+}
+
+// TestMacroRoundTrip - the GPIO_INIT_CONFIG entry generated for the pad is
+// encoded into the same DW0/DW1 values
+func TestMacroRoundTrip(t *testing.T) {
+	tests := []struct {
+		dw0 uint32
+		dw1 uint32
+	}{
+		{0x44000700, 0x00003000},
+		{0x80880100, 0x00000000},
+		{0x84000201, 0x02000000},
+		{0x82900100, 0x00001000},
+	}
+	for _, test := range tests {
+		macro := fspMacroGet(t, "GPP_A1", test.dw0, test.dw1)
+		_, fields, err := fsp.ConfigFieldsGet(macro)
+		if err != nil {
+			t.Errorf("%s: %v", macro, err)
+			continue
+		}
+		cfg, _, err := fsp.ConfigEncode(snr.PlatformSpecific{}, "GPP_A1", fields)
+		if err != nil {
+			t.Errorf("%s: %v", macro, err)
+			continue
+		}
+		dw0 := cfg.Register(common.PAD_CFG_DW0).ValueGet()
+		dw1 := cfg.Register(common.PAD_CFG_DW1).ValueGet()
+		if dw0 != test.dw0 || dw1 != test.dw1 {
+			t.Errorf("%s: got 0x%08x 0x%08x, want 0x%08x 0x%08x", macro, dw0, dw1,
+				test.dw0, test.dw1)
+		}
+	}
+}
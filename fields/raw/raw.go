@@ -1,28 +1,40 @@
 package raw
 
 import "fmt"
-import "../../platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
 
 type FieldMacros struct {}
 
-func (FieldMacros) DecodeDW0() {
-	macro := common.GetMacro()
+func (FieldMacros) DecodeDW0(macro *common.Macro) {
 	// Do not decode, print as is.
 	macro.Add(fmt.Sprintf("0x%0.8x", macro.Register(common.PAD_CFG_DW0).ValueGet()))
 }
 
-func (FieldMacros) DecodeDW1() {
-	macro := common.GetMacro()
+func (FieldMacros) DecodeDW1(macro *common.Macro) {
 	// Do not decode, print as is.
 	macro.Add(fmt.Sprintf("0x%0.8x", macro.Register(common.PAD_CFG_DW1).ValueGet()))
 }
 
+func (FieldMacros) DecodeDW2(macro *common.Macro) {
+	// Do not decode, print as is.
+	macro.Add(fmt.Sprintf("0x%0.8x", macro.Register(common.PAD_CFG_DW2).ValueGet()))
+}
+
 // GenerateString - generates the entire string of bitfield macros.
-func (bitfields FieldMacros) GenerateString() {
-	macro := common.GetMacro()
-	macro.Add("_PAD_CFG_STRUCT(").Id().Add(", ")
-	bitfields.DecodeDW0()
+func (bitfields FieldMacros) GenerateString(macro *common.Macro) {
+	if macro.Register(common.PAD_CFG_DW2).ValueGet() == 0 {
+		macro.Add("_PAD_CFG_STRUCT(").Id().Add(", ")
+		bitfields.DecodeDW0(macro)
+		macro.Add(", ")
+		bitfields.DecodeDW1(macro)
+		macro.Add("),")
+		return
+	}
+	macro.Add("_PAD_CFG_STRUCT_3(").Id().Add(", ")
+	bitfields.DecodeDW0(macro)
+	macro.Add(", ")
+	bitfields.DecodeDW1(macro)
 	macro.Add(", ")
-	bitfields.DecodeDW1()
+	bitfields.DecodeDW2(macro)
 	macro.Add("),")
 }
@@ -0,0 +1,255 @@
+package sbl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/fields/fsp"
+
+type FieldMacros struct {}
+
+// GPIO_CFG_DATA_DW0 fields, they contain the GPIO_CONFIG values of FSP/edk2
+const (
+	padModeShift         = 0
+	hostSoftPadOwnShift  = 5
+	directionShift       = 7
+	outputStateShift     = 13
+	interruptConfigShift = 15
+	powerConfigShift     = 24
+)
+
+// GPIO_CFG_DATA_DW1 fields, the pad ID is the group index and the pad number
+// in the group
+const (
+	electricalConfigShift = 0
+	lockConfigShift       = 7
+	padNumShift           = 16
+	grpIdxShift           = 24
+	hideShift             = 31
+)
+
+// dw1ReservedMask - reserved bits 15:13 and 30:29 of GPIO_CFG_DATA_DW1
+const dw1ReservedMask = 0x7 << 13 | 0x3 << 29
+
+// GPIO_CONFIG values for the register fields. Zero keeps the hardware default,
+// it is also used for the values that GPIO_CONFIG can not express.
+var hostSoftPadOwn = map[uint8]uint32{
+	common.PAD_OWN_ACPI:   0x1, // GpioHostOwnAcpi
+	common.PAD_OWN_DRIVER: 0x3, // GpioHostOwnGpio
+}
+
+// direction - RX invert << 4 | RX/TX buffer disable
+var direction = map[uint8]uint32{
+	0:          0x09, // GpioDirInOut
+	1:          0x0b, // GpioDirIn
+	2:          0x05, // GpioDirOut
+	3:          0x07, // GpioDirNone
+	1 << 4 | 0: 0x19, // GpioDirInInvOut
+	1 << 4 | 1: 0x1b, // GpioDirInInv
+}
+
+var outputState = map[uint8]uint32{
+	0: 0x1, // GpioOutLow
+	1: 0x3, // GpioOutHigh
+}
+
+// interruptRoute - IOxAPIC << 3 | SCI << 2 | SMI << 1 | NMI
+var interruptRoute = map[uint8]uint32{
+	1 << 0: 0x03, // GpioIntNmi
+	1 << 1: 0x05, // GpioIntSmi
+	1 << 2: 0x09, // GpioIntSci
+	1 << 3: 0x11, // GpioIntApic
+}
+
+const interruptDisable = 0x01 // GpioIntDis
+
+var interruptTrig = map[uint8]uint32{
+	common.TRIG_LEVEL:       0x1 << 5, // GpioIntLevel
+	common.TRIG_EDGE_SINGLE: 0x3 << 5, // GpioIntEdge
+	common.TRIG_OFF:         0x5 << 5, // GpioIntLvlEdgDis
+	common.TRIG_EDGE_BOTH:   0x7 << 5, // GpioIntBothEdge
+}
+
+// powerConfig - Pad Reset Config (PADRSTCFG) field values
+var powerConfig = map[uint8]uint32{
+	0: 0x09, // GpioResetPwrGood
+	1: 0x0b, // GpioResetDeep
+	2: 0x0d, // GpioResetNormal
+	3: 0x0f, // GpioResetResume
+}
+
+var termination = map[uint8]uint32{
+	0x0: 0x01, // GpioTermNone
+	0x2: 0x05, // GpioTermWpd5K
+	0x4: 0x09, // GpioTermWpd20K
+	0x9: 0x13, // GpioTermWpu1K
+	0xa: 0x15, // GpioTermWpu5K
+	0xb: 0x17, // GpioTermWpu2K
+	0xc: 0x19, // GpioTermWpu20K
+	0xd: 0x1b, // GpioTermWpu1K2K
+	0xf: 0x1f, // GpioTermNative
+}
+
+const tolerance1v8 = 0x3 << 5 // GpioTolerance1v8
+
+// lockConfig - GPIO_LOCK_CONFIG values for the pad lock states, the same as in
+// the FSP style
+var lockConfig = map[uint8]uint32{
+	common.PAD_LOCK_DEFAULT: 0x1,       // GpioPadConfigLock
+	common.PAD_LOCK_CONFIG:  0x1 | 0xc, // GpioPadConfigLock | GpioOutputStateUnlock
+	common.PAD_LOCK_TX:      0x3 | 0x4, // GpioPadConfigUnlock | GpioOutputStateLock
+	common.PAD_LOCK_FULL:    0x5,       // GpioPadLock
+	common.PAD_UNLOCK:       0xf,       // GpioPadUnlock
+}
+
+// groups - indices of the GPIO groups in the SBL pad ID. Sunrise Point uses the
+// group order of the -H PCH, as the FSP style does. Apollo Lake pads are
+// numbered across the communities and have no group.
+var groups = map[uint8]map[string]uint32{
+	config.SunriseType: {
+		"GPP_A": 0x0,
+		"GPP_B": 0x1,
+		"GPP_C": 0x2,
+		"GPP_D": 0x3,
+		"GPP_E": 0x4,
+		"GPP_F": 0x5,
+		"GPP_G": 0x6,
+		"GPP_H": 0x7,
+		"GPP_I": 0x8,
+		"GPD":   0x9,
+	},
+	config.ApolloType: {
+		"GPIO_": 0x0,
+	},
+}
+
+// IsPlatformSupported - returns true if the pad IDs of the platform are known
+// opts : converter settings with the platform
+func IsPlatformSupported(opts *config.Options) bool {
+	_, valid := groups[opts.PlatformGet()]
+	return valid
+}
+
+// padIdGet - returns the SBL pad ID and the name of the configuration item
+// macro : macro object with the pad id, e.g. GPP_A1 or GPIO_39
+// return
+//     item name, e.g. GPP_A01 or GPIO_39
+//     group index
+//     pad number in the group
+//     false if the pad has no SBL pad ID
+func padIdGet(macro *common.Macro) (string, uint32, uint32, bool) {
+	id := macro.PadIdGet()
+	start := strings.IndexFunc(id, unicode.IsDigit)
+	if start <= 0 {
+		return "", 0, 0, false
+	}
+	group, valid := groups[macro.Options.PlatformGet()][id[:start]]
+	number, err := strconv.ParseUint(id[start:], 10, 8)
+	if !valid || err != nil {
+		return "", 0, 0, false
+	}
+	if macro.Options.IsPlatformApollo() {
+		return id, group, uint32(number), true
+	}
+	return fmt.Sprintf("%s%02d", id[:start], number), group, uint32(number), true
+}
+
+// PadNameGet - returns the pad name for the SBL pad ID
+// opts   : converter settings with the platform
+// group  : group index
+// number : pad number in the group
+// return false if the pad ID is unknown
+func PadNameGet(opts *config.Options, group uint32, number uint32) (string, bool) {
+	for name, index := range groups[opts.PlatformGet()] {
+		if index == group {
+			return fmt.Sprintf("%s%d", name, number), true
+		}
+	}
+	return "", false
+}
+
+// ItemUnpack - unpacks the GPIO_CFG_DATA item from the binary configuration
+// data of Slim Bootloader. DW0 has the same layout as the binary GPIO_CONFIG
+// structure of FSP.
+// dw0 : GPIO_CFG_DATA_DW0
+// dw1 : GPIO_CFG_DATA_DW1
+// return
+//     GPIO_CONFIG fields
+//     group index
+//     pad number in the group
+//     true if the item is hidden and should be skipped
+//     false if the reserved bits are set
+func ItemUnpack(dw0 uint32, dw1 uint32) (fsp.Config, uint32, uint32, bool, bool) {
+	electrical := (dw1 >> electricalConfigShift) & 0x7f
+	lock := (dw1 >> lockConfigShift) & 0xf
+	cfg, _ := fsp.ConfigUnpack(dw0, electrical | lock << 9)
+	return cfg, (dw1 >> grpIdxShift) & 0x1f, (dw1 >> padNumShift) & 0xff,
+		dw1 >> hideShift != 0, dw1 & dw1ReservedMask == 0
+}
+
+// DecodeDW0 - decode value of DW0 register into GPIO_CFG_DATA_DW0
+func (FieldMacros) DecodeDW0(macro *common.Macro) {
+	dw0 := macro.Register(common.PAD_CFG_DW0)
+	var ownership uint8 = common.PAD_OWN_ACPI
+	if macro.IsOwnershipDriver() {
+		ownership = common.PAD_OWN_DRIVER
+	}
+	route := dw0.GetGPIOInputRouteIOxAPIC() << 3 | dw0.GetGPIOInputRouteSCI() << 2 |
+		dw0.GetGPIOInputRouteSMI() << 1 | dw0.GetGPIOInputRouteNMI()
+	var interrupt uint32 = interruptDisable
+	for bit := uint8(1); bit <= 1 << 3; bit <<= 1 {
+		if route & bit != 0 {
+			interrupt |= interruptRoute[bit]
+		}
+	}
+	interrupt |= interruptTrig[dw0.GetRXLevelEdgeConfiguration()]
+
+	value := (uint32(dw0.GetPadMode()) << 1 | 1) << padModeShift |
+		hostSoftPadOwn[ownership] << hostSoftPadOwnShift |
+		direction[dw0.GetRxInvert() << 4 | dw0.GetGPIORxTxDisableStatus()] << directionShift |
+		outputState[dw0.GetGPIOTXState()] << outputStateShift |
+		interrupt << interruptConfigShift |
+		powerConfig[dw0.GetResetConfig()] << powerConfigShift
+	macro.Add(fmt.Sprintf("0x%08X", value))
+}
+
+// DecodeDW1 - decode value of DW1 register into GPIO_CFG_DATA_DW1 with the
+// pad ID
+func (FieldMacros) DecodeDW1(macro *common.Macro) {
+	dw1 := macro.Register(common.PAD_CFG_DW1)
+	electrical := termination[dw1.GetTermination()]
+	if dw1.GetPadTol() != 0 {
+		electrical |= tolerance1v8
+	}
+	_, group, number, _ := padIdGet(macro)
+	value := electrical << electricalConfigShift |
+		lockConfig[macro.LockGet()] << lockConfigShift |
+		number << padNumShift |
+		group << grpIdxShift
+	macro.Add(fmt.Sprintf("0x%08X", value))
+}
+
+// DecodeDW2 - decode value of DW2 register
+func (FieldMacros) DecodeDW2(macro *common.Macro) {
+	// GPIO_CFG_DATA has no debounce settings, the fields are ignored
+}
+
+// GenerateString - generates the GPIO_CFG_DATA item of the pad, e.g.
+// - !expand { GPIO_TMPL : [ GPP_A01, 0x0F04C0A5, 0x00010081 ] }
+func (bitfields FieldMacros) GenerateString(macro *common.Macro) {
+	name, _, _, valid := padIdGet(macro)
+	if !valid {
+		macro.Add("# ").Id().Add(" - no SBL pad ID")
+		return
+	}
+	macro.Add("- !expand { GPIO_TMPL : [ ").Add(name).Add(", ")
+	bitfields.DecodeDW0(macro)
+	macro.Add(", ")
+	bitfields.DecodeDW1(macro)
+	macro.Add(" ] }")
+}
@@ -0,0 +1,144 @@
+package sbl_test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/fields/fsp"
+import "review.coreboot.org/coreboot.git/util/intelp2m/fields/sbl"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/apl"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/snr"
+
+// optsGet - returns the settings for the SBL style
+// platform : platform name
+func optsGet(t *testing.T, platform string) *config.Options {
+	opts := &config.Options{}
+	if opts.PlatformSet(platform) != 0 || opts.FldStyleSet("sbl") != 0 {
+		t.Fatalf("invalid settings: %s, sbl", platform)
+	}
+	return opts
+}
+
+func TestGenerateString(t *testing.T) {
+	tests := []struct {
+		id   string
+		dw0  uint32
+		dw1  uint32
+		lock uint8
+		item string
+	}{
+		{"GPP_A1", 0x44000702, 0x00003000, common.PAD_LOCK_DEFAULT,
+			"- !expand { GPIO_TMPL : [ GPP_A01, 0x0B50A3A3, 0x00010099 ] }"},
+		{"GPP_B12", 0x84000201, 0x00000000, common.PAD_LOCK_TX,
+			"- !expand { GPIO_TMPL : [ GPP_B12, 0x0D50E2A1, 0x010C0381 ] }"},
+		{"GPD7", 0x04000201, 0x02000000, common.PAD_UNLOCK,
+			"- !expand { GPIO_TMPL : [ GPD07, 0x0950E2A1, 0x090707E1 ] }"},
+	}
+	opts := optsGet(t, "snr")
+	for _, test := range tests {
+		item := snr.PlatformSpecific{}.GenMacro(test.id, test.dw0, test.dw1, 0,
+			common.PAD_OWN_ACPI, test.lock, opts)
+		if item != test.item {
+			t.Errorf("%s: got %s, want %s", test.id, item, test.item)
+		}
+	}
+}
+
+func TestPadId(t *testing.T) {
+	opts := optsGet(t, "apl")
+	tests := []struct {
+		id   string
+		item string
+	}{
+		{"GPIO_39", "- !expand { GPIO_TMPL : [ GPIO_39, 0x0B50A3A3, 0x00270099 ] }"},
+		{"TCK", "# TCK - no SBL pad ID"},
+	}
+	for _, test := range tests {
+		item := apl.PlatformSpecific{}.GenMacro(test.id, 0x44000702, 0x00003000, 0,
+			common.PAD_OWN_ACPI, common.PAD_LOCK_DEFAULT, opts)
+		if item != test.item {
+			t.Errorf("%s: got %s, want %s", test.id, item, test.item)
+		}
+	}
+
+	for platform, supported := range map[string]bool{
+		"snr": true, "apl": true, "cnl": false,
+	} {
+		if sbl.IsPlatformSupported(optsGet(t, platform)) != supported {
+			t.Errorf("%s: supported is not %v", platform, supported)
+		}
+	}
+}
+
+// fieldsNormalize - sorts the parts of the combined GPIO_CONFIG fields
+func fieldsNormalize(fields []string) string {
+	var normalized []string
+	for _, field := range fields {
+		parts := strings.Split(field, "|")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		sort.Strings(parts)
+		normalized = append(normalized, strings.Join(parts, " | "))
+	}
+	return strings.Join(normalized, ", ")
+}
+
+// TestItemUnpack - the GPIO_CFG_DATA item generated for the pad is unpacked
+// into the same GPIO_CONFIG fields as in the FSP-style macro
+func TestItemUnpack(t *testing.T) {
+	tests := []struct {
+		dw0  uint32
+		dw1  uint32
+		lock uint8
+	}{
+		{0x44000702, 0x00003000, common.PAD_LOCK_DEFAULT},
+		{0x84000201, 0x00000000, common.PAD_LOCK_TX},
+		{0x04000201, 0x02000000, common.PAD_UNLOCK},
+		{0x80880102, 0x00000000, common.PAD_LOCK_DEFAULT},
+	}
+	opts := optsGet(t, "snr")
+	fspOpts := &config.Options{}
+	fspOpts.PlatformSet("snr")
+	fspOpts.FldStyleSet("fsp")
+	for _, test := range tests {
+		item := snr.PlatformSpecific{}.GenMacro("GPP_B12", test.dw0, test.dw1, 0,
+			common.PAD_OWN_ACPI, test.lock, opts)
+		var dw0, dw1 uint32
+		if _, err := fmt.Sscanf(item, "- !expand { GPIO_TMPL : [ GPP_B12, 0x%x, 0x%x",
+			&dw0, &dw1); err != nil {
+			t.Errorf("%s: %v", item, err)
+			continue
+		}
+		cfg, group, number, hidden, valid := sbl.ItemUnpack(dw0, dw1)
+		if group != 1 || number != 12 || hidden || !valid {
+			t.Errorf("%s: got group %d, number %d, hidden %v, valid %v", item, group,
+				number, hidden, valid)
+		}
+		names, valid := fsp.ConfigNamesGet(cfg)
+		if !valid {
+			t.Errorf("%s: unknown GPIO_CONFIG values", item)
+			continue
+		}
+
+		macro := snr.PlatformSpecific{}.GenMacro("GPP_B12", test.dw0, test.dw1, 0,
+			common.PAD_OWN_ACPI, test.lock, fspOpts)
+		_, fields, err := fsp.ConfigFieldsGet(macro)
+		if err != nil {
+			t.Errorf("%s: %v", macro, err)
+			continue
+		}
+		if fieldsNormalize(names) != fieldsNormalize(fields) {
+			t.Errorf("%s: got %q, want %q", item, names, fields)
+		}
+	}
+
+	if _, _, _, hidden, _ := sbl.ItemUnpack(0x0B50A3A3, 0x810C0099); !hidden {
+		t.Errorf("hidden item is not detected")
+	}
+}
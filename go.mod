@@ -0,0 +1,3 @@
+module review.coreboot.org/coreboot.git/util/intelp2m
+
+go 1.16
@@ -2,32 +2,332 @@ package main
 
 import "flag"
 import "fmt"
+import "io"
 import "os"
+import "path/filepath"
+import "strconv"
 
-import "./parser"
-import "./config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/parser"
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
 
 // generateOutputFile - generates include file
-// parser            : parser data structure
-func generateOutputFile(parser *parser.ParserData) (err error) {
+// parser : parser data structure
+// w      : writer for the generated file
+// opts   : converter settings
+func generateOutputFile(parser *parser.ParserData, w io.Writer, opts *config.Options) (err error) {
+	if opts.IsJsonFormat() {
+		return parser.PadMapJsonFprint(w)
+	}
+	if opts.IsAslFormat() {
+		return parser.PadMapAslFprint(w)
+	}
+	if opts.IsSblStyleMacro() {
+		return parser.SblCfgDataFprint(w)
+	}
+	return parser.GpioHFprint(w)
+}
+
+// stdioName - file name for stdin and stdout, e.g. -file - -o -
+const stdioName = "-"
+
+// inputOpen - opens the input file, stdin for "-"
+// name : path to the file
+func inputOpen(name string) (*os.File, error) {
+	if name == stdioName {
+		return os.Stdin, nil
+	}
+	return os.Open(name)
+}
+
+// outputCreate - creates the generated file and its directory, stdout for "-"
+// name : path to the file
+func outputCreate(name string) (*os.File, error) {
+	if name == stdioName {
+		return os.Stdout, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(name), os.ModePerm); err != nil {
+		return nil, err
+	}
+	return os.Create(name)
+}
+
+// parseFile - parses the file with the pad configuration
+// name : path to the file, stdin for "-"
+// opts : converter settings
+// return the parser data or nil if the file can not be parsed
+func parseFile(name string, opts *config.Options) *parser.ParserData {
+	file, err := inputOpen(name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: file %s was not found!\n", name)
+		return nil
+	}
+	defer file.Close()
+	// the parser messages go to stderr, stdout is left for the command output
+	data := parser.NewParserData(opts, os.Stderr)
+	if err := data.Parse(file); err != nil {
+		// the notes found before the error, e.g. the GPIO table candidates
+		data.DiagnosticsFprint(os.Stderr, name)
+		fmt.Fprintf(os.Stderr, "Error: %s: %v\n", name, err)
+		return nil
+	}
+	if !diagnosticsCheck(data, os.Stderr, name, opts) {
+		return nil
+	}
+	return data
+}
+
+// diagnosticsCheck - prints the problems found in the input file to stderr
+// data : parser data
+// msg  : writer for the messages
+// name : name of the input file
+// opts : converter settings
+// return false if there are warnings or errors in the strict mode
+func diagnosticsCheck(data *parser.ParserData, msg io.Writer, name string,
+		opts *config.Options) bool {
+	problems := data.DiagnosticsFprint(os.Stderr, name)
+	if problems != 0 && opts.IsStrict() {
+		fmt.Fprintf(msg, "Error: %d problems found in %s (-strict)!\n", problems, name)
+		return false
+	}
+	return true
+}
+
+// diffCommand - compares the pad configurations from two inteltool logs
+// args : paths to the first and second inteltool log files
+// opts : converter settings
+// return exit status
+func diffCommand(args []string, opts *config.Options) int {
+	if len(args) != 2 {
+		fmt.Printf("Error! Usage: intelp2m [options] diff <first.log> <second.log>\n")
+		return 1
+	}
+	var dumps [2]*parser.ParserData
+	for i, name := range args {
+		if dumps[i] = parseFile(name, opts); dumps[i] == nil {
+			return 1
+		}
+	}
+	if dumps[0].PlatformGet() != dumps[1].PlatformGet() {
+		fmt.Printf("Warning: the logs are from different platforms: %s and %s\n",
+			dumps[0].PlatformGet(), dumps[1].PlatformGet())
+	}
+	fmt.Printf("--- %s\n+++ %s\n", args[0], args[1])
+	if parser.PadMapDiff(os.Stdout, dumps[0], dumps[1]) != 0 {
+		return 1
+	}
+	return 0
+}
+
+// compareCommand - compares the pad configuration table from the board gpio.h
+// with the inteltool log from the same board
+// args : paths to the gpio.h and inteltool log files
+// opts : converter settings
+// return exit status
+func compareCommand(args []string, opts *config.Options) int {
+	if len(args) != 2 {
+		fmt.Printf("Error! Usage: intelp2m [options] compare <gpio.h> <inteltool.log>\n")
+		return 1
+	}
+	tableOpts, dumpOpts := *opts, *opts
+	tableOpts.TemplateSet(config.TempGpioh)
+	dumpOpts.TemplateSet(config.TempInteltool)
+	dump := parseFile(args[1], &dumpOpts)
+	if dump == nil {
+		return 1
+	}
+	// gpio.h does not contain the hardware, use the platform of the log
+	tableOpts.PlatformSet(dump.PlatformGet())
+	table := parseFile(args[0], &tableOpts)
+	if table == nil {
+		return 1
+	}
+	fmt.Printf("--- %s\n+++ %s\n", args[1], args[0])
+	if problems := parser.PadMapCompare(os.Stdout, table, dump); problems != 0 {
+		fmt.Printf("%d problems found!\n", problems)
+		return 1
+	}
+	return 0
+}
 
-	config.OutputGenFile.WriteString(`/* SPDX-License-Identifier: GPL-2.0-only */
+// decodeCommand - decodes the configuration registers of a single pad
+// args : pad ID and DW0, DW1 (DW2 for Tiger Lake and newer) register values
+// opts : converter settings
+// return exit status
+func decodeCommand(args []string, opts *config.Options) int {
+	if len(args) < 3 || len(args) > 4 {
+		fmt.Printf("Error! Usage: intelp2m [options] decode <pad> <dw0> <dw1> [<dw2>]\n")
+		return 1
+	}
+	var regs [common.MAX_DW_NUM]uint32
+	for i, arg := range args[1:] {
+		value, err := strconv.ParseUint(arg, 0, 32)
+		if err != nil {
+			fmt.Printf("Error! Invalid DW%d register value %s!\n", i, arg)
+			return 1
+		}
+		regs[i] = uint32(value)
+	}
+	data := parser.NewParserData(opts, nil)
+	if err := data.PadDecodeFprint(os.Stdout, args[0], regs, common.PAD_OWN_ACPI); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
 
-#ifndef CFG_GPIO_H
-#define CFG_GPIO_H
+// encodeCommand - encodes the pad configuration macro or the named bit fields
+// into the register values
+// args : macro or pad ID and bit fields in the <name>=<value> format
+// opts : converter settings
+// return exit status
+func encodeCommand(args []string, opts *config.Options) int {
+	if len(args) < 1 {
+		fmt.Printf("Error! Usage: intelp2m [options] encode <macro>|<pad> <name>=<value>...\n")
+		return 1
+	}
+	data := parser.NewParserData(opts, nil)
+	if err := data.PadEncodeFprint(os.Stdout, args); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
 
-#include <gpio.h>
+// lintCommand - checks the pad configuration from the inteltool log or gpio.h
+// with the lint rules
+// args : path to the file, the catalogue of the rules is printed without it
+// opts : converter settings
+// return exit status
+func lintCommand(args []string, opts *config.Options) int {
+	if len(args) != 1 {
+		fmt.Printf("Usage: intelp2m [options] lint <file>\nRules:\n")
+		parser.LintRulesFprint(os.Stdout)
+		return 1
+	}
+	data := parseFile(args[0], opts)
+	if data == nil {
+		return 1
+	}
+	if data.PadMapLint(os.Stdout) != 0 {
+		return 1
+	}
+	return 0
+}
 
-/* Pad configuration was generated automatically using intelp2m utility */
-static const struct pad_config gpio_table[] = {
-`)
-	// Add the pads map
-	parser.PadMapFprint()
-	config.OutputGenFile.WriteString(`};
+// variantBaseboardDir - directory of the baseboard gpio.c, the variant logs can
+// not have this name
+const variantBaseboardDir = "baseboard"
+
+// variantsDirGet - returns the directory for the generated files of the
+// variants command: generate/variants or the path from the -o option
+func variantsDirGet() string {
+	outdir := filepath.Join("generate", "variants")
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "o" {
+			outdir = f.Value.String()
+		}
+	})
+	return outdir
+}
 
-#endif /* CFG_GPIO_H */
-`)
-	return nil
+// variantFileCreate - creates gpio.c of the baseboard or the variant
+// dir    : directory of the baseboard or the variant
+// name   : name of the inteltool log, it is printed in the messages
+// fprint : prints the file content
+// return false if the file can not be generated
+func variantFileCreate(dir string, name string, fprint func(w io.Writer) error) bool {
+	output := filepath.Join(dir, "gpio.c")
+	file, err := outputCreate(output)
+	if err != nil {
+		fmt.Printf("Error: unable to generate %s: %v\n", output, err)
+		return false
+	}
+	defer file.Close()
+	if err := fprint(file); err != nil {
+		fmt.Printf("Error: %s: %v\n", name, err)
+		return false
+	}
+	return true
+}
+
+// variantsCommand - generates gpio.c with the baseboard pad configuration table
+// and gpio.c with the override table for each variant. The override table
+// contains only the pads that differ from the baseboard.
+// args : paths to the baseboard and variant inteltool logs
+// opts : converter settings
+// return exit status
+func variantsCommand(args []string, opts *config.Options) int {
+	if len(args) < 2 {
+		fmt.Printf("Error! Usage: intelp2m [options] variants <baseboard.log> <variant.log>...\n")
+		return 1
+	}
+	if opts.IsJsonFormat() || opts.IsAslFormat() || opts.IsFspStyleMacro() ||
+			opts.IsSblStyleMacro() {
+		fmt.Printf("Error! The override tables are generated only with the pad_config macros!\n")
+		return 1
+	}
+	outdir := variantsDirGet()
+	if outdir == stdioName {
+		fmt.Printf("Error! The variants command writes several files, -o - can not be used!\n")
+		return 1
+	}
+	// the variant directories are named after the logs in the same way as
+	// the files in the batch mode
+	jobs, err := batchJobsGet(args[1:], outdir, "")
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+	for _, job := range jobs {
+		if filepath.Base(job.output) == variantBaseboardDir {
+			fmt.Printf("Error: %s would overwrite %s/gpio.c, rename the variant log!\n",
+				job.input, variantBaseboardDir)
+			return 1
+		}
+	}
+
+	base := parseFile(args[0], opts)
+	if base == nil {
+		return 1
+	}
+	baseFprint := base.BaseboardFprint
+	if !variantFileCreate(filepath.Join(outdir, variantBaseboardDir), args[0], baseFprint) {
+		return 1
+	}
+	fmt.Printf("%s: %d pads in %s/gpio.c\n", args[0], base.PadsNumGet(), variantBaseboardDir)
+
+	for _, job := range jobs {
+		variant := parseFile(job.input, opts)
+		if variant == nil {
+			return 1
+		}
+		if variant.PlatformGet() != base.PlatformGet() {
+			fmt.Printf("Warning: the logs are from different platforms: %s and %s\n",
+				base.PlatformGet(), variant.PlatformGet())
+		}
+		var count int
+		fprint := func(w io.Writer) (err error) {
+			count, err = variant.VariantFprint(w, base)
+			return err
+		}
+		if !variantFileCreate(job.output, job.input, fprint) {
+			return 1
+		}
+		fmt.Printf("%s: %d pads in %s/gpio.c\n", job.input, count, filepath.Base(job.output))
+	}
+	return 0
+}
+
+// commands - utility commands that are used instead of generating gpio.h
+var commands = map[string]func(args []string, opts *config.Options) int{
+	"diff":     diffCommand,
+	"compare":  compareCommand,
+	"batch":    batchCommand,
+	"decode":   decodeCommand,
+	"encode":   encodeCommand,
+	"lint":     lintCommand,
+	"variants": variantsCommand,
 }
 
 // main
@@ -35,11 +335,12 @@ func main() {
 	// Command line arguments
 	inputFileName := flag.String("file",
 		"inteltool.log",
-		"the path to the inteltool log file\n")
+		"the path to the inteltool log file, - for stdin\n")
 
 	outputFileName := flag.String("o",
 		"generate/gpio.h",
-		"the path to the generated file with GPIO configuration\n")
+		"the path to the generated file with GPIO configuration, - for stdout\n" +
+		"\t(the messages are printed to stderr)\n")
 
 	ignFlag := flag.Bool("ign",
 		false,
@@ -76,84 +377,199 @@ func main() {
 	template := flag.Int("t", 0, "template type number\n"+
 		"\t0 - inteltool.log (default)\n"+
 		"\t1 - gpio.h\n"+
-		"\t2 - your template\n\t")
-
-	platform :=  flag.String("p", "snr", "set platform:\n"+
+		"\t2 - your template\n"+
+		"\t3 - FSP/edk2 GPIO_INIT_CONFIG table, e.g. GpioTable.c\n"+
+		"\t4 - Linux pinctrl-intel debugfs, e.g.\n"+
+		"\t    /sys/kernel/debug/pinctrl/INT34BB:00/pins\n"+
+		"\t5 - firmware image (SPI flash dump) with GPIO_INIT_CONFIG\n"+
+		"\t    or Slim Bootloader GPIO_CFG_DATA tables\n\t")
+
+	imageTable := flag.Int("table", 1, "number of the GPIO table candidate in the\n"+
+		"\tfirmware image (-t 5), 1 is the most plausible one\n")
+
+	platform :=  flag.String("p", config.PlatformAuto, "set platform:\n"+
+		"\tauto - detect from the inteltool log header, snr if the\n"+
+		"\t       hardware is unknown or the input is gpio.h (default)\n"+
 		"\tsnr - Sunrise PCH or Skylake/Kaby Lake SoC\n"+
 		"\tlbg - Lewisburg PCH with Xeon SP\n"+
-		"\tapl - Apollo Lake SoC\n")
+		"\tapl - Apollo Lake SoC\n"+
+		"\tcnl - Cannon Lake/Comet Lake PCH\n"+
+		"\ttgl - Tiger Lake-LP SoC\n"+
+		"\tadl - Alder Lake-P/S\n")
+
+	verifyFlag := flag.Bool("verify",
+		false,
+		"re-encode each generated macro into DW0-DW2 and compare it with\n" +
+		"\tthe original register values (read-only fields are ignored)\n")
+
+	format := flag.String("format", "c", "set output format:\n"+
+		"\tc    - gpio.h with pad configuration macros (default)\n"+
+		"\tjson - one object per pad with raw and decoded register values\n"+
+		"\t       and the generated macro (generate/gpio.json by default)\n"+
+		"\tasl  - ACPI Interrupt/GpioInt/GpioIo resources for the pads routed\n"+
+		"\t       to IOxAPIC or owned by the GPIO driver (generate/gpio.asl)\n")
 
 	filedstyle :=  flag.String("fld", "none", "set fileds macros style:\n"+
 		"\tcb  - use coreboot style for bit fields macros\n"+
 		"\tfsp - use fsp style\n"+
-		"\traw - do not convert, print as is\n")
+		"\traw - do not convert, print as is\n"+
+		"\tsbl - Slim Bootloader GPIO_CFG_DATA in YAML (generate/gpio.yaml)\n")
 
-	flag.Parse()
+	strictFlag := flag.Bool("strict",
+		false,
+		"fail if there are warnings or errors in the input file, e.g. the pads\n" +
+		"\tthat were skipped or have reserved field values\n")
+
+	suppress := flag.String("suppress", "",
+		"comma-separated list of lint rules that should not be reported,\n" +
+		"\t<rule>:<pad> suppresses the rule only for the pad\n")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(),
+			"Usage: %s [options]\n"+
+			"       %s [options] diff <first.log> <second.log>\n"+
+			"       %s [options] compare <gpio.h> <inteltool.log>\n"+
+			"       %s [options] batch <dir|glob> [<output dir>]\n"+
+			"       %s [options] decode <pad> <dw0> <dw1> [<dw2>]\n"+
+			"       %s [options] encode <macro>|<pad> <name>=<value>...\n"+
+			"       %s [options] lint <file>\n"+
+			"       %s [options] variants <baseboard.log> <variant.log>...\n",
+			os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0],
+			os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+
+	// intelp2m <command> [options] args or intelp2m [options] <command> args
+	var command string
+	var args []string
+	if len(os.Args) > 1 && commands[os.Args[1]] != nil {
+		command = os.Args[1]
+		flag.CommandLine.Parse(os.Args[2:])
+		args = flag.Args()
+	} else {
+		flag.Parse()
+		if flag.NArg() > 0 {
+			command = flag.Arg(0)
+			args = flag.Args()[1:]
+		}
+	}
 
-	config.IgnoredFieldsFlagSet(*ignFlag)
-	config.NonCheckingFlagSet(*nonCheckFlag)
+	opts := &config.Options{}
+	opts.IgnoredFieldsFlagSet(*ignFlag)
+	opts.NonCheckingFlagSet(*nonCheckFlag)
+	opts.LintSuppressSet(*suppress)
+	opts.StrictFlagSet(*strictFlag)
 
 	if *infoLevel1 {
-		config.InfoLevelSet(1)
+		opts.InfoLevelSet(1)
 	} else if *infoLevel2 {
-		config.InfoLevelSet(2)
+		opts.InfoLevelSet(2)
 	} else if *infoLevel3 {
-		config.InfoLevelSet(3)
+		opts.InfoLevelSet(3)
 	} else if *infoLevel4 {
-		config.InfoLevelSet(4)
+		opts.InfoLevelSet(4)
 	}
 
-	if !config.TemplateSet(*template) {
+	if !opts.TemplateSet(*template) {
 		fmt.Printf("Error! Unknown template format of input file!\n")
 		os.Exit(1)
 	}
 
-	if valid := config.PlatformSet(*platform); valid != 0 {
-		fmt.Printf("Error: invalid platform -%s!\n", *platform)
+	if !opts.ImageTableSet(*imageTable) {
+		fmt.Printf("Error! Invalid GPIO table number %d!\n", *imageTable)
 		os.Exit(1)
 	}
 
-	fmt.Println("Log file:", *inputFileName)
-	fmt.Println("Output generated file:", *outputFileName)
-
-	inputRegDumpFile, err := os.Open(*inputFileName)
-	if err != nil {
-		fmt.Printf("Error: inteltool log file was not found!\n")
+	if valid := opts.PlatformSet(*platform); valid != 0 {
+		fmt.Printf("Error: invalid platform -%s!\n", *platform)
 		os.Exit(1)
 	}
 
-	if config.FldStyleSet(*filedstyle) != 0 {
+	if opts.FldStyleSet(*filedstyle) != 0 {
 		fmt.Printf("Error! Unknown bit fields style option -%s!\n", *filedstyle)
 		os.Exit(1)
 	}
 
-	// create dir for output files
-	err = os.MkdirAll("generate", os.ModePerm)
-	if err != nil {
-		fmt.Printf("Error! Can not create a directory for the generated files!\n")
+	if opts.OutputFormatSet(*format) != 0 {
+		fmt.Printf("Error! Unknown output format -%s!\n", *format)
 		os.Exit(1)
 	}
 
-	// create empty gpio.h file
-	outputGenFile, err := os.Create(*outputFileName)
+	if opts.IsJsonFormat() || opts.IsAslFormat() || opts.IsSblStyleMacro() {
+		outputFileNameSet := false
+		flag.Visit(func(f *flag.Flag) {
+			if f.Name == "o" {
+				outputFileNameSet = true
+			}
+		})
+		switch {
+		case outputFileNameSet:
+		case opts.IsJsonFormat():
+			*outputFileName = "generate/gpio.json"
+		case opts.IsAslFormat():
+			*outputFileName = "generate/gpio.asl"
+		default:
+			*outputFileName = "generate/gpio.yaml"
+		}
+	}
+
+	if command != "" {
+		run, valid := commands[command]
+		if !valid {
+			fmt.Printf("Error! Unknown command %s!\n", command)
+			os.Exit(1)
+		}
+		os.Exit(run(args, opts))
+	}
+
+	// the messages go to stderr if the generated file is written to stdout
+	var msg io.Writer = os.Stdout
+	if *outputFileName == stdioName {
+		msg = os.Stderr
+	}
+
+	fmt.Fprintln(msg, "Log file:", *inputFileName)
+	fmt.Fprintln(msg, "Output generated file:", *outputFileName)
+
+	inputRegDumpFile, err := inputOpen(*inputFileName)
 	if err != nil {
-		fmt.Printf("Error: unable to generate GPIO config file!\n")
+		fmt.Fprintf(msg, "Error: inteltool log file was not found!\n")
 		os.Exit(1)
 	}
 
 	defer inputRegDumpFile.Close()
-	defer outputGenFile.Close()
 
-	config.OutputGenFile = outputGenFile
-	config.InputRegDumpFile = inputRegDumpFile
+	parser := parser.NewParserData(opts, msg)
+	if err := parser.Parse(inputRegDumpFile); err != nil {
+		// the notes found before the error, e.g. the GPIO table candidates
+		parser.DiagnosticsFprint(os.Stderr, *inputFileName)
+		fmt.Fprintf(msg, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if !diagnosticsCheck(parser, msg, *inputFileName, opts) {
+		os.Exit(1)
+	}
 
-	parser := parser.ParserData{}
-	parser.Parse()
+	// create empty gpio.h file
+	outputGenFile, err := outputCreate(*outputFileName)
+	if err != nil {
+		fmt.Fprintf(msg, "Error: unable to generate GPIO config file: %v\n", err)
+		os.Exit(1)
+	}
+	defer outputGenFile.Close()
 
 	// gpio.h
-	err = generateOutputFile(&parser)
+	err = generateOutputFile(parser, outputGenFile, opts)
 	if err != nil {
-		fmt.Printf("Error! Can not create the file with GPIO configuration!\n")
+		fmt.Fprintf(msg, "Error! Can not create the file with GPIO configuration: %v\n", err)
 		os.Exit(1)
 	}
+
+	if *verifyFlag {
+		if mismatches := parser.PadMapVerify(msg); mismatches != 0 {
+			fmt.Fprintf(msg, "Verification failed: %d pads do not match!\n", mismatches)
+			os.Exit(1)
+		}
+		fmt.Fprintln(msg, "Verification passed")
+	}
 }
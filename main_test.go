@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// mainEnv - environment variable that makes the test binary run main()
+const mainEnv = "INTELP2M_TEST_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(mainEnv) != "" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runMain - runs the utility with the arguments in a separate process
+// stdin : standard input of the utility
+// args  : command line arguments
+// return
+//     standard output, standard error and exit status
+func runMain(t *testing.T, stdin string, args ...string) (string, string, int) {
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), mainEnv+"=1")
+	cmd.Dir = t.TempDir()
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	if exit, valid := err.(*exec.ExitError); valid {
+		return stdout.String(), stderr.String(), exit.ExitCode()
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	return stdout.String(), stderr.String(), 0
+}
+
+// mainLog - Sunrise Point inteltool log
+var mainLog = strings.Join([]string{
+	"Southbridge: 8086:9d48 (Intel(R) 100 Series Chipset (Sunrise Point-LP))",
+	"============= GPIO =============",
+	"------- GPIO Community 0 -------",
+	"------- GPIO Group GPP_A -------",
+	"0x0400: 0x0000001840000400 GPP_A0   RCIN#",
+	"0x0408: 0x0000001840880100 GPP_A1   GPIO",
+}, "\n")
+
+// TestStdio - the log is read from stdin, gpio.h is written to stdout and the
+// messages are printed to stderr
+func TestStdio(t *testing.T) {
+	stdout, stderr, status := runMain(t, mainLog, "-file", "-", "-o", "-")
+	if status != 0 {
+		t.Fatalf("exit status %d\n%s", status, stderr)
+	}
+	for _, want := range []string{
+		"#ifndef CFG_GPIO_H",
+		"\tPAD_CFG_NF(GPP_A0, NONE, DEEP, NF1),",
+		"\tPAD_CFG_GPI_SCI(GPP_A1, NONE, DEEP, LEVEL, INVERT),",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("no %q in stdout\n%s", want, stdout)
+		}
+	}
+	if strings.Contains(stdout, "Log file:") || !strings.Contains(stderr, "Log file: -") {
+		t.Errorf("messages are not in stderr\nstdout:\n%s\nstderr:\n%s", stdout, stderr)
+	}
+}
+
+// TestOutputCreate - the directory of the generated file is created
+func TestOutputCreate(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "board", "gpio.h")
+	file, err := outputCreate(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	file.Close()
+	if _, err := os.Stat(name); err != nil {
+		t.Errorf("%s is not created: %v", name, err)
+	}
+	if file, err := outputCreate(stdioName); err != nil || file != os.Stdout {
+		t.Errorf("-: got %v %v, want stdout", file, err)
+	}
+	if file, err := inputOpen(stdioName); err != nil || file != os.Stdin {
+		t.Errorf("-: got %v %v, want stdin", file, err)
+	}
+	input := filepath.Join(t.TempDir(), "inteltool.log")
+	if err := ioutil.WriteFile(input, []byte(mainLog), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if file, err := inputOpen(input); err != nil {
+		t.Errorf("%s: unexpected error: %v", input, err)
+	} else {
+		file.Close()
+	}
+}
+
+// TestVerify - the utility fails if a generated macro does not match the log
+func TestVerify(t *testing.T) {
+	_, stderr, status := runMain(t, mainLog, "-file", "-", "-o", "-", "-verify")
+	if status != 0 || !strings.Contains(stderr, "Verification passed") {
+		t.Errorf("exit status %d\n%s", status, stderr)
+	}
+	// PAD_NC() sets RXEVCFG to OFF
+	log := mainLog + "\n0x0410: 0x0000001840000300 GPP_A2   GPIO"
+	stdout, stderr, status := runMain(t, log, "-file", "-", "-o", "-", "-verify")
+	if status != 1 || !strings.Contains(stderr, "Verification failed: 1 pads do not match!") {
+		t.Errorf("exit status %d\n%s", status, stderr)
+	}
+	// the report is not mixed into the generated file
+	if !strings.Contains(stderr, "GPP_A2: PAD_NC(GPP_A2, NONE),\n") ||
+		strings.Contains(stdout, "macro sets") {
+		t.Errorf("report is not in stderr\nstdout:\n%s\nstderr:\n%s", stdout, stderr)
+	}
+}
+
+// TestStrict - the utility fails if there are problems in the input file and
+// the -strict option is set
+func TestStrict(t *testing.T) {
+	// GPP_A2 has the reserved TERM value
+	log := mainLog + "\n0x0410: 0x00000c1844000702 GPP_A2   LAD1"
+	_, stderr, status := runMain(t, log, "-file", "-", "-o", "-")
+	if status != 0 || !strings.Contains(stderr, "-:7: error: GPP_A2: invalid TERM value 0x3\n") {
+		t.Errorf("exit status %d\n%s", status, stderr)
+	}
+	stdout, stderr, status := runMain(t, log, "-file", "-", "-o", "-", "-strict")
+	if status != 1 || stdout != "" ||
+		!strings.Contains(stderr, "Error: 1 problems found in - (-strict)!\n") {
+		t.Errorf("exit status %d\nstdout:\n%s\nstderr:\n%s", status, stdout, stderr)
+	}
+	_, stderr, status = runMain(t, mainLog, "-file", "-", "-o", "-", "-strict")
+	if status != 0 {
+		t.Errorf("no problems: exit status %d\n%s", status, stderr)
+	}
+	// the log without the hardware header is reported at the info level
+	noheader := mainLog[strings.Index(mainLog, "\n")+1:]
+	_, stderr, status = runMain(t, noheader, "-file", "-", "-o", "-", "-strict")
+	if status != 0 || !strings.Contains(stderr, "-: info: unable to detect the platform") {
+		t.Errorf("no header: exit status %d\n%s", status, stderr)
+	}
+}
+
+// TestVariants - the variant log named as the baseboard directory and -o - are
+// refused before any file is written
+func TestVariants(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"base.log", "brd1.log", "baseboard.log"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(mainLog), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	base, brd1 := filepath.Join(dir, "base.log"), filepath.Join(dir, "brd1.log")
+	outdir := filepath.Join(dir, "variants")
+	stdout, _, status := runMain(t, "", "variants", "-o", outdir, base, brd1)
+	if status != 0 {
+		t.Fatalf("exit status %d\n%s", status, stdout)
+	}
+	for _, name := range []string{"baseboard", "brd1"} {
+		if _, err := os.Stat(filepath.Join(outdir, name, "gpio.c")); err != nil {
+			t.Errorf("%s/gpio.c is not generated: %v", name, err)
+		}
+	}
+
+	outdir = filepath.Join(dir, "clash")
+	stdout, _, status = runMain(t, "", "variants", "-o", outdir, base,
+		filepath.Join(dir, "baseboard.log"))
+	if status != 1 || !strings.Contains(stdout, "would overwrite baseboard/gpio.c") {
+		t.Errorf("exit status %d\n%s", status, stdout)
+	}
+	if _, err := os.Stat(outdir); err == nil {
+		t.Errorf("%s is created", outdir)
+	}
+
+	stdout, _, status = runMain(t, "", "variants", "-o", "-", base, brd1)
+	if status != 1 || !strings.Contains(stdout, "-o - can not be used") {
+		t.Errorf("-o -: exit status %d\n%s", status, stdout)
+	}
+}
+
+// TestDiff - stdout of the diff command contains only the differences, the
+// parser messages are printed to stderr
+func TestDiff(t *testing.T) {
+	dir := t.TempDir()
+	logs := map[string]string{
+		"first.log":  mainLog,
+		"second.log": strings.Replace(mainLog, "0x0000001840880100", "0x0000001840880101", 1),
+	}
+	for name, log := range logs {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(log), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	first, second := filepath.Join(dir, "first.log"), filepath.Join(dir, "second.log")
+	stdout, stderr, status := runMain(t, "", "diff", first, second)
+	if status != 1 {
+		t.Errorf("exit status %d\n%s%s", status, stdout, stderr)
+	}
+	want := "--- " + first + "\n+++ " + second + "\n"
+	if !strings.HasPrefix(stdout, want) || !strings.Contains(stdout, "GPP_A1") {
+		t.Errorf("stdout:\n%s", stdout)
+	}
+	for _, msg := range []string{"Parse IntelTool Log File...", "detected from"} {
+		if strings.Contains(stdout, msg) || !strings.Contains(stderr, msg) {
+			t.Errorf("%q is not printed to stderr\nstdout:\n%s\nstderr:\n%s", msg,
+				stdout, stderr)
+		}
+	}
+}
+
+// TestImage - the platform is not detected from the firmware image
+func TestImage(t *testing.T) {
+	_, stderr, status := runMain(t, "", "-file", "-", "-o", "-", "-t", "5")
+	if status != 1 || !strings.Contains(stderr, "-p is required for -t 5") {
+		t.Errorf("exit status %d\n%s", status, stderr)
+	}
+}
+
+// TestGpiohRoundTrip - the generated gpio.h with the lock table and the GPE0
+// routing is accepted as the input in the strict mode
+func TestGpiohRoundTrip(t *testing.T) {
+	log := mainLog + "\n" + strings.Join([]string{
+		"------- GPIO Community 1 -------",
+		"0x0010: 0x00021000 (MISCCFG)",
+		"0x00a0: 0x00000001 (PADCFGLOCK_GPP_B)",
+		"0x00a4: 0x00000001 (PADCFGLOCKTX_GPP_B)",
+		"------- GPIO Group GPP_B -------",
+		"0x0400: 0x0000001844000702 GPP_B0   CORE_VID0",
+	}, "\n")
+	gpioh, stderr, status := runMain(t, log, "-file", "-", "-o", "-")
+	if status != 0 || !strings.Contains(gpioh, "\t{ GPP_B0, GPIO_LOCK_FULL },\n") ||
+		!strings.Contains(gpioh, "\tregister \"gpe0_dw0\" = \"GPP_A\"\n") {
+		t.Fatalf("exit status %d\n%s\n%s", status, gpioh, stderr)
+	}
+	_, stderr, status = runMain(t, gpioh, "-file", "-", "-o", "-", "-t", "1", "-strict")
+	if status != 0 {
+		t.Errorf("-t 1: exit status %d\n%s", status, stderr)
+	}
+}
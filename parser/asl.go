@@ -0,0 +1,186 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// aslPad - pad configuration for the ACPI resources
+// dw0 : DW0 register
+// dw1 : DW1 register
+type aslPad struct {
+	dw0 *common.Register
+	dw1 *common.Register
+}
+
+// trigGet - returns the interrupt mode: Level or Edge
+func (pad *aslPad) trigGet() string {
+	if pad.dw0.GetRXLevelEdgeConfiguration() == common.TRIG_LEVEL {
+		return "Level"
+	}
+	return "Edge"
+}
+
+// polarityGet - returns the interrupt polarity. The pad inverts the active-low
+// signal (RXINV), so that the interrupt controller gets the active-high one.
+func (pad *aslPad) polarityGet() string {
+	switch {
+	case pad.dw0.GetRXLevelEdgeConfiguration() == common.TRIG_EDGE_BOTH:
+		return "ActiveBoth"
+	case pad.dw0.GetRxInvert() != 0:
+		return "ActiveLow"
+	}
+	return "ActiveHigh"
+}
+
+// pullGet - returns the pin configuration from the pad termination
+// platform : platform-specific interface
+func (pad *aslPad) pullGet(platform common.EncoderSpecific) string {
+	pull, valid := platform.PullDecode(pad.dw1.GetTermination())
+	switch {
+	case !valid:
+		return "PullDefault"
+	case pull == "NONE":
+		return "PullNone"
+	case strings.Contains(pull, "UP") || strings.HasSuffix(pull, "_PU"):
+		return "PullUp"
+	case strings.Contains(pull, "DN") || strings.HasSuffix(pull, "_PD"):
+		return "PullDown"
+	}
+	// NATIVE
+	return "PullDefault"
+}
+
+// aslResourceGet - returns the ACPI resource for the pad:
+// Interrupt() for the pads routed to IOxAPIC, GpioInt() for the inputs owned
+// by the GPIO driver and GpioIo() for other GPIOs owned by the GPIO driver
+// pad : pad info
+// pos : pad position for the ACPI pin number
+// return
+//     resource, empty if the pad does not need it
+//     the reason why the resource is skipped, empty if it is not
+func (parser *ParserData) aslResourceGet(pad *padInfo, pos common.PadPosition) (string, string) {
+	asl := aslPad{dw0: &common.Register{}, dw1: &common.Register{}}
+	asl.dw0.ValueSet(pad.dw0)
+	asl.dw1.ValueSet(pad.dw1)
+	if asl.dw0.GetPadMode() != 0 {
+		// native function
+		return "", ""
+	}
+	rxtx := asl.dw0.GetGPIORxTxDisableStatus()
+	rxEnabled, txEnabled := rxtx&0x2 == 0, rxtx&0x1 == 0
+	trig := asl.dw0.GetRXLevelEdgeConfiguration()
+	if asl.dw0.GetGPIOInputRouteIOxAPIC() != 0 {
+		if !parser.isRegisterDump() {
+			// INTSEL is set by the hardware and is only in the register dumps
+			return "", "the IRQ (INTSEL) is only in the register dumps"
+		}
+		return fmt.Sprintf("Interrupt (ResourceConsumer, %s, %s, Exclusive) { %d }",
+			asl.trigGet(), asl.polarityGet(), pad.intsel), ""
+	}
+	if pad.ownership != common.PAD_OWN_DRIVER || !rxEnabled && !txEnabled {
+		// not connected
+		return "", ""
+	}
+	controller, pin, valid := parser.platform.AcpiPinGet(pos)
+	if !valid {
+		return "", fmt.Sprintf("the ACPI pin number is not known for -p %s",
+			parser.opts.PlatformNameGet())
+	}
+	controller = strings.Replace(controller, "\\", "\\\\", -1)
+	switch {
+	case rxEnabled && trig != common.TRIG_OFF:
+		// no bit of the pad tells if it can wake the system, the SCI route
+		// raises GPE, which is described by _PRW and not by GpioInt()
+		return fmt.Sprintf("GpioInt (%s, %s, Exclusive, %s, , \"%s\") { %s }",
+			asl.trigGet(), asl.polarityGet(), asl.pullGet(parser.platform),
+			controller, pin), ""
+	case rxEnabled && txEnabled:
+		return fmt.Sprintf("GpioIo (Exclusive, %s, , , IoRestrictionNone, \"%s\") { %s }",
+			asl.pullGet(parser.platform), controller, pin), ""
+	case rxEnabled:
+		return fmt.Sprintf("GpioIo (Exclusive, %s, , , IoRestrictionInputOnly, \"%s\") { %s }",
+			asl.pullGet(parser.platform), controller, pin), ""
+	}
+	return fmt.Sprintf("GpioIo (Exclusive, %s, , , IoRestrictionOutputOnly, \"%s\") { %s }",
+		asl.pullGet(parser.platform), controller, pin), ""
+}
+
+// aslCommunityGet - returns the community number from the title of the
+// inteltool log, e.g. "------- GPIO Community 0 -------"
+// title : title
+// return false if it is not the community title
+func aslCommunityGet(title string) (int, bool) {
+	const keyword = "GPIO Community "
+	start := strings.Index(title, keyword)
+	if start < 0 {
+		return 0, false
+	}
+	var community int
+	if _, err := fmt.Sscanf(title[start+len(keyword):], "%d", &community); err != nil {
+		return 0, false
+	}
+	return community, true
+}
+
+// PadMapAslFprint - print the ACPI resources for the pads routed to IOxAPIC
+// and for the pads owned by the GPIO driver to file. The ACPI path of the GPIO
+// controller and the pin number are provided by the platform, the pads without
+// them are listed in the comments.
+// w : writer for the generated file
+// return error
+func (parser *ParserData) PadMapAslFprint(w io.Writer) error {
+	_, err := io.WriteString(w, `/* SPDX-License-Identifier: GPL-2.0-only */
+
+/* ACPI resources were generated automatically using intelp2m utility */
+`)
+	if err != nil {
+		return err
+	}
+	groups := make(map[string]bool)
+	for i := range parser.padmap {
+		if valid, group, _ := parser.padNumberGet(parser.padmap[i].id); valid {
+			groups[group] = true
+		}
+	}
+	community := -1
+	var index, global uint
+	for i := range parser.padmap {
+		pad := &parser.padmap[i]
+		if pad.id == "" {
+			if number, valid := aslCommunityGet(pad.function); valid {
+				community, index = number, 0
+			}
+			continue
+		}
+		pos := common.PadPosition{
+			Id:        pad.id,
+			Dump:      parser.isRegisterDump(),
+			Community: community,
+			Index:     index,
+			Global:    global,
+			Groups:    groups,
+		}
+		_, pos.Group, pos.Number = parser.padNumberGet(pad.id)
+		// reserved pads also have the pin numbers
+		index++
+		global++
+		if pad.dw0 == 0xffffffff {
+			continue
+		}
+		resource, skipped := parser.aslResourceGet(pad, pos)
+		title := pad.id
+		if function := pad.functionGet(); function != "" {
+			title += " - " + function
+		}
+		if skipped != "" {
+			fmt.Fprintf(w, "\n/* %s: skipped, %s */\n", title, skipped)
+		} else if resource != "" {
+			fmt.Fprintf(w, "\n/* %s */\n%s\n", title, resource)
+		}
+	}
+	return nil
+}
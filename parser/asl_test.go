@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"bytes"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+
+// aslHeader - header of the generated ACPI resources
+const aslHeader = `/* SPDX-License-Identifier: GPL-2.0-only */
+
+/* ACPI resources were generated automatically using intelp2m utility */
+`
+
+func TestPadMapAslFprint(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform string
+		template int
+		input    []string
+		asl      string
+	}{
+		{"inteltool log", "snr", config.TempInteltool, []string{
+			"============= GPIO =============",
+			"------- GPIO Community 0 -------",
+			"0x00d0: 0x000001fc (HOSTSW_OWN_GPP_A)",
+			"------- GPIO Group GPP_A -------",
+			"0x0400: 0x0000001840000400 GPP_A0   RCIN#",
+			"0x0408: 0x0000001e82900100 GPP_A1   GPIO",
+			"0x0410: 0x00000000ffffffff GPP_A2   RESERVED",
+			"0x0418: 0x0000301882000100 GPP_A3   GPIO",
+			"0x0420: 0x0000100080080100 GPP_A4   GPIO",
+			"0x0428: 0x0000000084000200 GPP_A5   GPIO",
+			"0x0430: 0x0000000084000300 GPP_A6   GPIO",
+			"0x0438: 0x0000000084000000 GPP_A7   GPIO",
+			"0x0440: 0x0000000084000100 GPP_A8   GPIO",
+			"0x0450: 0x0000000082000100 GPP_A10  GPIO",
+		}, aslHeader + `
+/* GPP_A1 - GPIO */
+Interrupt (ResourceConsumer, Edge, ActiveLow, Exclusive) { 30 }
+
+/* GPP_A3 - GPIO */
+GpioInt (Edge, ActiveHigh, Exclusive, PullUp, , "\\_SB.PCI0.GPIO") { 3 }
+
+/* GPP_A4 - GPIO */
+GpioInt (Level, ActiveHigh, Exclusive, PullDown, , "\\_SB.PCI0.GPIO") { 4 }
+
+/* GPP_A5 - GPIO */
+GpioIo (Exclusive, PullNone, , , IoRestrictionOutputOnly, "\\_SB.PCI0.GPIO") { 5 }
+
+/* GPP_A7 - GPIO */
+GpioIo (Exclusive, PullNone, , , IoRestrictionNone, "\\_SB.PCI0.GPIO") { 7 }
+
+/* GPP_A8 - GPIO */
+GpioIo (Exclusive, PullNone, , , IoRestrictionInputOnly, "\\_SB.PCI0.GPIO") { 8 }
+`},
+		{"gpio.h", "snr", config.TempGpioh, []string{
+			"PAD_CFG_GPI_APIC(GPP_A1, NONE, PLTRST, EDGE_SINGLE, INVERT),",
+			"PAD_CFG_GPI_INT(GPP_A3, UP_20K, PLTRST, EDGE_SINGLE),",
+			"PAD_CFG_GPI_SCI(GPP_A4, NONE, PLTRST, LEVEL, INVERT),",
+			"_PAD_CFG_STRUCT(GPP_A5, PAD_FUNC(GPIO) | PAD_RESET(PLTRST) | PAD_TRIG(LEVEL) | " +
+				"PAD_IRQ_ROUTE(SCI) | PAD_BUF(TX_DISABLE), PAD_CFG_OWN_GPIO(DRIVER)),",
+		}, aslHeader + `
+/* GPP_A1: skipped, the IRQ (INTSEL) is only in the register dumps */
+
+/* GPP_A3 */
+GpioInt (Edge, ActiveHigh, Exclusive, PullUp, , "\\_SB.PCI0.GPIO") { GPP_A3 }
+
+/* GPP_A5 */
+GpioInt (Level, ActiveHigh, Exclusive, PullNone, , "\\_SB.PCI0.GPIO") { GPP_A5 }
+`},
+		{"pin number", "cnl", config.TempGpioh, []string{
+			"PAD_CFG_GPI_INT(GPP_A3, UP_20K, PLTRST, EDGE_SINGLE),",
+			"PAD_CFG_GPO_GPIO_DRIVER(GPP_K1, 1, PLTRST, NONE),",
+		}, aslHeader + `
+/* GPP_A3: skipped, the ACPI pin number is not known for -p cnl */
+
+/* GPP_K1: skipped, the ACPI pin number is not known for -p cnl */
+`},
+	}
+	for _, test := range tests {
+		parser := parse(t, test.platform, test.template, test.input...)
+		var buf bytes.Buffer
+		if err := parser.PadMapAslFprint(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != test.asl {
+			t.Errorf("%s: got\n%s\nwant\n%s", test.name, buf.String(), test.asl)
+		}
+	}
+}
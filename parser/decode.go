@@ -0,0 +1,129 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// padCheck - checks that the pad and its configuration registers exist on the
+// platform
+// id   : pad id string
+// regs : DW0-DW3 register values
+// return
+//     read-only fields masks and error
+func (parser *ParserData) padCheck(id string, regs [common.MAX_DW_NUM]uint32) (
+		[common.MAX_DW_NUM]uint32, error) {
+	ro := parser.readOnlyFieldsGet()
+	if !parser.platform.KeywordCheck(id) {
+		return ro, fmt.Errorf("unknown pad %s for this platform", id)
+	}
+	for dw := range regs {
+		if ro[dw] == common.AllFields && regs[dw] != 0 {
+			return ro, fmt.Errorf("there is no DW%d register on this platform", dw)
+		}
+	}
+	return ro, nil
+}
+
+// padRegsFprint - prints the register values of the pad
+// w   : writer
+// pad : pad info
+// ro  : read-only fields masks
+func padRegsFprint(w io.Writer, pad *padInfo, ro [common.MAX_DW_NUM]uint32) {
+	fmt.Fprintf(w, "%s: DW0: 0x%0.8x, DW1: 0x%0.8x", pad.id, pad.dw0, pad.dw1)
+	if ro[common.PAD_CFG_DW2] != common.AllFields {
+		fmt.Fprintf(w, ", DW2: 0x%0.8x", pad.dw2)
+	}
+	if pad.ownership == common.PAD_OWN_DRIVER {
+		fmt.Fprintf(w, ", HOSTSW_OWN: DRIVER")
+	}
+	fmt.Fprintf(w, "\n")
+}
+
+// styleMacroFprint - prints the macro generated in the specified style
+// w     : writer
+// pad   : pad info
+// title : title of the macro
+// style : bit fields macros style
+func (parser *ParserData) styleMacroFprint(w io.Writer, pad *padInfo, title string,
+		style string) {
+	opts := *parser.opts
+	opts.FldStyleSet(style)
+	macro := parser.platform.GenMacro(pad.id, pad.dw0, pad.dw1, pad.dw2,
+		pad.ownership, pad.lock, &opts)
+	fmt.Fprintf(w, "%s:\n\t%s\n", title, strings.TrimSpace(macro))
+}
+
+// PadDecodeFprint - prints the macros generated for a single pad and the
+// decoded bit fields of its configuration registers. The register values are
+// taken from the command line instead of the inteltool log.
+// w         : writer for the decoded pad
+// id        : pad id string
+// regs      : DW0-DW3 register values
+// ownership : host software ownership
+// return error
+func (parser *ParserData) PadDecodeFprint(w io.Writer, id string,
+	regs [common.MAX_DW_NUM]uint32, ownership uint8) error {
+	parser.PlatformSpecificInterfaceSet()
+	ro, err := parser.padCheck(id, regs)
+	if err != nil {
+		return err
+	}
+	pad := padInfo{id: id, dw0: regs[common.PAD_CFG_DW0], dw1: regs[common.PAD_CFG_DW1],
+		dw2: regs[common.PAD_CFG_DW2], ownership: ownership}
+
+	padRegsFprint(w, &pad, ro)
+	parser.styleMacroFprint(w, &pad, "Macro", "none")
+	parser.styleMacroFprint(w, &pad, "coreboot bit fields", "cb")
+	parser.styleMacroFprint(w, &pad, "FSP bit fields", "fsp")
+
+	fmt.Fprintf(w, "Fields:\n")
+	table := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, field := range common.FieldsDescribe(parser.platform, pad.id, regs, ro) {
+		fmt.Fprintf(table, "\tDW%d\t%s\t%s\t0x%x", field.Dw, field.Name, field.Desc,
+			field.Value)
+		if field.Decoded != "" {
+			fmt.Fprintf(table, "\t%s", field.Decoded)
+		}
+		fmt.Fprintf(table, "\n")
+	}
+	return table.Flush()
+}
+
+// PadEncodeFprint - prints the DW0-DW3 register values for the pad
+// configuration macro or for the named bit fields and the macro generated from
+// these values
+// w    : writer for the encoded pad
+// args : pad configuration macro, e.g. PAD_CFG_NF(GPP_A1, 20K_PU, DEEP, NF1),
+//        or pad id followed by the bit fields, e.g. GPP_A1 mode=NF1 reset=DEEP
+// return error
+func (parser *ParserData) PadEncodeFprint(w io.Writer, args []string) error {
+	parser.PlatformSpecificInterfaceSet()
+	var cfg *common.PadConfig
+	var err error
+	if len(args) == 1 && strings.Contains(args[0], "(") {
+		cfg, err = common.MacroEncode(parser.platform, args[0])
+	} else {
+		cfg, err = common.FieldsEncode(parser.platform, args[0], args[1:],
+			parser.readOnlyFieldsGet())
+	}
+	if err != nil {
+		return err
+	}
+	pad := padInfo{id: cfg.Id,
+		dw0: cfg.Register(common.PAD_CFG_DW0).ValueGet(),
+		dw1: cfg.Register(common.PAD_CFG_DW1).ValueGet(),
+		dw2: cfg.Register(common.PAD_CFG_DW2).ValueGet(),
+		ownership: cfg.Ownership}
+	ro, err := parser.padCheck(pad.id, pad.dwGet())
+	if err != nil {
+		return err
+	}
+	padRegsFprint(w, &pad, ro)
+	parser.styleMacroFprint(w, &pad, "Macro", "none")
+	return nil
+}
@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// decode - decodes the pad registers with the converter settings
+// platform : platform name for the -p option
+// id       : pad id string
+// regs     : DW0-DW3 register values
+func decode(platform string, id string, regs ...uint32) (string, error) {
+	opts := &config.Options{}
+	opts.PlatformSet(platform)
+	var dw [common.MAX_DW_NUM]uint32
+	copy(dw[:], regs)
+	var buf bytes.Buffer
+	err := NewParserData(opts, nil).PadDecodeFprint(&buf, id, dw, common.PAD_OWN_ACPI)
+	return buf.String(), err
+}
+
+func TestPadDecodeFprint(t *testing.T) {
+	out, err := decode("tgl", "GPP_B1", 0x44000100, 0x00003000, 0x00000009)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	space := regexp.MustCompile(`\s+`)
+	for _, want := range []string{
+		"GPP_B1: DW0: 0x44000100, DW1: 0x00003000, DW2: 0x00000009 Macro:",
+		"PAD_CFG2_DEBEN | PAD_CFG2_DEBOUNCE_16_RTC),",
+		"GpioPadModeGpio, GpioHostOwnAcpi,",
+		" DW0 PMODE Pad Mode 0x0 GPIO ",
+		" DW1 TERM Termination 0xc UP_20K ",
+		" DW2 DEBOUNCE Debounce Duration 0x4 16_RTC ",
+		" DW0 GPIOTXSTATE GPIO TX State 0x0 DW1",
+	} {
+		if !strings.Contains(space.ReplaceAllString(out, " "), want) {
+			t.Errorf("no %q in\n%s", want, out)
+		}
+	}
+
+	out, err = decode("snr", "GPP_B1", 0x44000100, 0x00003000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(out, "DW2") {
+		t.Errorf("snr has no DW2 register\n%s", out)
+	}
+}
+
+func TestPadDecodeFprintErrors(t *testing.T) {
+	tests := []struct {
+		platform string
+		id       string
+		regs     []uint32
+		err      string
+	}{
+		{"snr", "GPIO_12", []uint32{0x44000100, 0}, "unknown pad GPIO_12 for this platform"},
+		{"snr", "GPP_B1", []uint32{0x44000100, 0, 0x9},
+			"there is no DW2 register on this platform"},
+	}
+	for _, test := range tests {
+		_, err := decode(test.platform, test.id, test.regs...)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("%s %s: got error %v, want %s", test.platform, test.id, err, test.err)
+		}
+	}
+}
+
+func TestPadEncodeFprint(t *testing.T) {
+	tests := []struct {
+		platform string
+		args     []string
+		out      string
+	}{
+		{"snr", []string{"PAD_CFG_NF(GPP_A1, 20K_PU, DEEP, NF1),"},
+			"GPP_A1: DW0: 0x40000400, DW1: 0x00003000\n" +
+				"Macro:\n\tPAD_CFG_NF(GPP_A1, 20K_PU, DEEP, NF1),\n"},
+		{"snr", []string{"GPP_A1", "mode=NF1", "reset=DEEP", "term=20K_PU", "own=DRIVER"},
+			"GPP_A1: DW0: 0x40000400, DW1: 0x00003000, HOSTSW_OWN: DRIVER\n" +
+				"Macro:\n\tPAD_CFG_NF(GPP_A1, 20K_PU, DEEP, NF1),\n"},
+	}
+	for _, test := range tests {
+		opts := &config.Options{}
+		opts.PlatformSet(test.platform)
+		var buf bytes.Buffer
+		if err := NewParserData(opts, nil).PadEncodeFprint(&buf, test.args); err != nil {
+			t.Errorf("%v: unexpected error: %v", test.args, err)
+			continue
+		}
+		if buf.String() != test.out {
+			t.Errorf("%v: got\n%s\nwant\n%s", test.args, buf.String(), test.out)
+		}
+	}
+
+	opts := &config.Options{}
+	opts.PlatformSet("snr")
+	err := NewParserData(opts, nil).PadEncodeFprint(&bytes.Buffer{},
+		[]string{"GPP_A1", "mode=NF1", "debounce=16_RTC"})
+	if want := "GPP_A1: there is no DW2 register on this platform"; err == nil || err.Error() != want {
+		t.Errorf("DW2 field on snr: got error %v, want %s", err, want)
+	}
+}
@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// pchDevice - range of the PCI device IDs of the LPC/eSPI controller in the
+// PCH or SoC, inteltool prints it as the southbridge
+// first    : first device ID
+// last     : last device ID
+// platform : platform name for the -p option
+// name     : hardware name for the messages
+type pchDevice struct {
+	first    uint16
+	last     uint16
+	platform string
+	name     string
+}
+
+// pchDevices - LPC/eSPI controllers of the supported platforms, see
+// src/include/device/pci_ids.h in coreboot
+var pchDevices = []pchDevice{
+	{0x9d40, 0x9d5f, "snr", "Sunrise Point-LP PCH"},
+	{0xa140, 0xa15f, "snr", "Sunrise Point-H PCH"},
+	{0xa2c0, 0xa2df, "snr", "Union Point PCH"},
+	{0xa1c0, 0xa1df, "lbg", "Lewisburg PCH"},
+	{0xa240, 0xa24f, "lbg", "Lewisburg PCH"},
+	{0x5ae8, 0x5ae8, "apl", "Apollo Lake SoC"},
+	{0x9d80, 0x9d9f, "cnl", "Cannon Point-LP PCH"},
+	{0xa300, 0xa31f, "cnl", "Cannon Point-H PCH"},
+	{0x0280, 0x029f, "cnl", "Comet Lake-LP PCH"},
+	{0x0680, 0x069f, "cnl", "Comet Lake-H PCH"},
+	{0xa080, 0xa09f, "tgl", "Tiger Lake-LP SoC"},
+	{0x5180, 0x519f, "adl", "Alder Lake-P SoC"},
+	{0x7a80, 0x7a9f, "adl", "Alder Lake-S PCH"},
+}
+
+// socModels - CPU models of the SoCs with the GPIO controller. They are used
+// if the southbridge is not in the log. The models of the CPUs used with a
+// separate PCH are not here, since they are combined with different PCHs.
+var socModels = map[uint32]pchDevice{
+	0x5c: {platform: "apl", name: "Apollo Lake SoC"},
+	0x8c: {platform: "tgl", name: "Tiger Lake-LP SoC"},
+	0x9a: {platform: "adl", name: "Alder Lake-P SoC"},
+}
+
+// southbridgeGet - returns the hardware for the southbridge from the header
+// of the inteltool log, e.g.
+// Southbridge: 8086:9d4e (Intel(R) 100 Series Chipset (Sunrise Point-LP))
+func southbridgeGet(line string) (pchDevice, bool) {
+	fields := strings.Fields(strings.TrimPrefix(line, "Southbridge:"))
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "8086:") {
+		return pchDevice{}, false
+	}
+	id, err := strconv.ParseUint(strings.TrimPrefix(fields[0], "8086:"), 16, 16)
+	if err != nil {
+		return pchDevice{}, false
+	}
+	for _, device := range pchDevices {
+		if uint16(id) >= device.first && uint16(id) <= device.last {
+			device.name += " (" + fields[0] + ")"
+			return device, true
+		}
+	}
+	return pchDevice{}, false
+}
+
+// cpuGet - returns the SoC for the CPU signature from the header of the
+// inteltool log, e.g.
+// CPU: ID 0x506c9, Processor Type 0x0, Family 0x6, Model 0x5c, Stepping 0x9
+func cpuGet(line string) (pchDevice, bool) {
+	fields := strings.Fields(strings.TrimPrefix(line, "CPU: ID"))
+	if len(fields) == 0 {
+		return pchDevice{}, false
+	}
+	id, err := strconv.ParseUint(strings.TrimSuffix(fields[0], ","), 0, 32)
+	if err != nil {
+		return pchDevice{}, false
+	}
+	// extended model and model
+	model := uint32(id>>12)&0xf0 | uint32(id>>4)&0xf
+	if device, valid := socModels[model]; valid {
+		device.name += fmt.Sprintf(" (CPU ID 0x%x)", id)
+		return device, true
+	}
+	return pchDevice{}, false
+}
+
+// hardwareDetect - detects the platform from the header of the inteltool log.
+// The southbridge is preferred, the CPU signature is used for the SoCs only.
+// lines : lines of the inteltool log
+// return
+//     hardware from the log
+//     false if there is no known hardware in the log
+func hardwareDetect(lines []string) (pchDevice, bool) {
+	var soc pchDevice
+	var socFound bool
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "Southbridge:"):
+			if device, valid := southbridgeGet(line); valid {
+				return device, true
+			}
+		case strings.HasPrefix(line, "CPU: ID"):
+			soc, socFound = cpuGet(line)
+		case strings.Contains(line, "GPIO Community"):
+			// end of the header
+			return soc, socFound
+		}
+	}
+	return soc, socFound
+}
+
+// platformDetect - selects the platform detected from the header of the
+// inteltool log if the -p option is not set. The parser uses its own copy of
+// the settings, so the logs in the batch mode can be from different platforms.
+// lines : lines of the input file
+// return error if the -p option contradicts the detected hardware
+func (parser *ParserData) platformDetect(lines []string) error {
+	if parser.opts.TemplateGet() != config.TempInteltool {
+		return nil
+	}
+	device, found := hardwareDetect(lines)
+	switch {
+	case !found && parser.opts.IsPlatformAuto():
+		// old logs have no header, this is not a problem in the pad
+		// configuration, so -strict must not fail because of it
+		parser.diagAdd(common.LintInfo, "", "unable to detect the platform from the "+
+			"inteltool log header, -p %s is used", parser.opts.PlatformNameGet())
+	case !found:
+		return nil
+	case !parser.opts.IsPlatformAuto() && device.platform != parser.opts.PlatformNameGet():
+		return fmt.Errorf("-p %s contradicts the hardware in the log: %s, use -p %s",
+			parser.opts.PlatformNameGet(), device.name, device.platform)
+	case parser.opts.IsPlatformAuto():
+		opts := *parser.opts
+		opts.PlatformSet(device.platform)
+		parser.opts = &opts
+		fmt.Fprintf(parser.log, "Platform: %s, detected from %s\n", device.platform, device.name)
+	}
+	return nil
+}
+
+// PlatformGet - returns the platform name used by the parser, it can be
+// detected from the inteltool log
+func (parser *ParserData) PlatformGet() string {
+	return parser.opts.PlatformNameGet()
+}
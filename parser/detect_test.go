@@ -0,0 +1,118 @@
+package parser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+
+// detectParse - parses the inteltool log with the -p option and returns the
+// parser messages followed by the diagnostics
+// platform : platform name for the -p option
+// input    : lines of the inteltool log
+func detectParse(t *testing.T, platform string, input ...string) (*ParserData, string,
+	error) {
+	opts := &config.Options{}
+	if opts.PlatformSet(platform) != 0 {
+		t.Fatalf("invalid platform %s", platform)
+	}
+	var log bytes.Buffer
+	parser := NewParserData(opts, &log)
+	err := parser.Parse(strings.NewReader(strings.Join(input, "\n")))
+	parser.DiagnosticsFprint(&log, "inteltool.log")
+	return parser, log.String(), err
+}
+
+// cnlLog - Cannon Point-LP inteltool log without the header
+var cnlLog = []string{
+	"============= GPIO =============",
+	"------- GPIO Community 0 -------",
+	"------- GPIO Group GPP_A -------",
+	"0x0600: 0x0000001844000702 GPP_A0   RCIN#",
+	"0x0608: 0x0000301840000402 GPP_A1   LAD0",
+}
+
+func TestHardwareDetect(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   []string
+		platform string
+	}{
+		{"southbridge", []string{"Southbridge: 8086:9d84 (Intel(R) 300 Series Chipset)"},
+			"cnl"},
+		{"Sunrise Point-H", []string{"Southbridge: 8086:a145 (Intel(R) 100 Series Chipset)"},
+			"snr"},
+		{"Lewisburg", []string{"Southbridge: 8086:a1c1 (Intel(R) C620 Series Chipset)"},
+			"lbg"},
+		{"Alder Lake-S", []string{"Southbridge: 8086:7a84 (Intel(R) 600 Series Chipset)"},
+			"adl"},
+		{"Tiger Lake CPU", []string{"CPU: ID 0x806c1, Processor Type 0x0, Family 0x6, " +
+			"Model 0x8c, Stepping 0x1"}, "tgl"},
+		{"Apollo Lake CPU", []string{"CPU: ID 0x506c9, Processor Type 0x0, Family 0x6, " +
+			"Model 0x5c, Stepping 0x9"}, "apl"},
+		{"southbridge before CPU", []string{
+			"CPU: ID 0x806ea, Processor Type 0x0, Family 0x6, Model 0x8e, Stepping 0xa",
+			"Southbridge: 8086:9d84 (Intel(R) 300 Series Chipset)"}, "cnl"},
+		{"CPU with PCH", []string{"CPU: ID 0x806ea, Processor Type 0x0, Family 0x6, " +
+			"Model 0x8e, Stepping 0xa"}, ""},
+		{"unknown southbridge", []string{"Southbridge: 8086:1234 (Unknown)"}, ""},
+		{"other vendor", []string{"Southbridge: 1022:790e (FCH LPC Bridge)"}, ""},
+		{"no header", nil, ""},
+	}
+	for _, test := range tests {
+		device, found := hardwareDetect(append(test.header, cnlLog...))
+		if found != (test.platform != "") || device.platform != test.platform {
+			t.Errorf("%s: got %q %v, want %q", test.name, device.platform, found,
+				test.platform)
+		}
+	}
+
+	// the southbridge after the header is a part of the pad function
+	if device, found := hardwareDetect(append(cnlLog,
+		"Southbridge: 8086:9d84 (Intel(R) 300 Series Chipset)")); found {
+		t.Errorf("southbridge after the header: got %q", device.platform)
+	}
+}
+
+func TestPlatformDetect(t *testing.T) {
+	header := "Southbridge: 8086:9d84 (Intel(R) 300 Series Chipset)"
+	tests := []struct {
+		name     string
+		platform string
+		input    []string
+		detected string
+		message  string
+	}{
+		{"auto", "auto", append([]string{header}, cnlLog...), "cnl",
+			"Platform: cnl, detected from Cannon Point-LP PCH (8086:9d84)\n"},
+		{"auto without header", "auto", cnlLog, "snr",
+			"inteltool.log: info: unable to detect the platform from the inteltool " +
+				"log header, -p snr is used\n"},
+		{"-p cnl", "cnl", append([]string{header}, cnlLog...), "cnl", ""},
+		{"-p snr without header", "snr", cnlLog, "snr", ""},
+	}
+	for _, test := range tests {
+		parser, log, err := detectParse(t, test.platform, test.input...)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if parser.PlatformGet() != test.detected {
+			t.Errorf("%s: got %s, want %s", test.name, parser.PlatformGet(), test.detected)
+		}
+		if test.message != "" && !strings.Contains(log, test.message) {
+			t.Errorf("%s: no %q in\n%s", test.name, test.message, log)
+		}
+		if test.message == "" && strings.Contains(log, "Platform") {
+			t.Errorf("%s: unexpected message\n%s", test.name, log)
+		}
+	}
+
+	_, _, err := detectParse(t, "snr", append([]string{header}, cnlLog...)...)
+	if err == nil || err.Error() != "-p snr contradicts the hardware in the log: "+
+		"Cannon Point-LP PCH (8086:9d84), use -p cnl" {
+		t.Errorf("-p snr: got error %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// Diagnostic - problem found in the input file
+// Line     : line number in the input file, 0 if the problem is not bound to
+//            a line
+// Pad      : pad ID, empty if the problem is not bound to a pad
+// Severity : common.LintInfo, common.LintWarning or common.LintError
+// Message  : description of the problem
+type Diagnostic struct {
+	Line     int
+	Pad      string
+	Severity uint8
+	Message  string
+}
+
+// diagAdd - adds the problem found in the current line of the input file
+// severity : common.LintInfo, common.LintWarning or common.LintError
+// pad      : pad ID, empty if the problem is not bound to a pad
+// format   : message format as for fmt.Sprintf
+func (parser *ParserData) diagAdd(severity uint8, pad string, format string, args ...interface{}) {
+	parser.padDiagAdd(severity, &padInfo{id: pad, line: parser.lineNum}, format, args...)
+}
+
+// padDiagAdd - adds the problem found in the pad configuration
+// severity : common.LintInfo, common.LintWarning or common.LintError
+// pad      : pad info, the line of the pad in the input file is used
+// format   : message format as for fmt.Sprintf
+func (parser *ParserData) padDiagAdd(severity uint8, pad *padInfo, format string, args ...interface{}) {
+	parser.diags = append(parser.diags, Diagnostic{
+		Line:     pad.line,
+		Pad:      pad.id,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// DiagnosticsGet - returns the problems found in the input file
+func (parser *ParserData) DiagnosticsGet() []Diagnostic {
+	return parser.diags
+}
+
+// DiagnosticsFprint - print the problems found in the input file in the
+// compiler format, e.g.
+// gpio.h:12: error: GPP_A1: invalid pull value 20K_UP
+// w    : writer for the problems, usually stderr
+// name : name of the input file
+// return the number of warnings and errors
+func (parser *ParserData) DiagnosticsFprint(w io.Writer, name string) int {
+	var problems int
+	for _, diag := range parser.diags {
+		location := name
+		if diag.Line != 0 {
+			location += fmt.Sprintf(":%d", diag.Line)
+		}
+		message := diag.Message
+		if diag.Pad != "" {
+			message = diag.Pad + ": " + message
+		}
+		fmt.Fprintf(w, "%s: %s: %s\n", location, common.LintSeverityGet(diag.Severity), message)
+		if diag.Severity != common.LintInfo {
+			problems++
+		}
+	}
+	return problems
+}
+
+// padFieldsCheck - reports the values of the pad bit fields that are reserved
+// on the platform. The macro is generated with the raw value of such field.
+// pad : pad info
+func (parser *ParserData) padFieldsCheck(pad *padInfo) {
+	if pad.dw0 == 0xffffffff {
+		// reserved pad
+		return
+	}
+	dw0 := &common.Register{}
+	dw1 := &common.Register{}
+	dw0.ValueSet(pad.dw0)
+	dw1.ValueSet(pad.dw1)
+	if _, valid := common.RstCfgDecode(parser.platform, pad.id, dw0.GetResetConfig()); !valid {
+		parser.padDiagAdd(common.LintError, pad, "invalid pad reset config 0x%x",
+			dw0.GetResetConfig())
+	}
+	if _, valid := parser.platform.PullDecode(dw1.GetTermination()); !valid {
+		parser.padDiagAdd(common.LintError, pad, "invalid TERM value 0x%x",
+			dw1.GetTermination())
+	}
+	dw2 := &common.Register{}
+	dw2.ValueSet(pad.dw2)
+	if dw2.GetDebounceEnable() == 0 {
+		return
+	}
+	// GPIO_CONFIG and GPIO_CFG_DATA have no debounce settings
+	switch {
+	case parser.opts.IsFspStyleMacro():
+		parser.padDiagAdd(common.LintWarning, pad, "debounce is enabled (DEBOUNCE "+
+			"0x%x), it can not be set in GPIO_CONFIG and is dropped", dw2.GetDebounceDuration())
+	case parser.opts.IsSblStyleMacro():
+		parser.padDiagAdd(common.LintWarning, pad, "debounce is enabled (DEBOUNCE "+
+			"0x%x), it can not be set in GPIO_CFG_DATA and is dropped", dw2.GetDebounceDuration())
+	}
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+
+func TestDiagnosticsFprint(t *testing.T) {
+	tests := []struct {
+		name     string
+		template int
+		input    []string
+		problems int
+		want     string
+	}{
+		{"inteltool", config.TempInteltool, []string{
+			"============= GPIO =============",
+			"------- GPIO Group GPP_A -------",
+			"0x0400: 0x00000c1844000702 GPP_A0   RCIN#",
+			"0x0408: GPP_A1",
+			"0x0410: 0x0000001844000702 GPP_A2   LAD1",
+		}, 2, "gpio.h:3: error: GPP_A0: invalid TERM value 0x3\n" +
+			"gpio.h:4: error: pad is skipped, the line does not match the " +
+			"template (0)\n"},
+		{"gpio.h", config.TempGpioh, []string{
+			"static const struct pad_config gpio_table[] = {",
+			"\t/* PAD_CFG_NF(GPP_A0, NONE, DEEP, NF1), */",
+			"\t// PAD_CFG_NF(GPP_A0, NONE, DEEP, NF1),",
+			"\tPAD_CFG_NF(GPP_A1, 20K_UP, DEEP, NF1),",
+			"\tPAD_CFG_NF(GPP_A2, NONE, DEEP, NF1),",
+			"};",
+		}, 1, "gpio.h:4: error: pad is skipped: GPP_A1: invalid pull value 20K_UP\n"},
+		{"no problems", config.TempInteltool, []string{
+			"------- GPIO Group GPP_A -------",
+			"0x0400: 0x0000001844000702 GPP_A0   RCIN#",
+		}, 0, ""},
+	}
+	for _, test := range tests {
+		parser := parse(t, "snr", test.template, test.input...)
+		var buf bytes.Buffer
+		problems := parser.DiagnosticsFprint(&buf, "gpio.h")
+		if problems != test.problems {
+			t.Errorf("%s: got %d problems, want %d\n%s", test.name, problems,
+				test.problems, buf.String())
+		}
+		if buf.String() != test.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", test.name, buf.String(), test.want)
+		}
+	}
+}
+
+// TestDebounceCheck - the debounce of the pad is dropped by the FSP and SBL
+// bit fields styles
+func TestDebounceCheck(t *testing.T) {
+	input := strings.Join([]string{
+		"------- GPIO Group GPP_B -------",
+		"0x0700: 0x0000301844000702 0x0000000000000007 GPP_B0   CORE_VID0",
+		"0x0708: 0x0000301844000702 0x0000000000000000 GPP_B1   CORE_VID1",
+	}, "\n")
+	for style, want := range map[string]string{
+		"none": "",
+		"fsp": "gpio.h:2: warning: GPP_B0: debounce is enabled (DEBOUNCE 0x3), " +
+			"it can not be set in GPIO_CONFIG and is dropped\n",
+	} {
+		opts := &config.Options{}
+		opts.PlatformSet("tgl")
+		opts.FldStyleSet(style)
+		parser := NewParserData(opts, nil)
+		if err := parser.Parse(strings.NewReader(input)); err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		parser.DiagnosticsFprint(&buf, "gpio.h")
+		if buf.String() != want {
+			t.Errorf("%s: got %q, want %q", style, buf.String(), want)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// padMapGet - returns the map of pads from the pad info map with pad ID as a key.
+// Group and community titles are skipped.
+func (parser *ParserData) padMapGet() map[string]*padInfo {
+	pads := make(map[string]*padInfo)
+	for i := range parser.padmap {
+		if pad := &parser.padmap[i]; pad.id != "" {
+			pads[pad.id] = pad
+		}
+	}
+	return pads
+}
+
+// PadsNumGet - returns the number of pads in the pad info map, including
+// reserved pads. Group and community titles are not counted.
+func (parser *ParserData) PadsNumGet() int {
+	var num int
+	for i := range parser.padmap {
+		if parser.padmap[i].id != "" {
+			num++
+		}
+	}
+	return num
+}
+
+// padDiffFprint - print the difference between two pad configurations
+// w        : writer for the differences
+// platform : platform-specific interface to decode the field values
+// first    : pad info from the first file
+// second   : pad info from the second file
+// ro       : masks of read-only fields that should be ignored
+// return true if the configurations differ
+func padDiffFprint(w io.Writer, platform PlatformSpecific, first *padInfo, second *padInfo,
+		ro [common.MAX_DW_NUM]uint32) bool {
+	diffs := common.FieldsCompare(platform, first.id, first.dwGet(), second.dwGet(), ro)
+	var gpi []string
+	for _, reg := range common.GpiEnableRegs {
+		// the registers are compared only if both dumps contain them
+		value1, valid1 := first.gpi[reg]
+		value2, valid2 := second.gpi[reg]
+		if valid1 && valid2 && value1 != value2 {
+			gpi = append(gpi, fmt.Sprintf("\t%s: %d -> %d\n", reg, value1, value2))
+		}
+	}
+	lock := first.lock != second.lock && first.lock != common.PAD_LOCK_DEFAULT &&
+		second.lock != common.PAD_LOCK_DEFAULT
+	// the ownership is compared only if both dumps contain HOSTSW_OWN
+	ownership := first.ownership != second.ownership && first.hostsw && second.hostsw
+	if len(diffs) == 0 && len(gpi) == 0 && !lock && !ownership {
+		return false
+	}
+	fmt.Fprintf(w, "%s (%s / %s):\n", first.id, first.function, second.function)
+	for _, diff := range diffs {
+		fmt.Fprintf(w, "\tDW%d %s: %s -> %s\n", diff.Dw, diff.Name,
+			diff.Values[0], diff.Values[1])
+	}
+	if ownership {
+		fmt.Fprintf(w, "\tHOSTSW_OWN: %d -> %d\n", first.ownership, second.ownership)
+	}
+	for _, line := range gpi {
+		fmt.Fprint(w, line)
+	}
+	if lock {
+		fmt.Fprintf(w, "\tLOCK: %s -> %s\n", common.LockNameGet(first.lock),
+			common.LockNameGet(second.lock))
+	}
+	return true
+}
+
+// PadMapDiff - compares pads from two parsed inteltool logs of the same platform
+// and prints every pad whose configuration differs field by field
+// w      : writer for the differences
+// first  : parser data of the first file
+// second : parser data of the second file
+// return the number of different pads
+func PadMapDiff(w io.Writer, first *ParserData, second *ParserData) int {
+	var differences int
+	ro := first.readOnlyFieldsGet()
+	pads := second.padMapGet()
+	for i := range first.padmap {
+		pad := &first.padmap[i]
+		if pad.id == "" {
+			continue
+		}
+		other, valid := pads[pad.id]
+		if !valid {
+			fmt.Fprintf(w, "%s (%s): missing in the second file\n", pad.id, pad.function)
+			differences++
+			continue
+		}
+		delete(pads, pad.id)
+		if padDiffFprint(w, first.platform, pad, other, ro) {
+			differences++
+		}
+	}
+	// pads that are only in the second file, in the original order
+	for i := range second.padmap {
+		if pad := &second.padmap[i]; pad.id != "" {
+			if _, valid := pads[pad.id]; valid {
+				fmt.Fprintf(w, "%s (%s): missing in the first file\n", pad.id, pad.function)
+				differences++
+			}
+		}
+	}
+	return differences
+}
+
+// PadMapCompare - compares the pad configuration table from the board gpio.h
+// with the inteltool log from the same board. Prints pads that disagree with
+// the firmware state, pads that are missing from the table and pads that appear
+// in the table more than once.
+// w     : writer for the problems
+// table : parser data of the gpio.h file
+// dump  : parser data of the inteltool log
+// return the number of problems found
+func PadMapCompare(w io.Writer, table *ParserData, dump *ParserData) int {
+	var problems int
+	ro := dump.readOnlyFieldsGet()
+	pads := table.padMapGet()
+
+	fmt.Fprintf(w, "Pads that disagree with the inteltool log (log -> gpio.h):\n")
+	for i := range dump.padmap {
+		pad := &dump.padmap[i]
+		if other, valid := pads[pad.id]; pad.id != "" && valid {
+			if padDiffFprint(w, dump.platform, pad, other, ro) {
+				problems++
+			}
+		}
+	}
+
+	fmt.Fprintf(w, "Pads missing from the table:\n")
+	for i := range dump.padmap {
+		pad := &dump.padmap[i]
+		if _, valid := pads[pad.id]; pad.id != "" && !valid && pad.dw0 != 0xffffffff {
+			fmt.Fprintf(w, "\t%s (%s)\n", pad.id, pad.function)
+			problems++
+		}
+	}
+
+	fmt.Fprintf(w, "Pads that appear in the table more than once:\n")
+	count := make(map[string]int)
+	for i := range table.padmap {
+		if pad := &table.padmap[i]; pad.id != "" {
+			if count[pad.id]++; count[pad.id] == 2 {
+				fmt.Fprintf(w, "\t%s\n", pad.id)
+				problems++
+			}
+		}
+	}
+
+	fmt.Fprintf(w, "Pads not found in the inteltool log:\n")
+	logpads := dump.padMapGet()
+	for i := range table.padmap {
+		pad := &table.padmap[i]
+		if _, valid := logpads[pad.id]; pad.id != "" && !valid {
+			fmt.Fprintf(w, "\t%s\n", pad.id)
+			problems++
+		}
+	}
+	return problems
+}
@@ -0,0 +1,170 @@
+package parser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+
+func TestPadMapDiff(t *testing.T) {
+	first := parse(t, "snr", config.TempInteltool,
+		"============= GPIO =============",
+		"------- GPIO Group GPP_A -------",
+		"0x0400: 0x0000001844000702 GPP_A0   RCIN#",
+		"0x0408: 0x0000001844000400 GPP_A1   LAD0",
+		"0x0410: 0x0000001840880102 GPP_A2   GPIO",
+	)
+	tests := []struct {
+		name        string
+		pads        []string
+		differences int
+	}{
+		{"same", []string{
+			"0x0400: 0x0000001844000702 GPP_A0   RCIN#",
+			"0x0408: 0x0000001844000400 GPP_A1   LAD0",
+			"0x0410: 0x0000001840880102 GPP_A2   GPIO",
+		}, 0},
+		{"RX state is ignored", []string{
+			"0x0400: 0x0000001844000700 GPP_A0   RCIN#",
+			"0x0408: 0x0000001844000400 GPP_A1   LAD0",
+			"0x0410: 0x0000001840880100 GPP_A2   GPIO",
+		}, 0},
+		{"pad mode and termination", []string{
+			"0x0400: 0x0000001844000702 GPP_A0   RCIN#",
+			"0x0408: 0x0000301844000800 GPP_A1   LAD0",
+			"0x0410: 0x0000001840880102 GPP_A2   GPIO",
+		}, 1},
+		{"missing pads", []string{
+			"0x0400: 0x0000001844000702 GPP_A0   RCIN#",
+			"0x0408: 0x0000001844000400 GPP_A1   LAD0",
+			"0x0418: 0x0000001840880102 GPP_A3   GPIO",
+		}, 2},
+	}
+	for _, test := range tests {
+		input := []string{
+			"============= GPIO =============",
+			"------- GPIO Group GPP_A -------",
+		}
+		second := parse(t, "snr", config.TempInteltool, append(input, test.pads...)...)
+		var buf bytes.Buffer
+		if differences := PadMapDiff(&buf, first, second); differences != test.differences {
+			t.Errorf("%s: got %d different pads, want %d\n%s", test.name, differences,
+				test.differences, buf.String())
+		}
+	}
+}
+
+// TestPadMapDiffNames - the values are printed with the names from the decoder
+func TestPadMapDiffNames(t *testing.T) {
+	input := []string{
+		"============= GPIO =============",
+		"------- GPIO Group GPP_A -------",
+	}
+	first := parse(t, "snr", config.TempInteltool,
+		append(input, "0x0408: 0x0000001844000400 GPP_A1   LAD0")...)
+	second := parse(t, "snr", config.TempInteltool,
+		append(input, "0x0408: 0x0000301884000800 GPP_A1   LAD0")...)
+	var buf bytes.Buffer
+	PadMapDiff(&buf, first, second)
+	for _, want := range []string{
+		"\tDW0 PADRSTCFG: 0x1 (DEEP) -> 0x2 (PLTRST)\n",
+		"\tDW0 PMODE: 0x1 (NF1) -> 0x2 (NF2)\n",
+		"\tDW1 TERM: 0x0 (NONE) -> 0xc (20K_PU)\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("no %q in\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestPadMapCompare(t *testing.T) {
+	dump := parse(t, "snr", config.TempInteltool,
+		"============= GPIO =============",
+		"------- GPIO Group GPP_A -------",
+		"0x0408: 0x0000001840000400 GPP_A1   LAD0",
+		"0x0410: 0x0000001840880102 GPP_A2   GPIO",
+		"0x0418: 0x0000001884000201 GPP_A3   GPIO",
+		"0x0420: 0x00000000ffffffff GPP_A4   RESERVED",
+	)
+	tests := []struct {
+		name     string
+		table    []string
+		problems int
+	}{
+		{"same", []string{
+			"PAD_CFG_NF(GPP_A1, NONE, DEEP, NF1),",
+			"PAD_CFG_GPI_SCI(GPP_A2, NONE, DEEP, LEVEL, INVERT),",
+			"PAD_CFG_GPO(GPP_A3, 1, PLTRST),",
+		}, 0},
+		{"trigger", []string{
+			"PAD_CFG_NF(GPP_A1, NONE, DEEP, NF1),",
+			"PAD_CFG_GPI_SCI(GPP_A2, NONE, DEEP, EDGE_SINGLE, INVERT),",
+			"PAD_CFG_GPO(GPP_A3, 1, PLTRST),",
+		}, 1},
+		{"missing pad", []string{
+			"PAD_CFG_NF(GPP_A1, NONE, DEEP, NF1),",
+			"PAD_CFG_GPO(GPP_A3, 1, PLTRST),",
+		}, 1},
+		{"duplicate pad", []string{
+			"PAD_CFG_NF(GPP_A1, NONE, DEEP, NF1),",
+			"PAD_CFG_GPI_SCI(GPP_A2, NONE, DEEP, LEVEL, INVERT),",
+			"PAD_CFG_GPO(GPP_A3, 1, PLTRST),",
+			"PAD_CFG_GPO(GPP_A3, 1, PLTRST),",
+		}, 1},
+		{"pad not in the log", []string{
+			"PAD_CFG_NF(GPP_A1, NONE, DEEP, NF1),",
+			"PAD_CFG_GPI_SCI(GPP_A2, NONE, DEEP, LEVEL, INVERT),",
+			"PAD_CFG_GPO(GPP_A3, 1, PLTRST),",
+			"PAD_CFG_GPO(GPP_A5, 1, PLTRST),",
+		}, 1},
+		{"board gpio.h", []string{
+			"#define EC_SCI_GPI GPP_A2",
+			"static const struct pad_config gpio_table[] = {",
+			"\tPAD_CFG_NF(GPP_A1, NONE, DEEP, NF1),",
+			"\tPAD_CFG_GPI_SCI(GPP_A2, NONE, DEEP, LEVEL, INVERT),",
+			"\tPAD_CFG_GPO(GPP_A3, 1, PLTRST),",
+			"};",
+			"static const struct gpio_lock_config gpio_lock_table[] = {",
+			"\t{ GPP_A1, GPIO_LOCK_FULL },",
+			"};",
+			"/* GPE0 routing from MISCCFG, devicetree.cb:",
+			"\tregister \"gpe0_dw0\" = \"GPP_A\"",
+			"*/",
+			"\tgpio_output(GPP_A3, 1);",
+		}, 0},
+	}
+	for _, test := range tests {
+		table := parse(t, "snr", config.TempGpioh, test.table...)
+		var buf bytes.Buffer
+		if problems := PadMapCompare(&buf, table, dump); problems != test.problems {
+			t.Errorf("%s: got %d problems, want %d\n%s", test.name, problems,
+				test.problems, buf.String())
+		}
+		if diags := table.DiagnosticsGet(); len(diags) != 0 {
+			t.Errorf("%s: got diagnostics %v", test.name, diags)
+		}
+	}
+
+	// the lines of the pads are listed in the sections of the problems
+	table := parse(t, "snr", config.TempGpioh,
+		"PAD_CFG_NF(GPP_A1, NONE, DEEP, NF1),",
+		"PAD_CFG_NF(GPP_A1, NONE, DEEP, NF1),",
+		"PAD_CFG_GPO(GPP_A3, 1, PLTRST),",
+		"PAD_CFG_GPO(GPP_A5, 1, PLTRST),",
+	)
+	var buf bytes.Buffer
+	if problems := PadMapCompare(&buf, table, dump); problems != 3 {
+		t.Errorf("got %d problems, want 3", problems)
+	}
+	for _, want := range []string{
+		"Pads missing from the table:\n\tGPP_A2 (GPIO)\n",
+		"Pads that appear in the table more than once:\n\tGPP_A1\n",
+		"Pads not found in the inteltool log:\n\tGPP_A5\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("no %q in\n%s", want, buf.String())
+		}
+	}
+}
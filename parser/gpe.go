@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// Fields of the MISCCFG register with the group numbers routed to the GPE0
+// DW0/DW1/DW2 blocks, see MISCCFG_GPE0_DWx_SHIFT in coreboot
+const (
+	miscCfgGpe0Shift = 8
+	miscCfgGpe0Mask  = 0xf
+	gpe0DwNum        = 3
+	gpe0DwPads       = 32
+)
+
+// gpe0Extract - extracts the GPE0 routing from the MISCCFG register, e.g.
+// 0x0010: 0x00076c00 (MISCCFG)
+// coreboot sets the same routing in every community, so only the first
+// register is used
+// return true if the line contains the MISCCFG register
+func (parser *ParserData) gpe0Extract() bool {
+	status, _, _, value := parser.Register("(MISCCFG")
+	if !status {
+		return false
+	}
+	var gpe0 = make([]uint8, gpe0DwNum)
+	for i := range gpe0 {
+		gpe0[i] = uint8(value>>uint(miscCfgGpe0Shift+4*i)) & miscCfgGpe0Mask
+	}
+	if parser.gpe0 == nil {
+		parser.gpe0 = gpe0
+		return true
+	}
+	for i := range gpe0 {
+		if gpe0[i] != parser.gpe0[i] {
+			parser.diagAdd(common.LintWarning, "", "MISCCFG GPE0_DW%d differs between "+
+				"communities: 0x%x and 0x%x, the first one is used", i, parser.gpe0[i], gpe0[i])
+		}
+	}
+	return true
+}
+
+// gpe0GroupGet - returns the name of the group routed to GPE0_DWx
+// dw : GPE0 block number
+func (parser *ParserData) gpe0GroupGet(dw int) string {
+	if status, group := parser.platform.GpeGroupGet(parser.gpe0[dw]); status {
+		return group
+	}
+	return fmt.Sprintf("0x%x", parser.gpe0[dw])
+}
+
+// padGpeGet - returns the GPE that the SCI-routed pad raises and the ACPI
+// method that handles it, e.g. GPE0 0x2C (_L2C)
+// pad : pad info
+// return
+//     GPE string, empty if the pad is not SCI-routed or there is no MISCCFG
+//     register in the inteltool log
+//     false if the pad is SCI-routed, but its group is not routed to GPE0
+func (parser *ParserData) padGpeGet(pad *padInfo) (string, bool) {
+	dw0 := &common.Register{}
+	dw0.ValueSet(pad.dw0)
+	if parser.gpe0 == nil || dw0.GetPadMode() != 0 || dw0.GetGPIOInputRouteSCI() == 0 {
+		return "", true
+	}
+	status, group, number := parser.padNumberGet(pad.id)
+	if !status {
+		return "", true
+	}
+	for dw := range parser.gpe0 {
+		if parser.gpe0GroupGet(dw) != group {
+			continue
+		}
+		gpe := dw*gpe0DwPads + int(number)
+		switch dw0.GetRXLevelEdgeConfiguration() {
+		case common.TRIG_LEVEL:
+			return fmt.Sprintf("GPE0 0x%02X (_L%02X)", gpe, gpe), true
+		case common.TRIG_EDGE_SINGLE, common.TRIG_EDGE_BOTH:
+			return fmt.Sprintf("GPE0 0x%02X (_E%02X)", gpe, gpe), true
+		}
+		return fmt.Sprintf("GPE0 0x%02X", gpe), true
+	}
+	return "no GPE0, " + group + " is not in GPE0_DW0/1/2", false
+}
+
+// padGpeSet - annotates the SCI-routed pads with the GPE numbers and warns
+// about the pads whose group is not routed to GPE0
+func (parser *ParserData) padGpeSet() {
+	for i := range parser.padmap {
+		pad := &parser.padmap[i]
+		if pad.id == "" || pad.dw0 == 0xffffffff {
+			continue
+		}
+		var routed bool
+		if pad.gpe, routed = parser.padGpeGet(pad); !routed {
+			parser.padDiagAdd(common.LintWarning, pad, "SCI-routed, but %s", pad.gpe)
+		}
+	}
+}
+
+// Gpe0Fprint - print the GPE0 routing from the MISCCFG register to file as the
+// coreboot devicetree settings. Nothing is printed if there is no MISCCFG
+// register in the inteltool log.
+// w : writer for the generated file
+func (parser *ParserData) Gpe0Fprint(w io.Writer) {
+	if parser.gpe0 == nil {
+		return
+	}
+	// Tiger Lake and newer SoCs set the GPE0 routing in the common PMC block
+	name := "gpe0_dw"
+	if parser.opts.IsPlatformTiger() || parser.opts.IsPlatformAlder() {
+		name = "pmc_gpe0_dw"
+	}
+	fmt.Fprint(w, "\n/* GPE0 routing from MISCCFG, devicetree.cb:\n")
+	for dw := range parser.gpe0 {
+		fmt.Fprintf(w, "\tregister \"%s%d\" = \"%s\"\n", name, dw, parser.gpe0GroupGet(dw))
+	}
+	fmt.Fprint(w, "*/\n")
+}
@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// gpeParse - parses the Sunrise Point inteltool log and returns the parser
+// messages followed by the diagnostics
+// input : lines of the inteltool log
+func gpeParse(t *testing.T, input ...string) (*ParserData, string) {
+	opts := &config.Options{}
+	opts.PlatformSet("snr")
+	var log bytes.Buffer
+	parser := NewParserData(opts, &log)
+	if err := parser.Parse(strings.NewReader(strings.Join(input, "\n"))); err != nil {
+		t.Fatal(err)
+	}
+	parser.DiagnosticsFprint(&log, "inteltool.log")
+	return parser, log.String()
+}
+
+// gpeLog - Sunrise Point inteltool log with the MISCCFG register
+// misccfg : MISCCFG register line
+func gpeLog(misccfg ...string) []string {
+	log := []string{
+		"============= GPIO =============",
+		"------- GPIO Community 0 -------",
+	}
+	log = append(log, misccfg...)
+	return append(log,
+		"------- GPIO Group GPP_A -------",
+		"0x0418: 0x0000001842880102 GPP_A3   GPIO",
+		"0x0420: 0x0000001840880102 GPP_A4   GPIO",
+		"0x0428: 0x0000001844000702 GPP_A5   LFRAME#",
+		"------- GPIO Group GPP_B -------",
+		"0x0528: 0x0000001840880102 GPP_B5   GPIO",
+		"0x0530: 0x0000001844880102 GPP_B6   GPIO",
+		"------- GPIO Group GPP_D -------",
+		"0x0618: 0x0000001840880102 GPP_D3   GPIO",
+	)
+}
+
+func TestPadGpe(t *testing.T) {
+	tests := []struct {
+		misccfg string
+		pads    map[string]string
+		notgpe  []string
+	}{
+		{"0x0010: 0x00021000 (MISCCFG)",
+			map[string]string{
+				"GPP_A3": "GPE0 0x03 (_E03)",
+				"GPP_A4": "GPE0 0x04 (_L04)",
+				"GPP_A5": "",
+				"GPP_B5": "GPE0 0x25 (_L25)",
+				"GPP_B6": "GPE0 0x26",
+				"GPP_D3": "no GPE0, GPP_D is not in GPE0_DW0/1/2",
+			},
+			[]string{"GPP_D3"}},
+		{"0x0010: 0x00032100 (MISCCFG)",
+			map[string]string{
+				"GPP_A3": "no GPE0, GPP_A is not in GPE0_DW0/1/2",
+				"GPP_B5": "GPE0 0x05 (_L05)",
+				"GPP_D3": "GPE0 0x43 (_L43)",
+			},
+			[]string{"GPP_A3", "GPP_A4"}},
+		{"", map[string]string{"GPP_A3": "", "GPP_D3": ""}, nil},
+	}
+	for _, test := range tests {
+		parser, _ := gpeParse(t, gpeLog(test.misccfg)...)
+		for id, gpe := range test.pads {
+			if pad := padFind(parser, id); pad == nil || pad.gpe != gpe {
+				t.Errorf("%s: %s: got %v, want %q", test.misccfg, id, pad, gpe)
+			}
+		}
+		var warned []string
+		for _, diag := range parser.DiagnosticsGet() {
+			if diag.Severity == common.LintWarning {
+				warned = append(warned, diag.Pad)
+			}
+		}
+		if strings.Join(warned, ",") != strings.Join(test.notgpe, ",") {
+			t.Errorf("%s: warnings for %v, want %v", test.misccfg, warned, test.notgpe)
+		}
+	}
+}
+
+func TestGpe0Fprint(t *testing.T) {
+	parser := parse(t, "snr", config.TempInteltool,
+		gpeLog("0x0010: 0x00021000 (MISCCFG)", "0x0010: 0x00021000 (MISCCFG)")...)
+	var buf bytes.Buffer
+	parser.Gpe0Fprint(&buf)
+	want := "\n/* GPE0 routing from MISCCFG, devicetree.cb:\n" +
+		"\tregister \"gpe0_dw0\" = \"GPP_A\"\n" +
+		"\tregister \"gpe0_dw1\" = \"GPP_B\"\n" +
+		"\tregister \"gpe0_dw2\" = \"GPP_C\"\n" +
+		"*/\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	// the first register is used if the communities have different routing
+	parser, log := gpeParse(t,
+		gpeLog("0x0010: 0x00021000 (MISCCFG)", "0x0010: 0x00032100 (MISCCFG)")...)
+	if parser.gpe0GroupGet(0) != "GPP_A" {
+		t.Errorf("got %s for GPE0_DW0, want GPP_A", parser.gpe0GroupGet(0))
+	}
+	if !strings.Contains(log, "inteltool.log:4: warning: MISCCFG GPE0_DW0 differs between communities") {
+		t.Errorf("no warning about the different routing:\n%s", log)
+	}
+
+	parser = parse(t, "snr", config.TempInteltool, gpeLog()...)
+	buf.Reset()
+	parser.Gpe0Fprint(&buf)
+	if buf.Len() != 0 {
+		t.Errorf("no MISCCFG: got %q", buf.String())
+	}
+}
@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// Names of the group registers with one bit per pad: host software ownership
+// and pad configuration lock
+const (
+	hostSwOwn    = "HOSTSW_OWN"
+	padCfgLock   = "PADCFGLOCK"
+	padCfgLockTx = "PADCFGLOCKTX"
+)
+
+// groupRegNames - group registers with one bit per pad from the inteltool log
+var groupRegNames = append([]string{hostSwOwn, padCfgLock, padCfgLockTx},
+	common.GpiRegs...)
+
+// groupRegsExtract - extracts the group register from the inteltool log, e.g.
+// 0x0100: 0x00000000 (GPI_IS_GPP_A)
+// return true if the line contains a group register
+func (parser *ParserData) groupRegsExtract() bool {
+	for _, reg := range groupRegNames {
+		status, name, offset, value := parser.Register(reg + "_")
+		if !status {
+			continue
+		}
+		if _, valid := parser.groupregs[reg]; !valid {
+			parser.groupregs[reg] = make(map[string]uint32)
+		}
+		_, group := parser.platform.GroupNameExtract(strings.TrimPrefix(name, reg))
+		parser.groupregs[reg][group] = value
+		fmt.Fprintf(parser.log, "\n\t/* groupRegsExtract: [offset 0x%x] %s = 0x%x */\n",
+			offset, name, value)
+		return true
+	}
+	return false
+}
+
+// groupBitGet - returns the bit of the group register for the pad
+// reg : group register name
+// id  : pad ID string
+// return
+//     bit value
+//     true if the register was found in the inteltool log
+func (parser *ParserData) groupBitGet(reg string, id string) (uint8, bool) {
+	status, group, number := parser.padNumberGet(id)
+	value, valid := parser.groupregs[reg][group]
+	if !status || !valid {
+		return 0, false
+	}
+	return uint8(value>>number) & 0x1, true
+}
+
+// padNumberGet - returns the group and the number of the pad in the group
+// id : pad ID string
+// return
+//     true if the pad ID contains a group identifier and a valid number
+//     group identifier
+//     pad number, i.e. bit number in the group registers
+func (parser *ParserData) padNumberGet(id string) (bool, string, uint) {
+	status, group := parser.platform.GroupNameExtract(id)
+	if !status {
+		return false, "", 0
+	}
+	// GPP_A12 -> 12, vGPIO_3 -> 3
+	number, err := strconv.Atoi(strings.TrimPrefix(strings.TrimPrefix(id, group), "_"))
+	if err != nil || number < 0 || number > 31 {
+		return false, "", 0
+	}
+	return true, group, uint(number)
+}
+
+// padGroupBitsSet - attaches the bits of the group registers to the pad and
+// warns if the interrupt routes of the pad contradict the GPI enable bits
+// pad : pad info
+func (parser *ParserData) padGroupBitsSet(pad *padInfo) {
+	if ownership, valid := parser.groupBitGet(hostSwOwn, pad.id); valid {
+		pad.ownership, pad.hostsw = ownership, true
+	}
+	pad.lock = parser.padLockGet(pad.id)
+	for _, reg := range common.GpiRegs {
+		if bit, valid := parser.groupBitGet(reg, pad.id); valid {
+			if pad.gpi == nil {
+				pad.gpi = make(map[string]uint8)
+			}
+			pad.gpi[reg] = bit
+		}
+	}
+	if pad.dw0 == 0xffffffff {
+		// reserved pad
+		return
+	}
+	for _, problem := range common.GpiRouteCheck(pad.dw0, pad.gpi, pad.ownership) {
+		parser.padDiagAdd(common.LintWarning, pad, "%s", problem)
+	}
+}
+
+// padLockGet - returns the pad lock state from the PADCFGLOCK and PADCFGLOCKTX
+// registers, PAD_LOCK_DEFAULT if there are no such registers in the dump
+// id : pad ID string
+func (parser *ParserData) padLockGet(id string) uint8 {
+	config, cfgValid := parser.groupBitGet(padCfgLock, id)
+	tx, txValid := parser.groupBitGet(padCfgLockTx, id)
+	if !cfgValid && !txValid {
+		return common.PAD_LOCK_DEFAULT
+	}
+	if lock := config*common.PAD_LOCK_CONFIG | tx*common.PAD_LOCK_TX; lock != 0 {
+		return lock
+	}
+	return common.PAD_UNLOCK
+}
+
+// gpiBitsGet - returns the names of the GPI group registers whose bits are set
+// for the pad, e.g. GPI_IE | GPI_SMI_EN
+func (info *padInfo) gpiBitsGet() string {
+	var regs []string
+	for _, reg := range common.GpiRegs {
+		if info.gpi[reg] != 0 {
+			regs = append(regs, reg)
+		}
+	}
+	return strings.Join(regs, " | ")
+}
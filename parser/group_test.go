@@ -0,0 +1,315 @@
+package parser
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// groupLog - Sunrise Point inteltool log with the group registers of GPP_A
+// regs : group register lines
+func groupLog(regs ...string) []string {
+	log := []string{
+		"============= GPIO =============",
+		"------- GPIO Community 0 -------",
+	}
+	log = append(log, regs...)
+	return append(log,
+		"------- GPIO Group GPP_A -------",
+		"0x0400: 0x0000001840880100 GPP_A0   GPIO",
+		"0x0408: 0x0000001840000100 GPP_A1   GPIO",
+		"0x0410: 0x00000000ffffffff GPP_A2   RESERVED",
+	)
+}
+
+// padFind - returns the pad with the specified id
+func padFind(parser *ParserData, id string) *padInfo {
+	for i := range parser.padmap {
+		if parser.padmap[i].id == id {
+			return &parser.padmap[i]
+		}
+	}
+	return nil
+}
+
+func TestGroupRegsExtract(t *testing.T) {
+	parser := parse(t, "snr", config.TempInteltool, groupLog(
+		"0x00d0: 0x00000002 (HOSTSW_OWN_GPP_A)",
+		"0x0100: 0x00000002 (GPI_IS_GPP_A)",
+		"0x0120: 0x00000002 (GPI_IE_GPP_A)",
+		"0x0140: 0x00000000 (GPI_GPE_STS_GPP_A)",
+		"0x0160: 0x00000001 (GPI_GPE_EN_GPP_A)",
+		"0x01a0: 0x00000000 (PAD_OWN_GPP_A)",
+	)...)
+	if count := parser.PadsNumGet(); count != 3 {
+		t.Fatalf("got %d pads, want 3: the group registers are taken as pads", count)
+	}
+	tests := []struct {
+		id        string
+		ownership uint8
+		gpi       map[string]uint8
+	}{
+		{"GPP_A0", common.PAD_OWN_ACPI, map[string]uint8{common.GpiIs: 0, common.GpiIe: 0,
+			common.GpiGpeSts: 0, common.GpiGpeEn: 1}},
+		{"GPP_A1", common.PAD_OWN_DRIVER, map[string]uint8{common.GpiIs: 1,
+			common.GpiIe: 1, common.GpiGpeSts: 0, common.GpiGpeEn: 0}},
+	}
+	for _, test := range tests {
+		pad := padFind(parser, test.id)
+		if pad == nil {
+			t.Errorf("%s: not found", test.id)
+			continue
+		}
+		if pad.ownership != test.ownership || !reflect.DeepEqual(pad.gpi, test.gpi) {
+			t.Errorf("%s: got %d %v, want %d %v", test.id, pad.ownership, pad.gpi,
+				test.ownership, test.gpi)
+		}
+	}
+	if gpi := padFind(parser, "GPP_A1").gpiBitsGet(); gpi != "GPI_IS | GPI_IE" {
+		t.Errorf("GPP_A1: got %s, want GPI_IS | GPI_IE", gpi)
+	}
+
+	// the dump without the group registers
+	parser = parse(t, "snr", config.TempInteltool, groupLog()...)
+	if pad := padFind(parser, "GPP_A1"); pad.gpi != nil || pad.ownership != common.PAD_OWN_ACPI {
+		t.Errorf("GPP_A1: got %d %v without the group registers", pad.ownership, pad.gpi)
+	}
+}
+
+// TestGroupRouteWarnings - the interrupt routes that contradict the GPI enable
+// bits are reported in the diagnostics
+func TestGroupRouteWarnings(t *testing.T) {
+	opts := &config.Options{}
+	opts.PlatformSet("snr")
+	parser := NewParserData(opts, nil)
+	input := strings.Join(groupLog("0x0160: 0x00000002 (GPI_GPE_EN_GPP_A)"), "\n")
+	if err := parser.Parse(strings.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+	var log bytes.Buffer
+	parser.DiagnosticsFprint(&log, "inteltool.log")
+	for _, want := range []string{
+		"warning: GPP_A0: SCI-routed, but GPI_GPE_EN is clear\n",
+		"warning: GPP_A1: GPI_GPE_EN is set, but the pad is not SCI-routed\n",
+	} {
+		if !strings.Contains(log.String(), want) {
+			t.Errorf("no %q in\n%s", want, log.String())
+		}
+	}
+	if strings.Contains(log.String(), "GPP_A2") {
+		t.Errorf("reserved pad is checked\n%s", log.String())
+	}
+}
+
+// TestGroupRegsDiff - the GPI enable bits are compared only if both dumps
+// contain them, the status bits are not compared
+func TestGroupRegsDiff(t *testing.T) {
+	first := parse(t, "snr", config.TempInteltool, groupLog(
+		"0x0100: 0x00000000 (GPI_IS_GPP_A)",
+		"0x0160: 0x00000001 (GPI_GPE_EN_GPP_A)",
+	)...)
+	tests := []struct {
+		name        string
+		regs        []string
+		differences int
+	}{
+		{"same", []string{"0x0160: 0x00000001 (GPI_GPE_EN_GPP_A)"}, 0},
+		{"status", []string{"0x0100: 0x00000003 (GPI_IS_GPP_A)",
+			"0x0160: 0x00000001 (GPI_GPE_EN_GPP_A)"}, 0},
+		{"no registers", nil, 0},
+		{"GPE_EN", []string{"0x0160: 0x00000003 (GPI_GPE_EN_GPP_A)"}, 1},
+	}
+	for _, test := range tests {
+		second := parse(t, "snr", config.TempInteltool, groupLog(test.regs...)...)
+		var buf bytes.Buffer
+		if differences := PadMapDiff(&buf, first, second); differences != test.differences {
+			t.Errorf("%s: got %d different pads, want %d\n%s", test.name, differences,
+				test.differences, buf.String())
+		}
+	}
+}
+
+// TestOwnershipDiff - the ownership is compared only if both dumps contain the
+// HOSTSW_OWN registers
+func TestOwnershipDiff(t *testing.T) {
+	first := parse(t, "snr", config.TempInteltool,
+		groupLog("0x00d0: 0x00000002 (HOSTSW_OWN_GPP_A)")...)
+	tests := []struct {
+		name        string
+		regs        []string
+		differences int
+	}{
+		{"same", []string{"0x00d0: 0x00000002 (HOSTSW_OWN_GPP_A)"}, 0},
+		{"no registers", nil, 0},
+		{"HOSTSW_OWN", []string{"0x00d0: 0x00000000 (HOSTSW_OWN_GPP_A)"}, 1},
+	}
+	for _, test := range tests {
+		second := parse(t, "snr", config.TempInteltool, groupLog(test.regs...)...)
+		var buf bytes.Buffer
+		if differences := PadMapDiff(&buf, first, second); differences != test.differences {
+			t.Errorf("%s: got %d different pads, want %d\n%s", test.name, differences,
+				test.differences, buf.String())
+		}
+	}
+
+	// the macros always set the ownership, the log without HOSTSW_OWN does not
+	var buf bytes.Buffer
+	if err := first.GpioHFprint(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "PAD_CFG_GPI_TRIG_OWN(GPP_A1, NONE, DEEP, LEVEL, DRIVER)") {
+		t.Fatalf("GPP_A1 is not driver-owned\n%s", buf.String())
+	}
+	table := parse(t, "snr", config.TempGpioh, strings.Split(buf.String(), "\n")...)
+	dump := parse(t, "snr", config.TempInteltool, groupLog()...)
+	buf.Reset()
+	if problems := PadMapCompare(&buf, table, dump); problems != 0 {
+		t.Errorf("got %d problems without HOSTSW_OWN, want 0\n%s", problems, buf.String())
+	}
+}
+
+// lockLog - Sunrise Point inteltool log with the lock registers of GPP_A
+// regs : lock register lines
+func lockLog(regs ...string) []string {
+	log := append(groupLog(regs...), "0x0418: 0x0000001844000400 GPP_A3   LAD0")
+	return append(log, "0x0420: 0x0000001884000201 GPP_A4   GPIO")
+}
+
+func TestPadLockGet(t *testing.T) {
+	parser := parse(t, "snr", config.TempInteltool, lockLog(
+		"0x00a0: 0x0000000d (PADCFGLOCK_GPP_A)",
+		"0x00a4: 0x0000000a (PADCFGLOCKTX_GPP_A)",
+	)...)
+	for id, lock := range map[string]uint8{
+		"GPP_A0": common.PAD_LOCK_CONFIG,
+		"GPP_A1": common.PAD_LOCK_TX,
+		"GPP_A3": common.PAD_LOCK_FULL,
+		"GPP_A4": common.PAD_UNLOCK,
+	} {
+		if pad := padFind(parser, id); pad.lock != lock {
+			t.Errorf("%s: got lock %d, want %d", id, pad.lock, lock)
+		}
+	}
+
+	parser = parse(t, "snr", config.TempInteltool, lockLog()...)
+	if pad := padFind(parser, "GPP_A3"); pad.lock != common.PAD_LOCK_DEFAULT {
+		t.Errorf("GPP_A3: got lock %d without the lock registers", pad.lock)
+	}
+}
+
+func TestPadLockFprint(t *testing.T) {
+	regs := []string{
+		"0x00a0: 0x00000009 (PADCFGLOCK_GPP_A)",
+		"0x00a4: 0x00000008 (PADCFGLOCKTX_GPP_A)",
+	}
+	tests := []struct {
+		style string
+		regs  []string
+		want  []string
+		skip  []string
+	}{
+		{"none", regs, []string{
+			"static const struct gpio_lock_config gpio_lock_table[] = {\n" +
+				"\t{ GPP_A0, GPIO_LOCK_CONFIG },\n" +
+				"\t{ GPP_A3, GPIO_LOCK_FULL },\n" +
+				"};\n",
+		}, []string{"GPP_A1, GPIO_LOCK", "GPP_A2, GPIO_LOCK", "GPP_A4, GPIO_LOCK"}},
+		{"none", nil, nil, []string{"gpio_lock_table"}},
+		{"fsp", regs, []string{
+			"GpioPadConfigLock | GpioOutputStateUnlock } },\t/* GPIO */",
+			"GpioTermNone,  GpioPadLock } },\t/* LAD0 (LAD2) */",
+			"GpioPadUnlock } },\t/* GPIO */",
+		}, []string{"gpio_lock_table"}},
+	}
+	for _, test := range tests {
+		parser := parse(t, "snr", config.TempInteltool, lockLog(test.regs...)...)
+		parser.opts.FldStyleSet(test.style)
+		var buf bytes.Buffer
+		if err := parser.GpioHFprint(&buf); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range test.want {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("%s: no %q in\n%s", test.style, want, buf.String())
+			}
+		}
+		for _, skip := range test.skip {
+			if strings.Contains(buf.String(), skip) {
+				t.Errorf("%s: %q in\n%s", test.style, skip, buf.String())
+			}
+		}
+	}
+}
+
+// TestPadLockRoundTrip - the lock table generated after the pad configuration
+// is parsed back from gpio.h
+func TestPadLockRoundTrip(t *testing.T) {
+	log := parse(t, "snr", config.TempInteltool, lockLog(
+		"0x00a0: 0x0000000d (PADCFGLOCK_GPP_A)",
+		"0x00a4: 0x0000000a (PADCFGLOCKTX_GPP_A)",
+	)...)
+	var buf bytes.Buffer
+	if err := log.GpioHFprint(&buf); err != nil {
+		t.Fatal(err)
+	}
+	gpioh := parse(t, "snr", config.TempGpioh, strings.Split(buf.String(), "\n")...)
+	if diags := gpioh.DiagnosticsGet(); len(diags) != 0 {
+		t.Errorf("got diagnostics %v\n%s", diags, buf.String())
+	}
+	for id, lock := range map[string]uint8{
+		"GPP_A0": common.PAD_LOCK_CONFIG,
+		"GPP_A1": common.PAD_LOCK_TX,
+		"GPP_A3": common.PAD_LOCK_FULL,
+		"GPP_A4": common.PAD_LOCK_DEFAULT,
+	} {
+		if pad := padFind(gpioh, id); pad == nil || pad.lock != lock {
+			t.Errorf("%s: got %v, want lock %d", id, pad, lock)
+		}
+	}
+
+	gpioh = parse(t, "snr", config.TempGpioh,
+		"PAD_CFG_NF(GPP_A1, NONE, DEEP, NF1),",
+		"{ GPP_A1, GPIO_LOCK_ALL },",
+		"{ GPP_A2, GPIO_LOCK_FULL },",
+	)
+	var lines []int
+	for _, diag := range gpioh.DiagnosticsGet() {
+		lines = append(lines, diag.Line)
+	}
+	if !reflect.DeepEqual(lines, []int{2, 3}) {
+		t.Errorf("got diagnostics %v, want the errors in lines 2 and 3",
+			gpioh.DiagnosticsGet())
+	}
+}
+
+// TestPadLockDiff - the lock state is compared only if both dumps contain the
+// lock registers
+func TestPadLockDiff(t *testing.T) {
+	first := parse(t, "snr", config.TempInteltool, lockLog(
+		"0x00a0: 0x00000008 (PADCFGLOCK_GPP_A)",
+		"0x00a4: 0x00000008 (PADCFGLOCKTX_GPP_A)",
+	)...)
+	tests := []struct {
+		name        string
+		regs        []string
+		differences int
+	}{
+		{"no lock registers", nil, 0},
+		{"same", []string{"0x00a0: 0x00000008 (PADCFGLOCK_GPP_A)",
+			"0x00a4: 0x00000008 (PADCFGLOCKTX_GPP_A)"}, 0},
+		{"TX unlocked", []string{"0x00a0: 0x00000008 (PADCFGLOCK_GPP_A)",
+			"0x00a4: 0x00000000 (PADCFGLOCKTX_GPP_A)"}, 1},
+	}
+	for _, test := range tests {
+		second := parse(t, "snr", config.TempInteltool, lockLog(test.regs...)...)
+		var buf bytes.Buffer
+		if differences := PadMapDiff(&buf, first, second); differences != test.differences {
+			t.Errorf("%s: got %d different pads, want %d\n%s", test.name, differences,
+				test.differences, buf.String())
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package parser
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/fields/fsp"
+import "review.coreboot.org/coreboot.git/util/intelp2m/fields/sbl"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// imageTableMin - minimal number of the entries in the GPIO table candidate,
+// the shorter sequences of the valid entries are often random data
+const imageTableMin = 8
+
+// Formats of the GPIO tables in the firmware image
+const (
+	imageFspFormat = "GPIO_INIT_CONFIG"
+	imageSblFormat = "GPIO_CFG_DATA"
+)
+
+// imageTable - GPIO table candidate found in the firmware image
+// offset  : offset of the table in the image
+// format  : imageFspFormat or imageSblFormat
+// entries : number of the entries in the table
+// pads    : decoded pads, the entries with unknown pad ID are not here
+// score   : plausibility of the table
+type imageTable struct {
+	offset  int
+	format  string
+	entries int
+	pads    []padInfo
+	score   int
+}
+
+// imageEntry - decodes the table entry from the firmware image
+// data : entry data
+// return
+//     pad info, nil if the entry is valid but has no pad
+//     value that is the same for all entries of the table
+//     false if the data is not the table entry
+type imageEntry func(data []byte) (*padInfo, uint32, bool)
+
+// imagePadGet - converts the binary GPIO_CONFIG into the pad configuration
+// id  : pad ID, empty if it is unknown
+// cfg : GPIO_CONFIG fields
+// return
+//     pad info, the pad ID is empty if it is unknown
+//     false if the GPIO_CONFIG values are not valid
+func (parser *ParserData) imagePadGet(id string, cfg fsp.Config) (*padInfo, bool) {
+	names, valid := fsp.ConfigNamesGet(cfg)
+	if !valid {
+		return nil, false
+	}
+	pad := &padInfo{}
+	if id == "" {
+		return pad, true
+	}
+	encoded, lock, err := fsp.ConfigEncode(parser.platform, id, names)
+	if err != nil {
+		return nil, false
+	}
+	pad.id = id
+	pad.dw0 = encoded.Register(common.PAD_CFG_DW0).ValueGet()
+	pad.dw1 = encoded.Register(common.PAD_CFG_DW1).ValueGet()
+	pad.ownership, pad.hostsw = encoded.Ownership, true
+	pad.lock = lock
+	if encoded.Register(common.PAD_CFG_DW0).GetPadMode() == 0 {
+		// the native functions get the names from the platform table
+		pad.function = "GPIO"
+	}
+	return pad, true
+}
+
+// fspEntryGet - decodes the GPIO_INIT_CONFIG entry of FSP/edk2: GPIO_PAD and
+// GPIO_CONFIG. GPIO_PAD contains the chipset ID, the group index and the pad
+// number. The group index is the same as the GPE group number of the platform.
+// data : 12 bytes of the entry
+// return the same as imageEntry
+func (parser *ParserData) fspEntryGet(data []byte) (*padInfo, uint32, bool) {
+	gpioPad := binary.LittleEndian.Uint32(data)
+	dw0 := binary.LittleEndian.Uint32(data[4:])
+	dw1 := binary.LittleEndian.Uint32(data[8:])
+	chipset, group, number := gpioPad>>24, (gpioPad>>16)&0xff, gpioPad&0xffff
+	if chipset == 0 || chipset > 0x1f || group > 0x1f || number > 31 || dw0&0x1f == 0 {
+		return nil, 0, false
+	}
+	cfg, valid := fsp.ConfigUnpack(dw0, dw1)
+	if !valid {
+		return nil, 0, false
+	}
+	var id string
+	if known, name := parser.platform.GpeGroupGet(uint8(group)); known {
+		id = fmt.Sprintf("%s%d", name, number)
+	}
+	pad, valid := parser.imagePadGet(id, cfg)
+	return pad, chipset, valid
+}
+
+// sblEntryGet - decodes the GPIO_CFG_DATA item of Slim Bootloader, the hidden
+// items are skipped
+// data : 8 bytes of the item
+// return the same as imageEntry
+func (parser *ParserData) sblEntryGet(data []byte) (*padInfo, uint32, bool) {
+	dw0 := binary.LittleEndian.Uint32(data)
+	dw1 := binary.LittleEndian.Uint32(data[4:])
+	cfg, group, number, hidden, valid := sbl.ItemUnpack(dw0, dw1)
+	if !valid || dw0&0x1f == 0 {
+		return nil, 0, false
+	}
+	id, _ := sbl.PadNameGet(parser.opts, group, number)
+	pad, valid := parser.imagePadGet(id, cfg)
+	if hidden {
+		return nil, 0, valid
+	}
+	return pad, 0, valid
+}
+
+// imageTablesFind - finds the sequences of the valid table entries in the
+// firmware image. The tables are aligned to 4 bytes.
+// data   : firmware image
+// format : imageFspFormat or imageSblFormat
+// size   : size of the table entry
+// entry  : entry decoder
+// return the table candidates
+func imageTablesFind(data []byte, format string, size int, entry imageEntry) []imageTable {
+	var tables []imageTable
+	for offset := 0; offset+size <= len(data); offset += 4 {
+		table := imageTable{offset: offset, format: format}
+		var first uint32
+		for end := offset; end+size <= len(data); end += size {
+			pad, tag, valid := entry(data[end : end+size])
+			if !valid || end != offset && tag != first {
+				break
+			}
+			first = tag
+			table.entries++
+			if pad != nil {
+				table.pads = append(table.pads, *pad)
+			}
+		}
+		if table.entries < imageTableMin {
+			continue
+		}
+		table.scoreSet()
+		tables = append(tables, table)
+		// the next table can not start inside this one
+		offset += table.entries*size - 4
+	}
+	return tables
+}
+
+// scoreSet - sets the plausibility of the table: the number of the different
+// pads minus the number of the entries that repeat a pad or have an unknown
+// pad ID
+func (table *imageTable) scoreSet() {
+	ids := make(map[string]bool)
+	var known []padInfo
+	for _, pad := range table.pads {
+		switch {
+		case pad.id == "":
+			table.score--
+		case ids[pad.id]:
+			table.score--
+		default:
+			ids[pad.id] = true
+			known = append(known, pad)
+			table.score++
+		}
+	}
+	table.pads = known
+}
+
+// imageParse - finds the GPIO tables in the firmware image, e.g. the SPI flash
+// dump, and adds the pads from the most plausible one (or the one selected
+// with -table) to the pad info map
+// r : reader of the firmware image
+// return error
+func (parser *ParserData) imageParse(r io.Reader) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if parser.opts.IsPlatformAuto() {
+		// the pad IDs are decoded with the group numbers of the platform
+		return fmt.Errorf("-p is required for -t %d", config.TempImage)
+	}
+	parser.PlatformSpecificInterfaceSet()
+	parser.groupregs = make(map[string]map[string]uint32)
+	parser.gpe0 = nil
+
+	tables := imageTablesFind(data, imageFspFormat, 12, parser.fspEntryGet)
+	tables = append(tables, imageTablesFind(data, imageSblFormat, 8, parser.sblEntryGet)...)
+	sort.SliceStable(tables, func(i, j int) bool {
+		return tables[i].score > tables[j].score
+	})
+	// the tables without known pads are random data
+	for len(tables) != 0 && tables[len(tables)-1].score <= 0 {
+		tables = tables[:len(tables)-1]
+	}
+	if len(tables) == 0 {
+		return fmt.Errorf("no GPIO tables of -p %s found in the firmware image",
+			parser.opts.PlatformNameGet())
+	}
+	// the candidates are printed with the diagnostics to stderr, since the
+	// parser messages go to stdout together with the generated file if -o is
+	// not set
+	for i, table := range tables {
+		parser.diagAdd(common.LintInfo, "", "GPIO table candidate %d: 0x%08x %s, "+
+			"%d entries, %d pads, score %d", i+1, table.offset, table.format,
+			table.entries, len(table.pads), table.score)
+	}
+	number := parser.opts.ImageTableGet()
+	if number > len(tables) {
+		return fmt.Errorf("-table %d: only %d GPIO table candidates found", number, len(tables))
+	}
+	table := tables[number-1]
+	parser.diagAdd(common.LintInfo, "", "GPIO table %d is used", number)
+
+	parser.padmap = append(parser.padmap, padInfo{
+		function: fmt.Sprintf("%s at 0x%08x", table.format, table.offset),
+	})
+	for i := range table.pads {
+		pad := &table.pads[i]
+		parser.padFieldsCheck(pad)
+		parser.padNativeFunctionSet(pad)
+		parser.padmap = append(parser.padmap, *pad)
+	}
+	fmt.Fprintln(parser.log, "...done!")
+	return nil
+}
@@ -0,0 +1,150 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// Binary GPIO_CONFIG of the GPIO output: GpioPadModeGpio, GpioHostOwnAcpi,
+// GpioDirOut, GpioOutHigh, GpioIntDis | GpioIntLvlEdgDis, GpioPlatformReset,
+// GpioTermNone
+const (
+	imageTestDw0 = 0x1 | 0x1<<5 | 0x05<<7 | 0x3<<13 | (0x1|0x5<<5)<<15 | 0x05<<24
+	imageTestDw1 = 0x01
+)
+
+// imageFspTable - returns the GPIO_INIT_CONFIG table with the pads of the
+// group, the pad numbers are taken from the list
+func imageFspTable(group uint32, numbers ...uint32) []byte {
+	var buf bytes.Buffer
+	for _, number := range numbers {
+		// chipset ID 3, group index, pad number
+		binary.Write(&buf, binary.LittleEndian, []uint32{0x03<<24 | group<<16 | number,
+			imageTestDw0, imageTestDw1})
+	}
+	return buf.Bytes()
+}
+
+// imageSblTable - returns the GPIO_CFG_DATA table with the pads of the group,
+// the pad numbers are taken from the list, the pad number 31 is hidden
+func imageSblTable(group uint32, numbers ...uint32) []byte {
+	var buf bytes.Buffer
+	for _, number := range numbers {
+		dw1 := uint32(imageTestDw1) | 0x1<<7 | number<<16 | group<<24
+		if number == 31 {
+			dw1 |= 1 << 31
+		}
+		binary.Write(&buf, binary.LittleEndian, []uint32{imageTestDw0, dw1})
+	}
+	return buf.Bytes()
+}
+
+// imageParseTest - parses the firmware image
+// platform : platform name for the -p option
+// table    : table number for the -table option, 0 for the default
+func imageParseTest(platform string, table int, image []byte) (*ParserData, error) {
+	opts := &config.Options{}
+	opts.PlatformSet(platform)
+	opts.TemplateSet(config.TempImage)
+	opts.FldStyleSet("none")
+	if table != 0 {
+		opts.ImageTableSet(table)
+	}
+	parser := NewParserData(opts, nil)
+	return parser, parser.Parse(bytes.NewReader(image))
+}
+
+func TestImageParse(t *testing.T) {
+	var image []byte
+	image = append(image, make([]byte, 16)...)
+	image = append(image, imageFspTable(0, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9)...)
+	image = append(image, make([]byte, 20)...)
+	// two pads are repeated, the score is lower
+	image = append(image, imageFspTable(1, 0, 1, 2, 3, 4, 5, 0, 1)...)
+	image = append(image, make([]byte, 8)...)
+	// the shorter sequence is random data
+	image = append(image, imageFspTable(1, 10, 11, 12)...)
+
+	tests := []struct {
+		table int
+		first string
+		pads  int
+	}{
+		{0, "GPP_A0", 10},
+		{1, "GPP_A0", 10},
+		{2, "GPP_B0", 6},
+	}
+	for _, test := range tests {
+		parser, err := imageParseTest("cnl", test.table, image)
+		if err != nil {
+			t.Errorf("-table %d: unexpected error: %v", test.table, err)
+			continue
+		}
+		// the title and the pads
+		if len(parser.padmap) != test.pads+1 || parser.padmap[1].id != test.first {
+			t.Errorf("-table %d: got %d entries", test.table, len(parser.padmap))
+			continue
+		}
+		macro := parser.genMacro(&parser.padmap[1])
+		if want := "PAD_CFG_GPO(" + test.first + ", 1, PLTRST),"; macro != want {
+			t.Errorf("-table %d: got %s, want %s", test.table, macro, want)
+		}
+	}
+
+	parser, _ := imageParseTest("cnl", 0, image)
+	var candidates []string
+	for _, diag := range parser.DiagnosticsGet() {
+		if diag.Severity != common.LintInfo {
+			t.Errorf("unexpected problem: %s", diag.Message)
+		}
+		candidates = append(candidates, diag.Message)
+	}
+	want := []string{
+		fmt.Sprintf("GPIO table candidate 1: 0x%08x GPIO_INIT_CONFIG, 10 entries, "+
+			"10 pads, score 10", 16),
+		fmt.Sprintf("GPIO table candidate 2: 0x%08x GPIO_INIT_CONFIG, 8 entries, "+
+			"6 pads, score 4", 16+120+20),
+		"GPIO table 1 is used",
+	}
+	if strings.Join(candidates, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got candidates\n%s\nwant\n%s", strings.Join(candidates, "\n"),
+			strings.Join(want, "\n"))
+	}
+
+	if _, err := imageParseTest("cnl", 3, image); err == nil ||
+		err.Error() != "-table 3: only 2 GPIO table candidates found" {
+		t.Errorf("-table 3: got error %v", err)
+	}
+	if _, err := imageParseTest("auto", 0, image); err == nil ||
+		err.Error() != "-p is required for -t 5" {
+		t.Errorf("-p auto: got error %v", err)
+	}
+	if _, err := imageParseTest("cnl", 0, make([]byte, 256)); err == nil {
+		t.Errorf("no tables: no error")
+	}
+}
+
+func TestImageParseSbl(t *testing.T) {
+	image := append(make([]byte, 4), imageSblTable(1, 0, 1, 2, 31, 3, 4, 5, 6)...)
+	parser, err := imageParseTest("snr", 0, image)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []string
+	for _, pad := range parser.padmap[1:] {
+		ids = append(ids, pad.id)
+	}
+	// the hidden item is skipped
+	if strings.Join(ids, " ") != "GPP_B0 GPP_B1 GPP_B2 GPP_B3 GPP_B4 GPP_B5 GPP_B6" {
+		t.Errorf("got pads %v", ids)
+	}
+	if !strings.Contains(parser.padmap[0].function, "GPIO_CFG_DATA at 0x00000004") {
+		t.Errorf("got title %q", parser.padmap[0].function)
+	}
+}
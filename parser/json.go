@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// Pad - decoded pad configuration
+// Id        : pad id string
+// Group     : pad group, e.g. GPP_A
+// Community : GPIO community title from the inteltool log
+// Function  : pad function from the inteltool log or from the comment
+// DW        : raw DW0-DW3 register values, DW3 has no configuration fields and
+//             is always 0
+// HasDW2    : true if the platform has the DW2 register
+// Ownership : host software ownership, ACPI or DRIVER
+// Gpi       : GPI group registers bits, nil if they are not in the dump
+// Lock      : pad lock state, CONFIG, TX, FULL, UNLOCK or empty if unknown
+// Gpe       : GPE raised by the SCI-routed pad, e.g. GPE0 0x2C (_L2C)
+// Nf        : signal name of the native function from the platform table
+// Fields    : decoded bit fields, the values are strings
+// Raw       : raw values of the bit fields
+// Macro     : generated macro
+type Pad struct {
+	Id        string
+	Group     string
+	Community string
+	Function  string
+	DW        [common.MAX_DW_NUM]uint32
+	HasDW2    bool
+	Ownership string
+	Gpi       map[string]uint8
+	Lock      string
+	Gpe       string
+	Nf        string
+	Fields    map[string]string
+	Raw       map[string]uint8
+	Macro     string
+}
+
+// padJson - pad configuration in the JSON output
+type padJson struct {
+	Id        string            `json:"id"`
+	Group     string            `json:"group,omitempty"`
+	Community string            `json:"community,omitempty"`
+	Function  string            `json:"function"`
+	DW0       string            `json:"dw0"`
+	DW1       string            `json:"dw1"`
+	DW2       string            `json:"dw2,omitempty"`
+	Ownership string            `json:"ownership"`
+	Gpi       map[string]uint8  `json:"gpi,omitempty"`
+	Lock      string            `json:"lock,omitempty"`
+	Gpe       string            `json:"gpe,omitempty"`
+	Nf        string            `json:"nf,omitempty"`
+	Fields    map[string]string `json:"fields"`
+	Raw       map[string]uint8  `json:"raw"`
+	Macro     string            `json:"macro"`
+}
+
+// MarshalJSON - DW0-DW2 are printed as hex strings, DW2 only if the platform
+// has it
+func (pad Pad) MarshalJSON() ([]byte, error) {
+	info := padJson{
+		Id:        pad.Id,
+		Group:     pad.Group,
+		Community: pad.Community,
+		Function:  pad.Function,
+		DW0:       fmt.Sprintf("0x%0.8x", pad.DW[common.PAD_CFG_DW0]),
+		DW1:       fmt.Sprintf("0x%0.8x", pad.DW[common.PAD_CFG_DW1]),
+		Ownership: pad.Ownership,
+		Gpi:       pad.Gpi,
+		Lock:      pad.Lock,
+		Gpe:       pad.Gpe,
+		Nf:        pad.Nf,
+		Fields:    pad.Fields,
+		Raw:       pad.Raw,
+		Macro:     pad.Macro,
+	}
+	if pad.HasDW2 {
+		info.DW2 = fmt.Sprintf("0x%0.8x", pad.DW[common.PAD_CFG_DW2])
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	// do not escape the shift operators in the macros
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(info); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// titleGet - returns the title without decorations
+// ------- GPIO Community 0 ------- -> GPIO Community 0
+func titleGet(line string) string {
+	return strings.TrimSpace(strings.Trim(strings.TrimSpace(line), "-"))
+}
+
+// padGet - returns the decoded pad configuration
+// pad       : pad info
+// community : GPIO community title
+func (parser *ParserData) padGet(pad *padInfo, community string) Pad {
+	var ro [common.MAX_DW_NUM]uint32
+	for i := range ro {
+		ro[i] = parser.platform.ReadOnlyFieldsGet(uint8(i))
+	}
+	_, group := parser.platform.GroupNameExtract(pad.id)
+	info := Pad{
+		Id:        pad.id,
+		Group:     group,
+		Community: community,
+		Function:  pad.function,
+		DW:        pad.dwGet(),
+		HasDW2:    ro[common.PAD_CFG_DW2] != common.AllFields,
+		Ownership: "ACPI",
+		Gpi:       pad.gpi,
+		Lock:      common.LockNameGet(pad.lock),
+		Gpe:       pad.gpe,
+		Nf:        pad.nf,
+		Fields:    common.FieldsDecode(parser.platform, pad.id, pad.dwGet(), ro),
+		Raw:       common.FieldsRawGet(pad.dwGet(), ro),
+		Macro:     strings.TrimSpace(parser.genMacro(pad)),
+	}
+	if pad.ownership == common.PAD_OWN_DRIVER {
+		info.Ownership = "DRIVER"
+	}
+	return info
+}
+
+// PadsGet - returns the decoded configuration of all pads. Group titles and
+// reserved pads are skipped.
+func (parser *ParserData) PadsGet() []Pad {
+	pads := []Pad{}
+	var community string
+	for i := range parser.padmap {
+		pad := &parser.padmap[i]
+		switch {
+		case pad.id == "":
+			if strings.Contains(pad.function, "GPIO Community") {
+				community = titleGet(pad.function)
+			}
+		case pad.dw0 == 0xffffffff:
+			// reserved pad
+		default:
+			pads = append(pads, parser.padGet(pad, community))
+		}
+	}
+	return pads
+}
+
+// PadMapJsonFprint - print pad info map to file as a JSON array with one
+// object per pad. Group titles and reserved pads are skipped.
+// w : writer for the generated file
+// return error
+func (parser *ParserData) PadMapJsonFprint(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	// do not escape the shift operators in the macros
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(parser.PadsGet())
+}
@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// LintRulesFprint - prints the catalogue of the lint rules
+// w : writer for the catalogue
+func LintRulesFprint(w io.Writer) error {
+	table := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, rule := range common.LintRules {
+		fmt.Fprintf(table, "\t%s\t%s\t%s\n", rule.Id,
+			common.LintSeverityGet(rule.Severity), rule.Desc)
+	}
+	return table.Flush()
+}
+
+// PadMapLint - checks the pads with the lint rules and prints the problems.
+// Group titles and reserved pads are skipped, as well as the rules suppressed
+// in the settings.
+// w : writer for the problems
+// return the number of errors and warnings
+func (parser *ParserData) PadMapLint(w io.Writer) int {
+	var count [common.LintError + 1]int
+	ro := parser.readOnlyFieldsGet()
+	for i := range parser.padmap {
+		pad := &parser.padmap[i]
+		if pad.id == "" || pad.dw0 == 0xffffffff {
+			continue
+		}
+		for _, rule := range common.Lint(parser.platform, pad.id, pad.dwGet(), ro,
+			pad.ownership, pad.gpi) {
+			if parser.opts.IsLintRuleSuppressed(rule.Id, pad.id) {
+				continue
+			}
+			name := pad.id
+			if pad.function != "" {
+				name += " (" + pad.function + ")"
+			}
+			fmt.Fprintf(w, "%s: %s [%s] %s\n", name,
+				common.LintSeverityGet(rule.Severity), rule.Id, rule.Desc)
+			count[rule.Severity]++
+		}
+	}
+	fmt.Fprintf(w, "%d errors, %d warnings, %d info\n", count[common.LintError],
+		count[common.LintWarning], count[common.LintInfo])
+	return count[common.LintError] + count[common.LintWarning]
+}
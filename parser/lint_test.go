@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+
+func TestPadMapLint(t *testing.T) {
+	tests := []struct {
+		suppress string
+		count    int
+		problems []string
+	}{
+		{"", 2, []string{
+			"GPP_A1 (GPIO): warning [gpo-rx-route]",
+			"GPP_A2 (GPIO): warning [edge-no-route]",
+			"0 errors, 2 warnings, 0 info",
+		}},
+		{"gpo-rx-route", 1, []string{"0 errors, 1 warnings, 0 info"}},
+		{"gpo-rx-route:GPP_A1,edge-no-route:GPP_A1", 1, []string{
+			"GPP_A2 (GPIO): warning [edge-no-route]",
+		}},
+	}
+	for _, test := range tests {
+		parser := parse(t, "snr", config.TempInteltool,
+			"------- GPIO Group GPP_A -------",
+			"0x0400: 0x0000001840000400 GPP_A0   RCIN#",
+			"0x0408: 0x0000001884080201 GPP_A1   GPIO",
+			"0x0410: 0x0000001882000100 GPP_A2   GPIO",
+			"0x0418: 0x00000000ffffffff GPP_A3   RESERVED",
+		)
+		parser.opts.LintSuppressSet(test.suppress)
+		var buf bytes.Buffer
+		if count := parser.PadMapLint(&buf); count != test.count {
+			t.Errorf("%q: got %d problems, want %d\n%s", test.suppress, count, test.count,
+				buf.String())
+		}
+		for _, problem := range test.problems {
+			if !strings.Contains(buf.String(), problem) {
+				t.Errorf("%q: no %q in\n%s", test.suppress, problem, buf.String())
+			}
+		}
+		if strings.Contains(buf.String(), "GPP_A1") && strings.Contains(test.suppress,
+			"GPP_A1") {
+			t.Errorf("%q: GPP_A1 is reported\n%s", test.suppress, buf.String())
+		}
+	}
+}
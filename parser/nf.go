@@ -0,0 +1,37 @@
+package parser
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// padNativeFunctionSet - sets the signal name of the pad native function from
+// the platform table and warns if the pad mode has no function
+// pad : pad info
+func (parser *ParserData) padNativeFunctionSet(pad *padInfo) {
+	if pad.dw0 == 0xffffffff {
+		// reserved pad
+		return
+	}
+	dw0 := &common.Register{}
+	mode := dw0.ValueSet(pad.dw0).GetPadMode()
+	if mode == 0 {
+		return
+	}
+	known, name := parser.platform.NativeFunctionGet(pad.id, mode)
+	if known && name == "" {
+		parser.padDiagAdd(common.LintWarning, pad, "pad mode NF%d has no function on this platform",
+			mode)
+	}
+	pad.nf = name
+}
+
+// functionGet - returns the pad function for the comments. The signal name
+// of the native function is added if it differs from the function in the
+// inteltool log or in the gpio.h comment, e.g. TOUCHPAD_INT (UART0_RXD)
+func (info *padInfo) functionGet() string {
+	switch {
+	case info.nf == "" || info.nf == info.function:
+		return info.function
+	case info.function == "":
+		return info.nf
+	}
+	return info.function + " (" + info.nf + ")"
+}
@@ -0,0 +1,37 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPadNativeFunctionSet(t *testing.T) {
+	parser, log := gpeParse(t,
+		"------- GPIO Group GPP_A -------",
+		"0x0400: 0x0000001844000400 GPP_A0   RCIN#",
+		"0x0408: 0x0000001844000800 GPP_A1   TOUCHPAD_INT",
+		"0x0410: 0x0000001844000c00 GPP_A2   GPIO",
+		"0x0420: 0x0000001844000100 GPP_A4   GPIO",
+	)
+	tests := []struct {
+		id       string
+		nf       string
+		function string
+	}{
+		{"GPP_A0", "RCIN#", "RCIN#"},
+		{"GPP_A1", "ESPI_IO0", "TOUCHPAD_INT (ESPI_IO0)"},
+		{"GPP_A2", "", "GPIO"},
+		{"GPP_A4", "", "GPIO"},
+	}
+	for _, test := range tests {
+		pad := padFind(parser, test.id)
+		if pad.nf != test.nf || pad.functionGet() != test.function {
+			t.Errorf("%s: got %q %q, want %q %q", test.id, pad.nf, pad.functionGet(),
+				test.nf, test.function)
+		}
+	}
+	want := "inteltool.log:4: warning: GPP_A2: pad mode NF3 has no function on this platform\n"
+	if !strings.Contains(log, want) || strings.Count(log, "warning") != 1 {
+		t.Errorf("got messages\n%s\nwant %q", log, want)
+	}
+}
@@ -3,20 +3,30 @@ package parser
 import (
 	"bufio"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"strings"
-	"strconv"
 )
 
-import "../platforms/snr"
-import "../platforms/lbg"
-import "../platforms/apl"
-import "../config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/snr"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/lbg"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/apl"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/cnl"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/tgl"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/adl"
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
 
 // PlatformSpecific - platform-specific interface
 type PlatformSpecific interface {
-	GenMacro(id string, dw0 uint32, dw1 uint32, ownership uint8) string
+	GenMacro(id string, dw0 uint32, dw1 uint32, dw2 uint32, ownership uint8,
+		lock uint8, opts *config.Options) string
 	GroupNameExtract(line string) (bool, string)
+	GpeGroupGet(number uint8) (bool, string)
 	KeywordCheck(line string) bool
+	ReadOnlyFieldsGet(number uint8) uint32
+	AcpiPinGet(pad common.PadPosition) (string, string, bool)
+	common.EncoderSpecific
 }
 
 // padInfo - information about pad
@@ -25,109 +35,172 @@ type PlatformSpecific interface {
 // function  : the string that means the pad function
 // dw0       : DW0 register value
 // dw1       : DW1 register value
+// dw2       : DW2 register value (Tiger Lake and newer)
 // ownership : host software ownership
+// hostsw    : true if the ownership is known, i.e. the dump contains the HOSTSW_OWN
+//             register or the ownership is set by the pad configuration macro
+// gpi       : GPI group registers bits, nil if they are not in the dump
+// lock      : pad lock state from the PADCFGLOCK/PADCFGLOCKTX registers
+// gpe       : GPE raised by the SCI-routed pad, see MISCCFG register
+// intsel    : interrupt line from DW1 (INTSEL), only for the inteltool log
+// nf        : signal name of the native function from the platform table
+// line      : line number of the pad in the input file
 type padInfo struct {
 	id        string
 	offset    uint16
 	function  string
 	dw0       uint32
 	dw1       uint32
+	dw2       uint32
 	ownership uint8
+	hostsw    bool
+	gpi       map[string]uint8
+	lock      uint8
+	gpe       string
+	intsel    uint8
+	nf        string
+	line      int
 }
 
-// generate - wrapper for Fprintf(). Writes text to the file specified
-// in config.OutputGenFile
-func (info *padInfo) generate(lvl uint8, line string, a ...interface{}) {
-	if config.InfoLevelGet() >= lvl {
-		fmt.Fprintf(config.OutputGenFile, line, a...)
+// dwGet - returns the values of all configuration registers of the pad, DW3
+// has no configuration fields and is always 0
+func (info *padInfo) dwGet() [common.MAX_DW_NUM]uint32 {
+	return [common.MAX_DW_NUM]uint32{info.dw0, info.dw1, info.dw2, 0}
+}
+
+// generator - output of the pad configuration
+// w         : writer for the generated file
+// infolevel : information level in the comments
+type generator struct {
+	w         io.Writer
+	infolevel uint8
+}
+
+// generate - wrapper for Fprintf(). Writes text to the generated file if the
+// information level is not less than lvl
+func (gen *generator) generate(lvl uint8, line string, a ...interface{}) {
+	if gen.infolevel >= lvl {
+		fmt.Fprintf(gen.w, line, a...)
 	}
 }
 
 // titleFprint - print GPIO group title to file
 // /* ------- GPIO Group GPP_L ------- */
-func (info *padInfo) titleFprint() {
-	info.generate(0, "\n\t/* %s */\n", info.function)
+func (info *padInfo) titleFprint(gen *generator) {
+	gen.generate(0, "\n\t/* %s */\n", info.function)
 }
 
 // reservedFprint - print reserved GPIO to file as comment
 // /* GPP_H17 - RESERVED */
-func (info *padInfo) reservedFprint() {
-	info.generate(2, "\n")
+func (info *padInfo) reservedFprint(gen *generator) {
+	gen.generate(2, "\n")
 	// small comment about reserved port
-	info.generate(0, "\t/* %s - %s */\n", info.id, info.function)
+	gen.generate(0, "\t/* %s - %s */\n", info.id, info.function)
 }
 
 // padInfoMacroFprint - print information about current pad to file using
 // special macros:
 // PAD_CFG_NF(GPP_F1, 20K_PU, PLTRST, NF1), /* SATAXPCIE4 */
-// gpio  : gpio.c file descriptor
+// gen   : output of the generated file
 // macro : string of the generated macro
-func (info *padInfo) padInfoMacroFprint(macro string) {
-	info.generate(2, "\n")
-	info.generate(1, "\t/* %s - %s ", info.id, info.function)
-	info.generate(2, "DW0: 0x%0.8x, DW1: 0x%0.8x ", info.dw0, info.dw1)
-	info.generate(1, "*/\n")
-	info.generate(0, "\t%s", macro)
-	if config.InfoLevelGet() == 0 {
-		info.generate(0, "\t/* %s */", info.function)
+func (info *padInfo) padInfoMacroFprint(gen *generator, macro string) {
+	gen.generate(2, "\n")
+	gen.generate(1, "\t/* %s - %s ", info.id, info.functionGet())
+	gen.generate(2, "DW0: 0x%0.8x, DW1: 0x%0.8x ", info.dw0, info.dw1)
+	if info.dw2 != 0 {
+		gen.generate(2, "DW2: 0x%0.8x ", info.dw2)
+	}
+	if gpi := info.gpiBitsGet(); gpi != "" {
+		gen.generate(2, "%s ", gpi)
 	}
-	info.generate(0, "\n")
+	if info.lock != common.PAD_LOCK_DEFAULT {
+		gen.generate(2, "LOCK: %s ", common.LockNameGet(info.lock))
+	}
+	if info.gpe != "" {
+		gen.generate(1, "%s ", info.gpe)
+	}
+	gen.generate(1, "*/\n")
+	gen.generate(0, "\t%s", macro)
+	if gen.infolevel == 0 && info.gpe != "" {
+		gen.generate(0, "\t/* %s, %s */", info.functionGet(), info.gpe)
+	} else if gen.infolevel == 0 {
+		gen.generate(0, "\t/* %s */", info.functionGet())
+	}
+	gen.generate(0, "\n")
 }
 
-// ParserData - global data
+// ParserData - parser data
+// opts       : converter settings
+// log        : writer for the parser messages
 // line       : string from the configuration file
+// lineNum    : number of the line in the configuration file
 // padmap     : pad info map
-// RawFmt     : flag for generating pads config file with DW0/1 reg raw values
-// Template   : structure template type of ConfigFile
+// groupregs  : group registers (HOSTSW_OWN, GPI_*) for each group
+// gpe0       : groups routed to GPE0_DW0/1/2 from MISCCFG, nil if unknown
+// diags      : problems found in the configuration file
 type ParserData struct {
+	opts       *config.Options
+	log        io.Writer
 	platform   PlatformSpecific
 	line       string
+	lineNum    int
 	padmap     []padInfo
-	ownership  map[string]uint32
-}
-
-// hostOwnershipGet - get the host software ownership value for the corresponding
-// pad ID
-// id : pad ID string
-// return the host software ownership form the parser struct
-func (parser *ParserData) hostOwnershipGet(id string) uint8 {
-	var ownership uint8 = 0
-	status, group := parser.platform.GroupNameExtract(id)
-	if config.TemplateGet() == config.TempInteltool && status {
-		numder, _ := strconv.Atoi(strings.TrimLeft(id, group))
-		if (parser.ownership[group] & (1 << uint8(numder))) != 0 {
-			ownership = 1
-		}
+	groupregs  map[string]map[string]uint32
+	gpe0       []uint8
+	diags      []Diagnostic
+}
+
+// NewParserData - creates the parser data with the specified settings. Nothing
+// is shared between the parsers, so they can be used at the same time.
+// opts : converter settings
+// log  : writer for the parser messages, nil to discard them
+func NewParserData(opts *config.Options, log io.Writer) *ParserData {
+	if log == nil {
+		log = ioutil.Discard
 	}
-	return ownership
+	return &ParserData{opts: opts, log: log}
+}
+
+// genMacro - generates the macro for the pad with the parser settings
+func (parser *ParserData) genMacro(pad *padInfo) string {
+	return parser.platform.GenMacro(pad.id, pad.dw0, pad.dw1, pad.dw2, pad.ownership,
+		pad.lock, parser.opts)
 }
 
 // padInfoExtract - adds a new entry to pad info map
 // return error status
 func (parser *ParserData) padInfoExtract() int {
-	var function, id string
-	var dw0, dw1 uint32
 	var template = map[int]template{
 		config.TempInteltool: useInteltoolLogTemplate,
-		config.TempGpioh    : useGpioHTemplate,
+		config.TempGpioh    : parser.useGpioHTemplate,
 		config.TempSpec     : useYourTemplate,
+		config.TempFsp      : parser.useFspTemplate,
+		config.TempPinctrl  : usePinctrlTemplate,
 	}
-	if template[config.TemplateGet()](parser.line, &function, &id, &dw0, &dw1) == 0 {
-		pad := padInfo{id: id,
-			function: function,
-			dw0: dw0,
-			dw1: dw1,
-			ownership: parser.hostOwnershipGet(id)}
+	pad := padInfo{line: parser.lineNum}
+	if template[parser.opts.TemplateGet()](parser.line, &pad) == 0 {
+		if parser.opts.TemplateGet() == config.TempInteltool {
+			parser.padGroupBitsSet(&pad)
+		}
+		parser.padFieldsCheck(&pad)
+		parser.padNativeFunctionSet(&pad)
 		parser.padmap = append(parser.padmap, pad)
 		return 0
 	}
-	fmt.Printf("This template (%d) does not match!\n", config.TemplateGet())
+	if parser.opts.TemplateGet() != config.TempGpioh && parser.opts.TemplateGet() != config.TempFsp {
+		// the gpio.h and FSP templates report the problem with the pad themselves
+		parser.diagAdd(common.LintError, "", "pad is skipped, the line does not match "+
+			"the template (%d)", parser.opts.TemplateGet())
+	}
 	return -1
 }
 
 // communityGroupExtract
 func (parser *ParserData) communityGroupExtract() {
-	pad := padInfo{function: parser.line}
+	// gpio.h contains the titles in the comments
+	title := strings.TrimSpace(parser.line)
+	title = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(title, "/*"), "*/"))
+	pad := padInfo{function: title}
 	parser.padmap = append(parser.padmap, pad)
 }
 
@@ -138,26 +211,170 @@ func (parser *ParserData) PlatformSpecificInterfaceSet() {
 		config.SunriseType   : snr.PlatformSpecific{},
 		// See platforms/lbg/macro.go
 		config.LewisburgType : lbg.PlatformSpecific{
+			InheritanceMacro    : snr.PlatformSpecific{},
 			InheritanceTemplate : snr.PlatformSpecific{},
 		},
 		config.ApolloType    : apl.PlatformSpecific{},
+		// See platforms/cnl/macro.go
+		config.CannonType    : cnl.PlatformSpecific{
+			InheritanceMacro : apl.PlatformSpecific{},
+		},
+		// See platforms/tgl/macro.go
+		config.TigerType     : tgl.PlatformSpecific{
+			InheritanceMacro : cnl.PlatformSpecific{
+				InheritanceMacro : apl.PlatformSpecific{},
+			},
+		},
+		// See platforms/adl/macro.go
+		config.AlderType     : adl.PlatformSpecific{
+			InheritanceMacro : tgl.PlatformSpecific{
+				InheritanceMacro : cnl.PlatformSpecific{
+					InheritanceMacro : apl.PlatformSpecific{},
+				},
+			},
+		},
 	}
-	parser.platform = platform[config.PlatformGet()]
+	parser.platform = platform[parser.opts.PlatformGet()]
 }
 
 // PadMapFprint - print pad info map to file
-func (parser *ParserData) PadMapFprint() {
+// w : writer for the generated file
+func (parser *ParserData) PadMapFprint(w io.Writer) {
+	gen := &generator{w: w, infolevel: parser.opts.InfoLevelGet()}
 	for _, pad := range parser.padmap {
 		switch pad.dw0 {
 		case 0:
-			pad.titleFprint()
+			pad.titleFprint(gen)
 		case 0xffffffff:
-			pad.reservedFprint()
+			pad.reservedFprint(gen)
 		default:
-			str := parser.platform.GenMacro(pad.id, pad.dw0, pad.dw1, pad.ownership)
-			pad.padInfoMacroFprint(str)
+			pad.padInfoMacroFprint(gen, parser.genMacro(&pad))
+		}
+	}
+}
+
+// GpioHFprint - print gpio.h with the pad configuration table to file
+// w : writer for the generated file
+// return error
+func (parser *ParserData) GpioHFprint(w io.Writer) error {
+	_, err := io.WriteString(w, `/* SPDX-License-Identifier: GPL-2.0-only */
+
+#ifndef CFG_GPIO_H
+#define CFG_GPIO_H
+
+#include <gpio.h>
+
+/* Pad configuration was generated automatically using intelp2m utility */
+static const struct pad_config gpio_table[] = {
+`)
+	if err != nil {
+		return err
+	}
+	// Add the pads map
+	parser.PadMapFprint(w)
+	if _, err = io.WriteString(w, "};\n"); err != nil {
+		return err
+	}
+	if !parser.opts.IsFspStyleMacro() {
+		// FSP style sets the lock in the pad configuration
+		parser.PadLockFprint(w)
+		parser.Gpe0Fprint(w)
+	}
+	_, err = io.WriteString(w, "\n#endif /* CFG_GPIO_H */\n")
+	return err
+}
+
+// PadLockFprint - print the table of the locked pads for gpio_lock_pads() to
+// file. Nothing is printed if there are no locked pads or the lock registers
+// are not in the inteltool log. The FSP GPIO_INIT_CONFIG table also contains
+// the lock state of the pads.
+// w : writer for the generated file
+func (parser *ParserData) PadLockFprint(w io.Writer) {
+	var locked []*padInfo
+	for i := range parser.padmap {
+		pad := &parser.padmap[i]
+		if pad.id != "" && pad.dw0 != 0xffffffff && pad.lock&common.PAD_LOCK_FULL != 0 {
+			locked = append(locked, pad)
 		}
 	}
+	if len(locked) == 0 {
+		return
+	}
+	source := "PADCFGLOCK and PADCFGLOCKTX"
+	if parser.opts.TemplateGet() == config.TempFsp {
+		source = "GPIO_LOCK_CONFIG"
+	}
+	fmt.Fprintf(w, "\n/* Pad configuration lock from %s */\n", source)
+	fmt.Fprint(w, "static const struct gpio_lock_config gpio_lock_table[] = {\n")
+	for _, pad := range locked {
+		fmt.Fprintf(w, "\t{ %s, GPIO_LOCK_%s },\n", pad.id, common.LockNameGet(pad.lock))
+	}
+	fmt.Fprint(w, "};\n")
+}
+
+// readOnlyFieldsGet - returns the masks of DW0-DW3 fields that can not be
+// configured and should be ignored when comparing pad configurations
+func (parser *ParserData) readOnlyFieldsGet() [common.MAX_DW_NUM]uint32 {
+	var ro [common.MAX_DW_NUM]uint32
+	for i := range ro {
+		ro[i] = parser.platform.ReadOnlyFieldsGet(uint8(i))
+	}
+	// GPIORXSTATE reflects the current state of the pad and can not be set
+	// using macros
+	ro[common.PAD_CFG_DW0] |= common.RxStateMask
+	return ro
+}
+
+// padVerify - checks that the generated macro sets the same configuration as in
+// the original DW0-DW3 register values
+// w     : writer for the mismatches
+// pad   : pad info with the original register values
+// macro : string of the generated macro
+// return true if the configuration matches
+func (parser *ParserData) padVerify(w io.Writer, pad *padInfo, macro string) bool {
+	cfg, err := common.MacroEncode(parser.platform, macro)
+	if err != nil {
+		fmt.Fprintf(w, "%s: unable to encode the macro: %v\n", pad.id, err)
+		return false
+	}
+	var encoded [common.MAX_DW_NUM]uint32
+	for i := range encoded {
+		encoded[i] = cfg.Register(uint8(i)).ValueGet()
+	}
+	diffs := common.FieldsCompare(parser.platform, pad.id, pad.dwGet(), encoded,
+		parser.readOnlyFieldsGet())
+	if len(diffs) == 0 && cfg.Ownership == pad.ownership {
+		return true
+	}
+	fmt.Fprintf(w, "%s: %s\n", pad.id, strings.TrimSpace(macro))
+	for _, diff := range diffs {
+		fmt.Fprintf(w, "\tDW%d %s: %s, macro sets %s\n", diff.Dw, diff.Name,
+			diff.Values[0], diff.Values[1])
+	}
+	if cfg.Ownership != pad.ownership {
+		fmt.Fprintf(w, "\tHOSTSW_OWN: %d, macro sets %d\n", pad.ownership, cfg.Ownership)
+	}
+	return false
+}
+
+// PadMapVerify - re-encodes the generated macros and compares them with the
+// original register values, ignoring the read-only fields
+// w : writer for the mismatches, it should not be the generated file
+// return the number of pads whose configuration does not match
+func (parser *ParserData) PadMapVerify(w io.Writer) int {
+	var mismatches int
+	for i := range parser.padmap {
+		pad := &parser.padmap[i]
+		if pad.dw0 == 0 || pad.dw0 == 0xffffffff {
+			// group title or reserved pad
+			continue
+		}
+		macro := parser.genMacro(pad)
+		if !parser.padVerify(w, pad, macro) {
+			mismatches++
+		}
+	}
+	return mismatches
 }
 
 // Register - read specific platform registers (32 bits)
@@ -172,61 +389,88 @@ func (parser *ParserData) PadMapFprint() {
 func (parser *ParserData) Register(nameTemplate string) (
 		valid bool, name string, offset uint32, value uint32) {
 	if strings.Contains(parser.line, nameTemplate) &&
-		config.TemplateGet() == config.TempInteltool {
+		parser.opts.TemplateGet() == config.TempInteltool {
 		if registerInfoTemplate(parser.line, &name, &offset, &value) == 0 {
-			fmt.Printf("\n\t/* %s : 0x%x : 0x%x */\n", name, offset, value)
+			fmt.Fprintf(parser.log, "\n\t/* %s : 0x%x : 0x%x */\n", name, offset, value)
 			return true, name, offset, value
 		}
 	}
 	return false, "ERROR", 0, 0
 }
 
-// padOwnershipExtract - extract Host Software Pad Ownership from inteltool dump
-//                       return true if success
-func (parser *ParserData) padOwnershipExtract() bool {
-	var group string
-	status, name, offset, value := parser.Register("HOSTSW_OWN_GPP_")
-	if status {
-		_, group = parser.platform.GroupNameExtract(parser.line)
-		parser.ownership[group] = value
-		fmt.Printf("\n\t/* padOwnershipExtract: [offset 0x%x] %s = 0x%x */\n",
-				offset, name, parser.ownership[group])
-	}
-	return status
-}
-
 // padConfigurationExtract - reads GPIO configuration registers and returns true if the
 //                           information from the inteltool log was successfully parsed.
+//                           The gpio.h lines with the pad lock table are parsed here too.
 func (parser *ParserData) padConfigurationExtract() bool {
+	if parser.opts.TemplateGet() == config.TempGpioh {
+		return parser.padLockEntryExtract()
+	}
 	// Only for Sunrise PCH and only for inteltool.log file template
-	if config.TemplateGet() != config.TempInteltool || config.IsPlatformApollo() {
+	if parser.opts.TemplateGet() != config.TempInteltool || parser.opts.IsPlatformApollo() {
 		return false
 	}
-	return parser.padOwnershipExtract()
+	if parser.gpe0Extract() || parser.groupRegsExtract() {
+		return true
+	}
+	// skip other registers such as PAD_OWN or PADCFGLOCK, otherwise the line
+	// can be taken as the pad configuration
+	var name string
+	var offset, value uint32
+	return strings.Contains(parser.line, "(") &&
+		registerInfoTemplate(parser.line, &name, &offset, &value) == 0
 }
 
 // Parse pads groupe information in the inteltool log file
-// ConfigFile : name of inteltool log file
-func (parser *ParserData) Parse() {
+// r : reader of the inteltool log file
+// return error
+func (parser *ParserData) Parse(r io.Reader) error {
 	// Read all lines from inteltool log file
-	fmt.Println("Parse IntelTool Log File...")
+	fmt.Fprintln(parser.log, "Parse IntelTool Log File...")
+
+	if parser.opts.TemplateGet() == config.TempImage {
+		// the firmware image is not split into lines
+		parser.diags = nil
+		return parser.imageParse(r)
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	parser.diags = nil
 
 	// determine the platform type and set the interface for it
+	if err := parser.platformDetect(lines); err != nil {
+		return err
+	}
 	parser.PlatformSpecificInterfaceSet()
 
-	// map of thepad ownership registers for the GPIO controller
-	parser.ownership = make(map[string]uint32)
+	// map of the group registers for the GPIO controller
+	parser.groupregs = make(map[string]map[string]uint32)
+	parser.gpe0 = nil
 
-	scanner := bufio.NewScanner(config.InputRegDumpFile)
-	for scanner.Scan() {
-		parser.line = scanner.Text()
+	var comment bool
+	for i, line := range lines {
+		if parser.opts.TemplateGet() != config.TempInteltool {
+			line = commentBlockStrip(line, &comment)
+		}
+		parser.line, parser.lineNum = line, i+1
 		if strings.Contains(parser.line, "GPIO Community") || strings.Contains(parser.line, "GPIO Group") {
 			parser.communityGroupExtract()
+		} else if parser.opts.TemplateGet() != config.TempInteltool && isCommentLine(parser.line) {
+			// the pad IDs in the comments, e.g. -ii or -iii output
+			continue
 		} else if !parser.padConfigurationExtract() && parser.platform.KeywordCheck(parser.line) {
-			if parser.padInfoExtract() != 0 {
-				fmt.Println("...error!")
-			}
+			parser.padInfoExtract()
 		}
 	}
-	fmt.Println("...done!")
+	parser.lineNum = 0
+	parser.padGpeSet()
+	fmt.Fprintln(parser.log, "...done!")
+	return nil
 }
@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+
+// parse - parses the input with the converter settings
+// platform : platform name for the -p option
+// template : template type for the -t option
+// input    : lines of the input file
+func parse(t *testing.T, platform string, template int, input ...string) *ParserData {
+	opts := &config.Options{}
+	if opts.PlatformSet(platform) != 0 {
+		t.Fatalf("invalid platform %s", platform)
+	}
+	opts.TemplateSet(template)
+	opts.FldStyleSet("none")
+	parser := NewParserData(opts, nil)
+	if err := parser.Parse(strings.NewReader(strings.Join(input, "\n"))); err != nil {
+		t.Fatal(err)
+	}
+	return parser
+}
+
+// TestPadMapVerify - the generated macros are encoded back and compared with
+// the register values from the log
+func TestPadMapVerify(t *testing.T) {
+	tests := []struct {
+		line  string
+		valid bool
+	}{
+		{"0x0400: 0x0000001844000702 GPP_A0   RCIN#", true},
+		{"0x0408: 0x0000001844000400 GPP_A1   LAD0", true},
+		{"0x0410: 0x0000001840880102 GPP_A2   GPIO", true},
+		{"0x0418: 0x0000001884000201 GPP_A3   GPIO", true},
+		{"0x0420: 0x0000001806000100 GPP_A4   GPIO", true},
+		// PAD_NC() sets RXEVCFG to OFF
+		{"0x0428: 0x0000001840000300 GPP_A5   GPIO", false},
+		{"0x0430: 0x00000000ffffffff GPP_A6   RESERVED", true},
+	}
+	input := []string{
+		"============= GPIO =============",
+		"------- GPIO Group GPP_A -------",
+	}
+	var mismatches int
+	for _, test := range tests {
+		input = append(input, test.line)
+		if !test.valid {
+			mismatches++
+		}
+	}
+	parser := parse(t, "snr", config.TempInteltool, input...)
+	var buf bytes.Buffer
+	if count := parser.PadMapVerify(&buf); count != mismatches {
+		t.Errorf("got %d mismatches, want %d", count, mismatches)
+	}
+	want := "GPP_A5: PAD_NC(GPP_A5, NONE),\n\tDW0 RXEVCFG: 0x0 (LEVEL), macro sets 0x2 (OFF)\n"
+	if buf.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+	for i, test := range tests {
+		pad := &parser.padmap[i+1]
+		if pad.dw0 == 0xffffffff {
+			continue
+		}
+		macro := parser.genMacro(pad)
+		if parser.padVerify(&bytes.Buffer{}, pad, macro) != test.valid {
+			t.Errorf("%s: %s verified is not %v", pad.id, macro, test.valid)
+		}
+	}
+}
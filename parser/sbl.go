@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/fields/sbl"
+
+// sblItemPrefix - prefix of the GPIO_CFG_DATA item generated for the pad,
+// the pads without the SBL pad ID are generated as comments
+const sblItemPrefix = "- !expand"
+
+// SblCfgDataFprint - print the GPIO_CFG_DATA table of Slim Bootloader in the
+// YAML configuration data format. The items are generated with the sbl bit
+// fields style, the header contains the number of the items.
+// w : writer for the generated file
+// return error
+func (parser *ParserData) SblCfgDataFprint(w io.Writer) error {
+	if !sbl.IsPlatformSupported(parser.opts) {
+		return fmt.Errorf("SBL pad IDs are not known for -p %s", parser.opts.PlatformNameGet())
+	}
+	// the comments are added here, the macros contain only the items
+	opts := *parser.opts
+	opts.InfoLevelSet(0)
+	var items strings.Builder
+	gen := &generator{w: &items, infolevel: parser.opts.InfoLevelGet()}
+	var count int
+	for i := range parser.padmap {
+		pad := &parser.padmap[i]
+		switch pad.dw0 {
+		case 0:
+			gen.generate(0, "\n  # %s\n", pad.function)
+		case 0xffffffff:
+			gen.generate(0, "  # %s - %s\n", pad.id, pad.function)
+		default:
+			item := parser.platform.GenMacro(pad.id, pad.dw0, pad.dw1, pad.dw2,
+				pad.ownership, pad.lock, &opts)
+			if strings.HasPrefix(item, sblItemPrefix) {
+				count++
+			}
+			gen.generate(1, "  # %s - %s", pad.id, pad.functionGet())
+			gen.generate(2, " DW0: 0x%0.8x, DW1: 0x%0.8x", pad.dw0, pad.dw1)
+			gen.generate(1, "\n")
+			gen.generate(0, "  %s", item)
+			if gen.infolevel == 0 && pad.functionGet() != "" {
+				gen.generate(0, "  # %s", pad.functionGet())
+			}
+			gen.generate(0, "\n")
+		}
+	}
+	_, err := fmt.Fprintf(w, `# Pad configuration was generated automatically using intelp2m utility
+- GPIO_CFG_DATA :
+  - !expand { CFGHDR_TMPL : [ GPIO_CFG_DATA, 0x400, 0, 0 ] }
+  - GPIO_CFG_HDR :
+    - GpioHeaderSize :
+        length       : 0x01
+        value        : 0x08
+    - GpioBaseTableId :
+        length       : 0x01
+        value        : 0xFF
+    - GpioItemSize :
+        length       : 0x02
+        value        : 0x08
+    - GpioItemCount :
+        length       : 0x02
+        value        : 0x%04X
+    - GpioTableRsvd :
+        length       : 0x02
+        value        : 0x0000
+`, count)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, items.String())
+	return err
+}
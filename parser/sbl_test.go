@@ -0,0 +1,44 @@
+package parser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+
+func TestSblCfgDataFprint(t *testing.T) {
+	input := []string{
+		"============= GPIO =============",
+		"------- GPIO Community 0 -------",
+		"------- GPIO Group GPP_A -------",
+		"0x0400: 0x0000001844000702 GPP_A0   RCIN#",
+		"0x0408: 0xffffffffffffffff GPP_A1   RESERVED",
+		"0x0410: 0x0000001840880102 GPP_A2   GPIO",
+	}
+	parser := parse(t, "snr", config.TempInteltool, input...)
+	parser.opts.FldStyleSet("sbl")
+	var buf bytes.Buffer
+	if err := parser.SblCfgDataFprint(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"    - GpioItemCount :\n        length       : 0x02\n        value        : 0x0002\n",
+		"\n  # ------- GPIO Group GPP_A -------\n",
+		"  - !expand { GPIO_TMPL : [ GPP_A00, 0x0B50A3A3, 0x00000081 ] }  # RCIN#\n",
+		"  # GPP_A1 - RESERVED\n",
+		"  - !expand { GPIO_TMPL : [ GPP_A02, 0x0B14ADA1, 0x00020081 ] }  # GPIO\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("no %q in\n%s", want, buf.String())
+		}
+	}
+
+	parser = parse(t, "cnl", config.TempInteltool, input...)
+	parser.opts.FldStyleSet("sbl")
+	err := parser.SblCfgDataFprint(&buf)
+	if err == nil || !strings.Contains(err.Error(), "-p cnl") {
+		t.Errorf("cnl: got error %v", err)
+	}
+}
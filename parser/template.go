@@ -2,11 +2,22 @@ package parser
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"unicode"
 )
 
-type template func(string, *string, *string, *uint32, *uint32) int
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/fields/fsp"
+
+// template - parses the line from the file with pad config map
+// line : string from file with pad config map
+// pad  : (out) pad info with DW0/DW1 register values as they are
+//        set in the hardware
+// return
+//   error status
+type template func(line string, pad *padInfo) int
 
 // extractPadFuncFromComment
 // line   : string from file with pad config map
@@ -25,90 +36,288 @@ func extractPadFuncFromComment(line string) string {
 	return ""
 }
 
+// isCommentLine - returns true if the line contains only a comment, e.g.
+// /* PAD_CFG_NF(GPP_A1, 20K_PU, DEEP, NF1), */
+// line : string from file with pad config map
+func isCommentLine(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.HasPrefix(line, "//") ||
+		strings.HasPrefix(line, "/*") && strings.HasSuffix(line, "*/") &&
+			strings.Index(line, "*/") == len(line)-2
+}
+
+// commentBlockStrip - removes the parts of the line that are in the comment
+// over several lines, e.g. the GPE0 routing block in the generated gpio.h
+// line    : string from file with pad config map
+// comment : (in/out) true if the line starts inside the comment
+// return the line without the comment parts
+func commentBlockStrip(line string, comment *bool) string {
+	if *comment {
+		end := strings.Index(line, "*/")
+		if end < 0 {
+			return ""
+		}
+		*comment = false
+		line = line[end+2:]
+	}
+	if start := strings.LastIndex(line, "/*"); start >= 0 &&
+		!strings.Contains(line[start:], "*/") {
+		*comment = true
+		line = line[:start]
+	}
+	return line
+}
+
 // tokenCheck
 func tokenCheck(c rune) bool {
 	return c != '_' && c != '#' && !unicode.IsLetter(c) && !unicode.IsNumber(c)
 }
 
-// useGpioHTemplate
-// line      : string from file with pad config map
-// *function : the string that means the pad function
-// *id       : pad id string
-// *dw0      : DW0 register value
-// *dw1      : DW1 register value
+// useInteltoolLogTemplate
+// line : string from file with pad config map
+// pad  : (out) pad info
 // return
 //   error status
-func useInteltoolLogTemplate(line string, function *string,
-	id *string, dw0 *uint32, dw1 *uint32) int {
+func useInteltoolLogTemplate(line string, pad *padInfo) int {
 
 	var val uint64
 	// 0x0520: 0x0000003c44000600 GPP_B12  SLP_S0#
 	// 0x0438: 0xffffffffffffffff GPP_C7   RESERVED
+	// Tiger Lake and newer PCHs have four configuration registers per pad,
+	// inteltool prints DW2/DW3 as the second value. DW3 has no configuration
+	// fields, so only DW2 is used:
+	// 0x0700: 0x0000001844000702 0x0000000000000000 GPP_B0   CORE_VID0
 	if fields := strings.FieldsFunc(line, tokenCheck); len(fields) >= 4 {
 		fmt.Sscanf(fields[1], "0x%x", &val)
-		*dw0 = uint32(val & 0xffffffff)
-		*dw1 = uint32(val >> 32)
-		*id = fields[2]
-		*function = fields[3]
+		pad.dw0 = uint32(val & 0xffffffff)
+		pad.dw1 = uint32(val >> 32)
+		if strings.HasPrefix(fields[2], "0x") && len(fields) >= 5 {
+			fmt.Sscanf(fields[2], "0x%x", &val)
+			pad.dw2 = uint32(val & 0xffffffff)
+			fields = fields[1:]
+		}
+		pad.id = fields[2]
+		pad.function = fields[3]
 		// Sometimes the configuration file contains compound functions such as
 		// SUSWARN#/SUSPWRDNACK. Since the template does not take this into account,
 		// need to collect all parts of the pad function back into a single word
 		for i := 4; i < len(fields); i++ {
-			*function += "/" + fields[i]
+			pad.function += "/" + fields[i]
 		}
-		// clear RO Interrupt Select (INTSEL)
-		*dw1 &= 0xffffff00
+		// clear RO Interrupt Select (INTSEL), but keep it for the ACPI
+		// resources of the pads routed to IOxAPIC
+		pad.intsel = uint8(pad.dw1 & 0xff)
+		pad.dw1 &= 0xffffff00
+		return 0
+	}
+	return -1
+}
+
+// usePinctrlTemplate - parses the pad from the pins file of the Linux
+// pinctrl-intel driver, /sys/kernel/debug/pinctrl/<controller>/pins
+// line : string from file with pad config map
+// pad  : (out) pad info
+// return
+//   error status
+func usePinctrlTemplate(line string, pad *padInfo) int {
+
+	// pin 12 (GPP_A12) GPIO 0x80880102 0x00000000 [LOCKED]
+	// pin 0 (GPP_A0) mode 1 0x44000702 0x00000000 [LOCKED tx, ACPI]
+	// pin 5 (GPP_A5) not available
+	// Tiger Lake and newer PCHs have DW2 as the third value, the newer kernels
+	// also print the GPIO line number:
+	// pin 12 (GPP_A12) 12:INT34C5:00 GPIO 0x80880102 0x00000000 0x00000000
+	start, end := strings.Index(line, "("), strings.Index(line, ")")
+	if !strings.HasPrefix(strings.TrimSpace(line), "pin ") || start < 0 || end < start {
+		return -1
+	}
+	pad.id = strings.TrimSpace(line[start+1 : end])
+	line = line[end+1:]
+	if strings.Contains(line, "not available") {
+		// the pad is not owned by the host, it is printed as the reserved one
+		pad.dw0, pad.dw1 = 0xffffffff, 0xffffffff
+		pad.function = "not available"
+		return 0
+	}
+	var flags string
+	if start = strings.Index(line, "["); start >= 0 {
+		line, flags = line[:start], line[start:]
+	}
+	var regs []uint32
+	for _, field := range strings.Fields(line) {
+		if field == "GPIO" {
+			// the native functions are printed as "mode 1", the platform
+			// table gives their names
+			pad.function = field
+		}
+		if !strings.HasPrefix(field, "0x") {
+			continue
+		}
+		value, err := strconv.ParseUint(field, 0, 32)
+		if err != nil {
+			return -1
+		}
+		regs = append(regs, uint32(value))
+	}
+	if len(regs) < 2 {
+		return -1
+	}
+	pad.dw0, pad.dw1 = regs[0], regs[1]
+	if len(regs) > 2 {
+		pad.dw2 = regs[2]
+	}
+	// clear RO Interrupt Select (INTSEL) as for the inteltool log
+	pad.intsel = uint8(pad.dw1 & 0xff)
+	pad.dw1 &= 0xffffff00
+	// the driver prints ACPI for the pads with HOSTSW_OWN = 0
+	pad.ownership, pad.hostsw = common.PAD_OWN_DRIVER, true
+	if strings.Contains(flags, "ACPI") {
+		pad.ownership = common.PAD_OWN_ACPI
+	}
+	switch {
+	case strings.Contains(flags, "LOCKED full"):
+		pad.lock = common.PAD_LOCK_FULL
+	case strings.Contains(flags, "LOCKED tx"):
+		pad.lock = common.PAD_LOCK_TX
+	case strings.Contains(flags, "LOCKED"):
+		pad.lock = common.PAD_LOCK_CONFIG
+	default:
+		pad.lock = common.PAD_UNLOCK
 	}
 	return 0
 }
 
+// isRegisterDump - returns true if the input contains all pads of the GPIO
+// controller with the registers as they are set in the hardware, i.e. it is
+// the inteltool log or the pinctrl debugfs dump
+func (parser *ParserData) isRegisterDump() bool {
+	template := parser.opts.TemplateGet()
+	return template == config.TempInteltool || template == config.TempPinctrl
+}
+
 // useGpioHTemplate
-// line      : string from file with pad config map
-// *function : the string that means the pad function
-// *id       : pad id string
-// *dw0      : DW0 register value
-// *dw1      : DW1 register value
+// line : string from file with pad config map
+// pad  : (out) pad info
 // return
 //   error status
-func useGpioHTemplate(line string, function *string,
-	id *string, dw0 *uint32, dw1 *uint32) int {
+func (parser *ParserData) useGpioHTemplate(line string, pad *padInfo) int {
 
 	// /* RCIN# */	_PAD_CFG_STRUCT(GPP_A0, 0x44000702, 0x00000000),
 	// _PAD_CFG_STRUCT(GPP_A0, 0x44000702, 0x00000000), /* RCIN# */
-	// _PAD_CFG_STRUCT(GPP_A0, 0x44000702, 0x00000000)
-	fields := strings.FieldsFunc(line, tokenCheck)
-	for i, field := range fields {
-		if field == "_PAD_CFG_STRUCT" {
-			if len(fields) < 4 {
-				/* the number of definitions does not match the format */
-				return -1
-			}
-
-			if !strings.Contains(fields[i+2], "0x") || !strings.Contains(fields[i+3], "0x") {
-				/* definitions inside the macro do not match the pattern */
-				return -1
-			}
-			*id = fields[i+1]
-			fmt.Sscanf(fields[i+2], "0x%x", dw0)
-			fmt.Sscanf(fields[i+3], "0x%x", dw1)
-			*function = extractPadFuncFromComment(line)
-			return 0
+	// _PAD_CFG_STRUCT(GPP_A0, PAD_FUNC(NF1) | PAD_RESET(DEEP), PAD_PULL(NONE)),
+	// PAD_CFG_NF(GPP_A0, NONE, DEEP, NF1), /* RCIN# */
+	// The macros contain logical reset sources, the encoder converts them to the
+	// values of the PADRSTCFG field in the same way as coreboot does it.
+	if !common.IsMacroLine(line) {
+		// e.g. #define EC_SCI_GPI GPP_E16
+		return -1
+	}
+	cfg, err := common.MacroEncode(parser.platform, line)
+	if err != nil {
+		parser.diagAdd(common.LintError, "", "pad is skipped: %v", err)
+		return -1
+	}
+	pad.id = cfg.Id
+	pad.dw0 = cfg.Register(common.PAD_CFG_DW0).ValueGet()
+	pad.dw1 = cfg.Register(common.PAD_CFG_DW1).ValueGet()
+	pad.dw2 = cfg.Register(common.PAD_CFG_DW2).ValueGet()
+	pad.ownership, pad.hostsw = cfg.Ownership, true
+	pad.function = extractPadFuncFromComment(line)
+	return 0
+}
+
+// padLockEntryExtract - sets the lock state of the pad from the entry of the
+// gpio_lock_table that is generated after the pad configuration table
+// return true if the line is the entry of the lock table
+func (parser *ParserData) padLockEntryExtract() bool {
+
+	// { GPP_A0, GPIO_LOCK_FULL },
+	fields := strings.FieldsFunc(parser.line, tokenCheck)
+	if !strings.HasPrefix(strings.TrimSpace(parser.line), "{") || len(fields) != 2 ||
+		!strings.HasPrefix(fields[1], "GPIO_LOCK_") {
+		return false
+	}
+	lock, valid := common.LockValueGet(strings.TrimPrefix(fields[1], "GPIO_LOCK_"))
+	if !valid {
+		parser.diagAdd(common.LintError, fields[0], "unknown lock state %s", fields[1])
+		return true
+	}
+	for i := range parser.padmap {
+		if parser.padmap[i].id == fields[0] {
+			parser.padmap[i].lock = lock
+			return true
 		}
 	}
-	return -1
+	parser.diagAdd(common.LintError, fields[0], "the lock is set for the pad that is not "+
+		"in the pad configuration table")
+	return true
+}
+
+// useFspTemplate - parses the FSP/edk2 GPIO_INIT_CONFIG entry
+// line : string from file with pad config map
+// pad  : (out) pad info
+// return
+//   error status
+func (parser *ParserData) useFspTemplate(line string, pad *padInfo) int {
+
+	// { GPIO_SKL_H_GPP_A12, { GpioPadModeGpio, GpioHostOwnAcpi, GpioDirIn, ... } },
+	// {GPIO_CNL_LP_GPP_A0, {GpioPadModeNative1, ..., GpioTermNone}},//RCIN#
+	name, fields, err := fsp.ConfigFieldsGet(line)
+	if err != nil {
+		parser.diagAdd(common.LintError, "", "pad is skipped: %v", err)
+		return -1
+	}
+	id, valid := parser.fspPadIdGet(name)
+	if !valid {
+		parser.diagAdd(common.LintError, "", "pad is skipped: unknown pad %s", name)
+		return -1
+	}
+	cfg, lock, err := fsp.ConfigEncode(parser.platform, id, fields)
+	if err != nil {
+		parser.diagAdd(common.LintError, "", "pad is skipped: %v", err)
+		return -1
+	}
+	pad.id = id
+	pad.dw0 = cfg.Register(common.PAD_CFG_DW0).ValueGet()
+	pad.dw1 = cfg.Register(common.PAD_CFG_DW1).ValueGet()
+	pad.ownership, pad.hostsw = cfg.Ownership, true
+	pad.lock = lock
+	pad.function = extractPadFuncFromComment(line)
+	if start := strings.Index(line, "//"); pad.function == "" && start >= 0 {
+		if words := strings.Fields(line[start+2:]); len(words) != 0 {
+			pad.function = words[0]
+		}
+	}
+	return 0
+}
+
+// fspPadIdGet - returns the pad ID for the pad name from FSP/edk2, the prefix
+// with the platform name is removed: GPIO_SKL_H_GPP_A12 -> GPP_A12
+// name : pad name from the GPIO_INIT_CONFIG entry
+// return false if there is no pad of the platform in the name
+func (parser *ParserData) fspPadIdGet(name string) (string, bool) {
+	for id := name; ; {
+		if valid, _, _ := parser.padNumberGet(id); valid {
+			return id, true
+		}
+		separator := strings.Index(id, "_")
+		if separator < 0 {
+			return "", false
+		}
+		id = id[separator+1:]
+	}
 }
 
 // useYourTemplate
-func useYourTemplate(line string, function *string,
-	id *string, dw0 *uint32, dw1 *uint32) int {
+func useYourTemplate(line string, pad *padInfo) int {
 
 	// ADD YOUR TEMPLATE HERE
-	*function = ""
-	*id = ""
-	*dw0 = 0
-	*dw1 = 0
+	pad.function = ""
+	pad.id = ""
+	pad.dw0 = 0
+	pad.dw1 = 0
 
-	fmt.Printf("ADD YOUR TEMPLATE!\n")
+	// the line is reported as the skipped pad
 	return -1
 }
 
@@ -0,0 +1,196 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+func TestInteltoolTemplate(t *testing.T) {
+	tests := []struct {
+		line     string
+		id       string
+		function string
+		dw       [4]uint32
+	}{
+		// INTSEL is read-only and cleared
+		{"0x0500: 0x0000301844000702 GPP_A0   RCIN#", "GPP_A0", "RCIN#",
+			[4]uint32{0x44000702, 0x3000}},
+		// DW2/DW3 of Tiger Lake and newer PCHs, DW3 has no configuration fields
+		{"0x0700: 0x0000301844000702 0x0000000100000007 GPP_B0   CORE_VID0", "GPP_B0",
+			"CORE_VID0", [4]uint32{0x44000702, 0x3000, 0x7}},
+		{"0x0708: 0x0000301844000702 0x0000000000000000 GPP_B1   GPIO", "GPP_B1", "GPIO",
+			[4]uint32{0x44000702, 0x3000}},
+	}
+	for _, test := range tests {
+		parser := parse(t, "tgl", config.TempInteltool, "------- GPIO Group GPP_B -------",
+			test.line)
+		if len(parser.padmap) != 2 {
+			t.Errorf("%s: got %d entries", test.line, len(parser.padmap))
+			continue
+		}
+		pad := &parser.padmap[1]
+		if dw := pad.dwGet(); pad.id != test.id || pad.function != test.function ||
+			dw != test.dw {
+			t.Errorf("%s: got %s %s %08x", test.line, pad.id, pad.function, dw)
+		}
+	}
+}
+
+func TestPinctrlTemplate(t *testing.T) {
+	tests := []struct {
+		line   string
+		status int
+		pad    padInfo
+	}{
+		{"pin 0 (GPP_A0) mode 1 0x44000702 0x00000000 [LOCKED, ACPI]", 0,
+			padInfo{id: "GPP_A0", dw0: 0x44000702, ownership: common.PAD_OWN_ACPI,
+				lock: common.PAD_LOCK_CONFIG}},
+		{"pin 1 (GPP_A1) mode 1 0x44000702 0x00003c00 [LOCKED tx, ACPI]", 0,
+			padInfo{id: "GPP_A1", dw0: 0x44000702, dw1: 0x00003c00,
+				ownership: common.PAD_OWN_ACPI, lock: common.PAD_LOCK_TX}},
+		{"pin 2 (GPP_A2) GPIO 0x84000201 0x00000000 [LOCKED full]", 0,
+			padInfo{id: "GPP_A2", function: "GPIO", dw0: 0x84000201,
+				ownership: common.PAD_OWN_DRIVER, lock: common.PAD_LOCK_FULL}},
+		{"pin 3 (GPP_A3) 3:INT344B:00 GPIO 0x80880102 0x00000018", 0,
+			padInfo{id: "GPP_A3", function: "GPIO", dw0: 0x80880102, intsel: 0x18,
+				ownership: common.PAD_OWN_DRIVER, lock: common.PAD_UNLOCK}},
+		{"pin 12 (GPP_B12) 12:INT34C5:00 GPIO 0x80880102 0x00000000 0x00000009", 0,
+			padInfo{id: "GPP_B12", function: "GPIO", dw0: 0x80880102, dw2: 0x00000009,
+				ownership: common.PAD_OWN_DRIVER, lock: common.PAD_UNLOCK}},
+		{"pin 4 (GPP_A4) not available", 0,
+			padInfo{id: "GPP_A4", function: "not available", dw0: 0xffffffff,
+				dw1: 0xffffffff}},
+		{"pin 6 (GPP_A6) GPIO 0x84000201 zz", -1, padInfo{}},
+		{"pin 7 (GPP_A7) GPIO 0x84000201 0x0000000g", -1, padInfo{}},
+		{"registered pins: 9", -1, padInfo{}},
+	}
+	for _, test := range tests {
+		var pad padInfo
+		status := usePinctrlTemplate(test.line, &pad)
+		if status != test.status {
+			t.Errorf("%s: got status %d, want %d", test.line, status, test.status)
+			continue
+		}
+		if status != 0 {
+			continue
+		}
+		if pad.id != test.pad.id || pad.function != test.pad.function ||
+			pad.dwGet() != test.pad.dwGet() || pad.intsel != test.pad.intsel ||
+			pad.ownership != test.pad.ownership || pad.lock != test.pad.lock {
+			t.Errorf("%s: got %+v, want %+v", test.line, pad, test.pad)
+		}
+	}
+}
+
+func TestPinctrlParse(t *testing.T) {
+	parser := parse(t, "snr", config.TempPinctrl,
+		"registered pins: 3",
+		"pin 0 (GPP_A0) mode 1 0x40000400 0x00000000 [LOCKED, ACPI]",
+		"pin 1 (GPP_A1) GPIO 0x84000201 0x00000000 [ACPI]",
+		"pin 2 (GPP_A2) GPIO 0x84000201 zz",
+	)
+	want := []struct {
+		id    string
+		macro string
+	}{
+		{"GPP_A0", "PAD_CFG_NF(GPP_A0, NONE, DEEP, NF1),"},
+		{"GPP_A1", "PAD_CFG_GPO(GPP_A1, 1, PLTRST),"},
+	}
+	if len(parser.padmap) != len(want) {
+		t.Fatalf("got %d pads, want %d", len(parser.padmap), len(want))
+	}
+	for i, pad := range want {
+		if parser.padmap[i].id != pad.id {
+			t.Errorf("pad %d: got %s, want %s", i, parser.padmap[i].id, pad.id)
+		} else if macro := parser.genMacro(&parser.padmap[i]); macro != pad.macro {
+			t.Errorf("%s: got %s, want %s", pad.id, macro, pad.macro)
+		}
+	}
+	// the pad function of the native function comes from the platform table
+	if parser.padmap[0].nf != "RCIN#" {
+		t.Errorf("GPP_A0: got function %q, want RCIN#", parser.padmap[0].nf)
+	}
+	diags := parser.DiagnosticsGet()
+	if len(diags) != 1 || diags[0].Line != 4 || diags[0].Severity != common.LintError {
+		t.Errorf("got diagnostics %v, want the error in line 4", diags)
+	}
+}
+
+func TestFspParse(t *testing.T) {
+	parser := parse(t, "cnl", config.TempFsp,
+		"static GPIO_INIT_CONFIG mGpioTable[] = {",
+		"  {GPIO_CNL_LP_GPP_C8, {GpioPadModeGpio, GpioHostOwnGpio, GpioDirInInv, "+
+			"GpioOutDefault, GpioIntLevel | GpioIntApic, GpioPlatformReset, "+
+			"GpioTermNone}}, //TOUCHPAD_INT",
+		"  {GPIO_CNL_LP_GPP_C9, {GpioPadModeGpio, GpioHostOwnAcpi, GpioDirOut, "+
+			"GpioOutHigh, GpioIntDis, GpioHostDeepReset, GpioTermNone, "+
+			"GpioOutputStateLock}},",
+		"  //{GPIO_CNL_LP_GPP_C10, {GpioPadModeGpio, GpioHostOwnAcpi, GpioDirOut}},",
+		"  {GPIO_CNL_LP_GPP_Z1, {GpioPadModeGpio, GpioHostOwnAcpi, GpioDirOut}},",
+		"  {GPIO_CNL_LP_GPP_C11, {GpioPadModeGpio, GpioHostOwnAcpi, GpioDirSideways}},",
+		"};",
+	)
+	want := []struct {
+		id       string
+		function string
+		lock     uint8
+		macro    string
+	}{
+		{"GPP_C8", "TOUCHPAD_INT", common.PAD_LOCK_DEFAULT,
+			"PAD_CFG_GPI_APIC(GPP_C8, NONE, PLTRST, LEVEL, INVERT),"},
+		{"GPP_C9", "", common.PAD_LOCK_TX, "PAD_CFG_GPO(GPP_C9, 1, DEEP),"},
+	}
+	if len(parser.padmap) != len(want) {
+		t.Fatalf("got %d pads, want %d", len(parser.padmap), len(want))
+	}
+	for i, pad := range want {
+		got := &parser.padmap[i]
+		if got.id != pad.id || got.function != pad.function || got.lock != pad.lock {
+			t.Errorf("pad %d: got %s %q %d, want %s %q %d", i, got.id, got.function,
+				got.lock, pad.id, pad.function, pad.lock)
+		} else if macro := parser.genMacro(got); macro != pad.macro {
+			t.Errorf("%s: got %s, want %s", pad.id, macro, pad.macro)
+		}
+	}
+	var lines []int
+	for _, diag := range parser.DiagnosticsGet() {
+		if diag.Severity == common.LintError {
+			lines = append(lines, diag.Line)
+		}
+	}
+	if len(lines) != 2 || lines[0] != 5 || lines[1] != 6 {
+		t.Errorf("got errors in lines %v, want 5 and 6", lines)
+	}
+}
+
+// TestCommentBlock - the comments over several lines are skipped, e.g. the
+// GPE0 routing block of the generated gpio.h
+func TestCommentBlock(t *testing.T) {
+	parser := parse(t, "snr", config.TempGpioh,
+		"static const struct pad_config gpio_table[] = {",
+		"\tPAD_CFG_NF(GPP_A0, NONE, DEEP, NF1), /* RCIN#",
+		"\t\tPAD_CFG_NF(GPP_A9, NONE, DEEP, NF1), */",
+		"/*",
+		"\tPAD_CFG_NF(GPP_A1, NONE, DEEP, NF1),",
+		"*/ PAD_CFG_NF(GPP_A2, NONE, DEEP, NF1),",
+		"};",
+		"",
+		"/* GPE0 routing from MISCCFG, devicetree.cb:",
+		"\tregister \"gpe0_dw0\" = \"GPP_A\"",
+		"\tregister \"gpe0_dw1\" = \"GPP_B\"",
+		"*/",
+	)
+	var ids []string
+	for _, pad := range parser.padmap {
+		ids = append(ids, pad.id)
+	}
+	if strings.Join(ids, " ") != "GPP_A0 GPP_A2" {
+		t.Errorf("got pads %v", ids)
+	}
+	if diags := parser.DiagnosticsGet(); len(diags) != 0 {
+		t.Errorf("got diagnostics %v", diags)
+	}
+}
@@ -0,0 +1,140 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// padOverridden - returns true if the pad configuration of the variant differs
+// from the baseboard and the pad should be in the override table. The macros
+// generated for the baseboard and the variant are compared, so the fields that
+// the macro ignores, e.g. the debounce in the FSP style, do not add the pad to
+// the table. The RX state reflects the input level, the lock state and the GPI
+// enable registers are not in the pad_config structure, they are not compared.
+// base    : parser data of the baseboard log
+// basepad : pad info from the baseboard log, nil if the pad is not there
+// variant : pad info from the variant log
+func (parser *ParserData) padOverridden(base *ParserData, basepad *padInfo,
+		variant *padInfo) bool {
+	if basepad == nil || basepad.dw0 == 0xffffffff {
+		return true
+	}
+	first, second := *basepad, *variant
+	first.dw0 &= ^common.RxStateMask
+	second.dw0 &= ^common.RxStateMask
+	first.lock, second.lock = common.PAD_LOCK_DEFAULT, common.PAD_LOCK_DEFAULT
+	return base.genMacro(&first) != parser.genMacro(&second)
+}
+
+// padMapFilterFprint - print the pads selected by the filter to file. The group
+// title is printed only if the group contains the selected pads, the reserved
+// pads are skipped.
+// w      : writer for the generated file
+// filter : returns true if the pad should be printed
+// return the number of the printed pads
+func (parser *ParserData) padMapFilterFprint(w io.Writer, filter func(pad *padInfo) bool) int {
+	gen := &generator{w: w, infolevel: parser.opts.InfoLevelGet()}
+	var title *padInfo
+	var count int
+	for i := range parser.padmap {
+		pad := &parser.padmap[i]
+		switch {
+		case pad.dw0 == 0:
+			title = pad
+		case pad.dw0 == 0xffffffff || !filter(pad):
+		default:
+			if title != nil {
+				title.titleFprint(gen)
+				title = nil
+			}
+			pad.padInfoMacroFprint(gen, parser.genMacro(pad))
+			count++
+		}
+	}
+	return count
+}
+
+// variantHeader - beginning of gpio.c of the baseboard and the variant
+const variantHeader = `/* SPDX-License-Identifier: GPL-2.0-only */
+
+#include <baseboard/gpio.h>
+#include <baseboard/variants.h>
+#include <commonlib/helpers.h>
+
+/* Pad configuration was generated automatically using intelp2m utility */
+`
+
+// BaseboardFprint - print gpio.c of the baseboard with the pad configuration
+// table for variant_base_gpio_table()
+// w : writer for the generated file
+// return error
+func (parser *ParserData) BaseboardFprint(w io.Writer) error {
+	_, err := io.WriteString(w, variantHeader+"static const struct pad_config gpio_table[] = {\n")
+	if err != nil {
+		return err
+	}
+	parser.PadMapFprint(w)
+	_, err = io.WriteString(w, `};
+
+const struct pad_config *variant_base_gpio_table(size_t *num)
+{
+	*num = ARRAY_SIZE(gpio_table);
+	return gpio_table;
+}
+`)
+	return err
+}
+
+// VariantFprint - print gpio.c of the variant with the override table for
+// variant_override_gpio_table(). The table contains only the pads whose
+// configuration differs from the baseboard. The pads that are configured in
+// the baseboard, but are reserved or missing in the variant log, can not be
+// overridden and are listed in the comment.
+// w    : writer for the generated file
+// base : parser data of the baseboard log
+// return
+//     number of the pads in the override table
+//     error
+func (parser *ParserData) VariantFprint(w io.Writer, base *ParserData) (int, error) {
+	_, err := io.WriteString(w, variantHeader+"static const struct pad_config override_gpio_table[] = {\n")
+	if err != nil {
+		return 0, err
+	}
+	basepads := base.padMapGet()
+	count := parser.padMapFilterFprint(w, func(pad *padInfo) bool {
+		return parser.padOverridden(base, basepads[pad.id], pad)
+	})
+	if _, err = io.WriteString(w, "};\n"); err != nil {
+		return count, err
+	}
+
+	pads := parser.padMapGet()
+	var kept []*padInfo
+	for i := range base.padmap {
+		pad := &base.padmap[i]
+		if pad.id == "" || pad.dw0 == 0xffffffff {
+			continue
+		}
+		if other, valid := pads[pad.id]; !valid || other.dw0 == 0xffffffff {
+			kept = append(kept, pad)
+		}
+	}
+	if len(kept) != 0 {
+		fmt.Fprint(w, "\n/*\n * Not in the variant log, the baseboard configuration is used:\n")
+		for _, pad := range kept {
+			fmt.Fprintf(w, " *   %s - %s\n", pad.id, pad.functionGet())
+		}
+		fmt.Fprint(w, " */\n")
+	}
+
+	_, err = io.WriteString(w, `
+const struct pad_config *variant_override_gpio_table(size_t *num)
+{
+	*num = ARRAY_SIZE(override_gpio_table);
+	return override_gpio_table;
+}
+`)
+	return count, err
+}
@@ -0,0 +1,135 @@
+package parser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// variantLog - Sunrise Point inteltool log with the pads of the GPP_A group
+// pads : pad lines
+func variantLog(pads ...string) []string {
+	log := []string{
+		"============= GPIO =============",
+		"------- GPIO Community 0 -------",
+		"------- GPIO Group GPP_A -------",
+	}
+	return append(log, pads...)
+}
+
+func TestPadOverridden(t *testing.T) {
+	base := parse(t, "snr", config.TempInteltool, variantLog(
+		"0x0418: 0x0000001842880102 GPP_A3   GPIO",
+		"0x0420: 0x0000001844000702 GPP_A4   LFRAME#",
+		"0x0428: 0x0000001844000201 GPP_A5   GPIO",
+	)...)
+	tests := []struct {
+		name       string
+		id         string
+		dw0        uint32
+		dw1        uint32
+		ownership  uint8
+		overridden bool
+	}{
+		{"same", "GPP_A3", 0x42880102, 0x00000018, common.PAD_OWN_ACPI, false},
+		{"RX state", "GPP_A3", 0x42880100, 0x00000018, common.PAD_OWN_ACPI, false},
+		{"TX state", "GPP_A3", 0x42880103, 0x00000018, common.PAD_OWN_ACPI, true},
+		{"termination", "GPP_A3", 0x42880102, 0x00003018, common.PAD_OWN_ACPI, true},
+		{"native function", "GPP_A4", 0x44000b02, 0x00000018, common.PAD_OWN_ACPI, true},
+		{"output ownership", "GPP_A5", 0x44000201, 0x00000018, common.PAD_OWN_DRIVER, true},
+		{"output state", "GPP_A5", 0x44000200, 0x00000018, common.PAD_OWN_ACPI, true},
+	}
+	for _, test := range tests {
+		pad := padFind(base, test.id)
+		variant := *pad
+		variant.dw0, variant.dw1, variant.ownership = test.dw0, test.dw1, test.ownership
+		if base.padOverridden(base, pad, &variant) != test.overridden {
+			t.Errorf("%s: overridden is not %v\n%s\n%s", test.name, test.overridden,
+				base.genMacro(pad), base.genMacro(&variant))
+		}
+	}
+
+	pad := padFind(base, "GPP_A3")
+	if !base.padOverridden(base, nil, pad) {
+		t.Errorf("pad missing in the baseboard is not overridden")
+	}
+	reserved := *pad
+	reserved.dw0 = 0xffffffff
+	if !base.padOverridden(base, &reserved, pad) {
+		t.Errorf("pad reserved in the baseboard is not overridden")
+	}
+}
+
+// TestPadOverriddenStyle - the fields that the macro of the style does not
+// contain are not compared
+func TestPadOverriddenStyle(t *testing.T) {
+	base := parse(t, "tgl", config.TempInteltool, "------- GPIO Group GPP_B -------",
+		"0x0700: 0x0000301844000702 0x0000000000000007 GPP_B0   CORE_VID0")
+	pad := padFind(base, "GPP_B0")
+	variant := *pad
+	variant.dw2 = 0
+	for style, overridden := range map[string]bool{"none": true, "fsp": false} {
+		base.opts.FldStyleSet(style)
+		if base.padOverridden(base, pad, &variant) != overridden {
+			t.Errorf("%s: debounce: overridden is not %v", style, overridden)
+		}
+	}
+}
+
+func TestVariantFprint(t *testing.T) {
+	base := parse(t, "snr", config.TempInteltool, variantLog(
+		"0x0418: 0x0000001842880102 GPP_A3   GPIO",
+		"0x0420: 0x0000001840880102 GPP_A4   GPIO",
+		"0x0428: 0x0000001844000702 GPP_A5   LFRAME#",
+		"0x0430: 0x0000001844000702 GPP_A6   SERIRQ",
+	)...)
+	variant := parse(t, "snr", config.TempInteltool, variantLog(
+		"0x0418: 0x0000001842880100 GPP_A3   GPIO",
+		"0x0420: 0x0000001844000702 GPP_A4   LFRAME#",
+		"0x0430: 0x0000001844000702 GPP_A6   SERIRQ",
+		"0x0438: 0x0000001844000702 GPP_A7   PIRQA#",
+	)...)
+
+	var buf bytes.Buffer
+	count, err := variant.VariantFprint(&buf, base)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("got %d pads in the override table, want 2", count)
+	}
+	table := buf.String()
+	if end := strings.Index(table, "};"); end >= 0 {
+		table = table[:end]
+	}
+	for id, overridden := range map[string]bool{
+		"GPP_A3": false, "GPP_A4": true, "GPP_A6": false, "GPP_A7": true,
+	} {
+		if strings.Contains(table, id+",") != overridden {
+			t.Errorf("%s: overridden is not %v\n%s", id, overridden, table)
+		}
+	}
+	if !strings.Contains(buf.String(), " *   GPP_A5 - LFRAME#\n") {
+		t.Errorf("GPP_A5 is not listed as kept from the baseboard\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "variant_override_gpio_table(size_t *num)") {
+		t.Errorf("no variant_override_gpio_table()\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := base.BaseboardFprint(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"static const struct pad_config gpio_table[] = {\n",
+		"GPP_A3,", "GPP_A4,", "GPP_A5,", "GPP_A6,",
+		"variant_base_gpio_table(size_t *num)",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("baseboard has no %q\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Package intelp2m converts the pad configuration from the inteltool log or
+// from the board gpio.h into coreboot macros. The settings are passed
+// explicitly to each call and there is no global state, so the conversions
+// can run at the same time in one process.
+package intelp2m
+
+import (
+	"fmt"
+	"io"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/parser"
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+
+// Options - converter settings. The zero value converts the inteltool log of
+// the Sunrise PCH into high-level macros.
+// Platform     : snr, lbg, apl, cnl, tgl or adl (snr by default)
+// Template     : template type of the input, config.TempInteltool,
+//                config.TempGpioh, config.TempFsp, config.TempPinctrl or
+//                config.TempImage
+// FieldsStyle  : bit fields macros style: none, cb, fsp, raw or sbl (none by
+//                default)
+// InfoLevel    : information level in the gpio.h comments (0-4)
+// IgnoreFields : exclude ignored fields from advanced macros
+// NonChecking  : generate macros without checking
+type Options struct {
+	Platform     string
+	Template     int
+	FieldsStyle  string
+	InfoLevel    uint8
+	IgnoreFields bool
+	NonChecking  bool
+}
+
+// Pad - decoded pad configuration with the generated macro
+type Pad = parser.Pad
+
+// Diagnostic - problem found in the input, e.g. the line that was skipped
+// because it does not match the template
+type Diagnostic = parser.Diagnostic
+
+// configGet - converts the options to the converter settings
+// return
+//     settings and error
+func (opts *Options) configGet() (*config.Options, error) {
+	cfg := &config.Options{}
+	platform := opts.Platform
+	if platform == "" {
+		platform = "snr"
+	}
+	if cfg.PlatformSet(platform) != 0 {
+		return nil, fmt.Errorf("invalid platform %s", opts.Platform)
+	}
+	if !cfg.TemplateSet(opts.Template) || opts.Template < 0 {
+		return nil, fmt.Errorf("unknown template %d", opts.Template)
+	}
+	style := opts.FieldsStyle
+	if style == "" {
+		style = "none"
+	}
+	if cfg.FldStyleSet(style) != 0 {
+		return nil, fmt.Errorf("unknown bit fields style %s", opts.FieldsStyle)
+	}
+	if opts.InfoLevel > 4 {
+		return nil, fmt.Errorf("invalid info level %d", opts.InfoLevel)
+	}
+	cfg.InfoLevelSet(opts.InfoLevel)
+	cfg.IgnoredFieldsFlagSet(opts.IgnoreFields)
+	cfg.NonCheckingFlagSet(opts.NonChecking)
+	return cfg, nil
+}
+
+// parse - parses the input with the specified options
+// return
+//     parser data and error
+func parse(r io.Reader, opts Options) (*parser.ParserData, error) {
+	cfg, err := opts.configGet()
+	if err != nil {
+		return nil, err
+	}
+	data := parser.NewParserData(cfg, nil)
+	if err := data.Parse(r); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Convert - parses the pad configuration and generates a macro for each pad.
+// Group titles and reserved pads are skipped.
+// r    : reader of the inteltool log or gpio.h
+// opts : converter settings
+// return
+//     decoded pads
+//     problems found in the input, the pads from the skipped lines are not
+//     in the decoded pads
+//     error
+func Convert(r io.Reader, opts Options) ([]Pad, []Diagnostic, error) {
+	data, err := parse(r, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data.PadsGet(), data.DiagnosticsGet(), nil
+}
+
+// Generate - parses the pad configuration and writes gpio.h with the pad
+// configuration table, the same as the intelp2m utility does. The sbl style
+// writes the Slim Bootloader GPIO_CFG_DATA instead.
+// r    : reader of the inteltool log or gpio.h
+// w    : writer for gpio.h
+// opts : converter settings
+// return
+//     problems found in the input, as for Convert()
+//     error
+func Generate(r io.Reader, w io.Writer, opts Options) ([]Diagnostic, error) {
+	data, err := parse(r, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.FieldsStyle == "sbl" {
+		return data.DiagnosticsGet(), data.SblCfgDataFprint(w)
+	}
+	return data.DiagnosticsGet(), data.GpioHFprint(w)
+}
@@ -0,0 +1,104 @@
+package intelp2m_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/pkg/intelp2m"
+
+// sampleLog - inteltool log of the Sunrise PCH with two pads and a reserved pad
+var sampleLog = strings.Join([]string{
+	"============= GPIO =============",
+	"------- GPIO Community 0 -------",
+	"------- GPIO Group GPP_A -------",
+	"0x0400: 0x0000001840000400 GPP_A0   RCIN#",
+	"0x0408: 0x0000001840880100 GPP_A1   GPIO",
+	"0x0410: 0x00000000ffffffff GPP_A2   RESERVED",
+}, "\n")
+
+func TestConvert(t *testing.T) {
+	pads, diags, err := intelp2m.Convert(strings.NewReader(sampleLog), intelp2m.Options{})
+	if err != nil || len(diags) != 0 {
+		t.Fatalf("unexpected error: %v %v", err, diags)
+	}
+	if len(pads) != 2 {
+		t.Fatalf("got %d pads, want 2", len(pads))
+	}
+	tests := []struct {
+		id    string
+		mode  string
+		macro string
+	}{
+		{"GPP_A0", "NF1", "PAD_CFG_NF(GPP_A0, NONE, DEEP, NF1),"},
+		{"GPP_A1", "GPIO", "PAD_CFG_GPI_SCI(GPP_A1, NONE, DEEP, LEVEL, INVERT),"},
+	}
+	for i, test := range tests {
+		pad := pads[i]
+		if pad.Id != test.id || pad.Group != "GPP_A" || pad.Community != "GPIO Community 0" {
+			t.Errorf("pad %d: got %s %s %s", i, pad.Id, pad.Group, pad.Community)
+		}
+		if pad.Fields["mode"] != test.mode {
+			t.Errorf("%s: mode is %v, want %s", test.id, pad.Fields["mode"], test.mode)
+		}
+		if pad.Macro != test.macro {
+			t.Errorf("%s: got %s, want %s", test.id, pad.Macro, test.macro)
+		}
+	}
+}
+
+// TestConvertDiagnostics - the line that does not match the template is
+// returned in the diagnostics
+func TestConvertDiagnostics(t *testing.T) {
+	log := sampleLog + "\n0x0418: GPP_A3"
+	pads, diags, err := intelp2m.Convert(strings.NewReader(log), intelp2m.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pads) != 2 || len(diags) != 1 || diags[0].Line != 7 {
+		t.Errorf("got %d pads, diagnostics %+v", len(pads), diags)
+	}
+}
+
+func TestConvertOptions(t *testing.T) {
+	tests := []struct {
+		opts intelp2m.Options
+		err  string
+	}{
+		{intelp2m.Options{Platform: "skl"}, "invalid platform skl"},
+		{intelp2m.Options{Template: 9}, "unknown template 9"},
+		{intelp2m.Options{FieldsStyle: "edk"}, "unknown bit fields style edk"},
+		{intelp2m.Options{InfoLevel: 5}, "invalid info level 5"},
+	}
+	for _, test := range tests {
+		_, _, err := intelp2m.Convert(strings.NewReader(sampleLog), test.opts)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("%+v: got error %v, want %s", test.opts, err, test.err)
+		}
+	}
+}
+
+// TestGenerate - conversions with different settings run at the same time
+func TestGenerate(t *testing.T) {
+	styles := map[string]string{
+		"none": "PAD_CFG_NF(GPP_A0, NONE, DEEP, NF1),",
+		"cb":   "_PAD_CFG_STRUCT(GPP_A0,",
+	}
+	var wg sync.WaitGroup
+	for style, want := range styles {
+		wg.Add(1)
+		go func(style, want string) {
+			defer wg.Done()
+			var gpio strings.Builder
+			_, err := intelp2m.Generate(strings.NewReader(sampleLog), &gpio,
+				intelp2m.Options{FieldsStyle: style})
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", style, err)
+			} else if !strings.Contains(gpio.String(), want) {
+				t.Errorf("%s: no %s in\n%s", style, want, gpio.String())
+			}
+		}(style, want)
+	}
+	wg.Wait()
+}
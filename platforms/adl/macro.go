@@ -0,0 +1,38 @@
+package adl
+
+// Local packages
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+
+type InheritanceMacro interface {
+	GenMacro(id string, dw0 uint32, dw1 uint32, dw2 uint32, ownership uint8,
+		lock uint8, opts *config.Options) string
+	ReadOnlyFieldsGet(number uint8) uint32
+	PullEncode(pull string) (uint8, bool)
+	PullDecode(term uint8) (string, bool)
+	RstSrcEncode(id string, rst uint8) (uint8, bool)
+	NativeFunctionGet(id string, mode uint8) (bool, string)
+}
+
+// The GPIO controllers in Alder Lake-P/S and Tiger Lake have the same
+// registers and reset mapping, only the pad groups differ. Therefore, all
+// macros are generated by the Tiger Lake platform.
+type PlatformSpecific struct {
+	InheritanceMacro
+}
+
+// ReadOnlyFieldsGet - returns the mask of read-only fields
+// number : configuration register number
+func (platform PlatformSpecific) ReadOnlyFieldsGet(number uint8) uint32 {
+	return platform.InheritanceMacro.ReadOnlyFieldsGet(number)
+}
+
+// GenMacro - generate pad macro
+// dw0 : DW0 config register value
+// dw1 : DW1 config register value
+// dw2 : DW2 config register value
+// return: string of macro
+//         error
+func (platform PlatformSpecific) GenMacro(id string, dw0 uint32, dw1 uint32, dw2 uint32, ownership uint8,
+		lock uint8, opts *config.Options) string {
+	return platform.InheritanceMacro.GenMacro(id, dw0, dw1, dw2, ownership, lock, opts)
+}
@@ -0,0 +1,61 @@
+package adl_test
+
+import (
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/adl"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/apl"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/cnl"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/tgl"
+
+// TestGenMacro - Alder Lake generates the same macros as Tiger Lake
+func TestGenMacro(t *testing.T) {
+	tests := []struct {
+		dw0   uint32
+		dw1   uint32
+		dw2   uint32
+		macro string
+	}{
+		{0x40000400, 0x00003000, 0x0, "PAD_CFG_NF(GPP_F1, UP_20K, DEEP, NF1),"},
+		{0x82900100, 0x00000000, 0x0,
+			"PAD_CFG_GPI_APIC(GPP_F1, NONE, PLTRST, EDGE_SINGLE, INVERT),"},
+		{0x82900100, 0x00000000, 0x9, "_PAD_CFG_STRUCT_3(GPP_F1, PAD_FUNC(GPIO) | " +
+			"PAD_RESET(PLTRST) | PAD_TRIG(EDGE_SINGLE) | PAD_IRQ_ROUTE(IOAPIC) | " +
+			"PAD_RX_POL(INVERT) | PAD_BUF(TX_DISABLE), 0, " +
+			"PAD_CFG2_DEBEN | PAD_CFG2_DEBOUNCE_16_RTC),"},
+	}
+	opts := &config.Options{}
+	opts.PlatformSet("adl")
+	opts.FldStyleSet("none")
+	platform := adl.PlatformSpecific{
+		InheritanceMacro: tgl.PlatformSpecific{
+			InheritanceMacro: cnl.PlatformSpecific{InheritanceMacro: apl.PlatformSpecific{}},
+		},
+	}
+	for _, test := range tests {
+		macro := platform.GenMacro("GPP_F1", test.dw0, test.dw1, test.dw2,
+			common.PAD_OWN_ACPI, common.PAD_LOCK_DEFAULT, opts)
+		if macro != test.macro {
+			t.Errorf("0x%08x 0x%08x 0x%08x: got %s, want %s", test.dw0, test.dw1, test.dw2,
+				macro, test.macro)
+		}
+	}
+	if ro := platform.ReadOnlyFieldsGet(common.PAD_CFG_DW2); ro != 0xffffffe0 {
+		t.Errorf("got DW2 read-only mask 0x%08x", ro)
+	}
+}
+
+// TestNativeFunctionGet - Alder Lake uses the Tiger Lake table
+func TestNativeFunctionGet(t *testing.T) {
+	platform := adl.PlatformSpecific{
+		InheritanceMacro: tgl.PlatformSpecific{
+			InheritanceMacro: cnl.PlatformSpecific{InheritanceMacro: apl.PlatformSpecific{}},
+		},
+	}
+	if known, name := platform.NativeFunctionGet("GPP_B23", 2); !known || name != "PCHHOT#" {
+		t.Errorf("GPP_B23 NF2: got %v %q, want true PCHHOT#", known, name)
+	}
+}
@@ -0,0 +1,105 @@
+package adl
+
+import "strings"
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// gpeGroups - Alder Lake-P group numbers for the GPE0 routing in the MISCCFG register
+var gpeGroups = map[uint8]string{
+	0x0: "GPP_B",
+	0x1: "GPP_T",
+	0x2: "GPP_A",
+	0x3: "GPP_R",
+	0x4: "GPD",
+	0x5: "GPP_S",
+	0x6: "GPP_H",
+	0x7: "GPP_D",
+	0xa: "GPP_F",
+	0xb: "GPP_C",
+	0xc: "GPP_E",
+}
+
+// acpiPinBases - Alder Lake-P ACPI pin numbers of the first pads in the groups,
+// see pinctrl-alderlake.c in Linux
+var acpiPinBases = map[string]uint{
+	"GPP_B": 0,
+	"GPP_T": 32,
+	"GPP_A": 64,
+	"GPP_S": 96,
+	"GPP_I": 128,
+	"GPP_H": 160,
+	"GPP_D": 192,
+	"vGPIO": 224,
+	"GPP_C": 256,
+	"GPP_F": 288,
+	"GPP_E": 320,
+	"GPP_R": 352,
+}
+
+// GroupNameExtract - This function extracts the group ID, if it exists in a row
+// line      : string from the configuration file
+// return
+//     bool   : true if the string contains a group identifier
+//     string : group identifier
+func (PlatformSpecific) GroupNameExtract(line string) (bool, string) {
+	// inteltool prints the virtual GPIO group name in upper case in the
+	// register names, e.g. HOSTSW_OWN_VGPIO
+	if strings.Contains(line, "vGPIO") || strings.Contains(line, "VGPIO") {
+		return true, "vGPIO"
+	}
+	// Alder Lake-P and Alder Lake-S groups
+	for _, groupKeyword := range []string{
+		"GPP_A", "GPP_B", "GPP_C",
+		"GPP_D", "GPP_E", "GPP_F",
+		"GPP_G", "GPP_H", "GPP_I",
+		"GPP_J", "GPP_K", "GPP_R",
+		"GPP_S", "GPP_T", "GPD",
+	} {
+		if strings.Contains(line, groupKeyword) {
+			return true, groupKeyword
+		}
+	}
+	return false, ""
+}
+
+// KeywordCheck - This function is used to filter parsed lines of the configuration file and
+//                returns true if the keyword is contained in the line.
+// line      : string from the configuration file
+func (PlatformSpecific) KeywordCheck(line string) bool {
+	for _, keyword := range []string{
+		"GPP_", "GPD", "vGPIO",
+	} {
+		if strings.Contains(line, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// GpeGroupGet - returns the group name for the group number in the GPE0_DW0/1/2
+// fields of the MISCCFG register, as defined in coreboot soc/intel/alderlake/include/soc/gpio_defs.h
+// number : group number
+// return
+//     bool   : true if the group number is known
+//     string : group identifier
+func (PlatformSpecific) GpeGroupGet(number uint8) (bool, string) {
+	group, valid := gpeGroups[number]
+	return valid, group
+}
+
+// AcpiPinGet - returns the ACPI path of the GPIO controller and the pin number
+// of the pad. Each group starts at a multiple of 32, the groups without the
+// pin numbers (e.g. GPD) have no ACPI resources. The pin numbers of
+// the -S PCH with the GPP_G, GPP_J and GPP_K groups are not known.
+// pad : pad position
+// return
+//     string : ACPI path of the GPIO controller
+//     string : pin number
+//     bool   : false if the pin number is unknown
+func (PlatformSpecific) AcpiPinGet(pad common.PadPosition) (string, string, bool) {
+	if pad.Groups["GPP_G"] || pad.Groups["GPP_J"] || pad.Groups["GPP_K"] {
+		return "", "", false
+	}
+	pin, valid := common.AcpiGroupPinGet(acpiPinBases, pad)
+	return common.AcpiGpioController, pin, valid
+}
@@ -0,0 +1,45 @@
+package apl
+
+// PullEncode - returns the Pad Termination (TERM) field value for the pull
+// configuration used in the macro
+// name : pull configuration from the macro, e.g. UP_20K
+func (PlatformSpecific) PullEncode(name string) (uint8, bool) {
+	for term, str := range pull {
+		if str == name {
+			return term, true
+		}
+	}
+	return 0, false
+}
+
+// PullDecode - returns the pull configuration name used in the macro for the
+// Pad Termination (TERM) field value
+// term : TERM field value
+func (PlatformSpecific) PullDecode(term uint8) (string, bool) {
+	str, valid := pull[term]
+	return str, valid
+}
+
+// RstSrcEncode - returns the Pad Reset Source Config (PADRSTCFG) field value
+// for the logical reset source used in the macro
+// id  : pad id string
+// rst : logical reset source
+func (PlatformSpecific) RstSrcEncode(id string, rst uint8) (uint8, bool) {
+	// remmap is not required because it is the same as common,
+	// but RSMRST is not supported
+	if _, valid := resetsrc[rst]; !valid {
+		return 0, false
+	}
+	return rst, true
+}
+
+// NativeFunctionGet - returns the signal name of the native function
+// id   : pad ID string
+// mode : pad mode (PMODE), 1 for NF1
+// return
+//     bool   : true if the pad is in the table
+//     string : signal name, empty if the pad mode has no function
+func (PlatformSpecific) NativeFunctionGet(id string, mode uint8) (bool, string) {
+	// Not supported
+	return false, ""
+}
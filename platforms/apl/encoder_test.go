@@ -0,0 +1,55 @@
+package apl_test
+
+import (
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/apl"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+func TestMacroEncode(t *testing.T) {
+	tests := []struct {
+		macro string
+		dw0   uint32
+		dw1   uint32
+	}{
+		{"PAD_CFG_NF(GPIO_0, UP_20K, DEEP, NF1),", 0x40000400, 0x00003000},
+		{"PAD_CFG_GPO(GPIO_1, 1, DEEP),", 0x44000201, 0x00000000},
+		{"PAD_NC(GPIO_4, UP_20K),", 0x44000300, 0x00027000},
+		// sciRoute() takes the pull and IOSSTATE from DW1
+		{"PAD_CFG_GPI_ACPI_SCI(GPIO_2, UP_20K, DEEP, INVERT),", 0x42880100, 0x00003000},
+		// PAD_CFG_GPI_APIC is closed once
+		{"PAD_CFG_GPI_APIC(GPIO_3, NONE, DEEP, LEVEL, INVERT),", 0x40900100, 0x00000000},
+	}
+	opts := &config.Options{}
+	opts.PlatformSet("apl")
+	opts.FldStyleSet("none")
+	for _, test := range tests {
+		cfg, err := common.MacroEncode(apl.PlatformSpecific{}, test.macro)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.macro, err)
+			continue
+		}
+		dw0 := cfg.Register(common.PAD_CFG_DW0).ValueGet()
+		dw1 := cfg.Register(common.PAD_CFG_DW1).ValueGet()
+		if dw0 != test.dw0 || dw1 != test.dw1 {
+			t.Errorf("%s: got 0x%08x 0x%08x, want 0x%08x 0x%08x", test.macro, dw0, dw1,
+				test.dw0, test.dw1)
+		}
+		macro := apl.PlatformSpecific{}.GenMacro(cfg.Id, dw0, dw1, 0, cfg.Ownership,
+			common.PAD_LOCK_DEFAULT, opts)
+		if macro != test.macro {
+			t.Errorf("%s: generated %s", test.macro, macro)
+		}
+	}
+
+	for _, macro := range []string{
+		"PAD_CFG_NF(GPIO_0, 20K_PU, DEEP, NF1),",
+		"PAD_CFG_GPO(GPIO_1, 1, RSMRST),",
+	} {
+		if _, err := common.MacroEncode(apl.PlatformSpecific{}, macro); err == nil {
+			t.Errorf("%s: no error", macro)
+		}
+	}
+}
@@ -1,12 +1,11 @@
 package apl
 
-import "fmt"
 import "strconv"
 
 // Local packages
-import "../common"
-import "../../config"
-import "../../fields"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/fields"
 
 const (
 	PAD_CFG_DW0_RO_FIELDS = (0x1 << 27) | (0x1 << 24) | (0x3 << 21) | (0xf << 16) | 0xfc
@@ -31,29 +30,40 @@ const (
 	PULL_NATIVE  = 0xf  // 1 111: (optional) Native controller selected by Pad Mode
 )
 
+// Pad Termination (TERM) field values
+var pull = map[uint8]string{
+	PULL_NONE:   "NONE",
+	PULL_DN_5K:  "DN_5K",
+	PULL_DN_20K: "DN_20K",
+	PULL_UP_1K:  "UP_1K",
+	PULL_UP_2K:  "UP_2K",
+	PULL_UP_20K: "UP_20K",
+	PULL_UP_667: "UP_667",
+	PULL_NATIVE: "NATIVE",
+}
+
+// See src/soc/intel/apollolake/gpio_apl.c:
+// static const struct reset_mapping rst_map[] = {
+// { .logical = PAD_CFG0_LOGICAL_RESET_PWROK,  .chipset = 0U << 30 },
+// { .logical = PAD_CFG0_LOGICAL_RESET_DEEP,   .chipset = 1U << 30 },
+// { .logical = PAD_CFG0_LOGICAL_RESET_PLTRST, .chipset = 2U << 30 },
+// };
+var resetsrc = map[uint8]string{
+	0: "PWROK",
+	1: "DEEP",
+	2: "PLTRST",
+}
+
 type PlatformSpecific struct {}
 
 // RemmapRstSrc - remmap Pad Reset Source Config
 // remmap is not required because it is the same as common.
-func (PlatformSpecific) RemmapRstSrc() {}
+func (PlatformSpecific) RemmapRstSrc(macro *common.Macro) {}
 
 // Adds the PADRSTCFG parameter from DW0 to the macro as a new argument
 // return: macro
-func (PlatformSpecific) Rstsrc() {
-	macro := common.GetMacro()
+func (PlatformSpecific) Rstsrc(macro *common.Macro) {
 	dw0 := macro.Register(PAD_CFG_DW0)
-	// See src/soc/intel/apollolake/gpio_apl.c:
-	// static const struct reset_mapping rst_map[] = {
-	// { .logical = PAD_CFG0_LOGICAL_RESET_PWROK,  .chipset = 0U << 30 },
-	// { .logical = PAD_CFG0_LOGICAL_RESET_DEEP,   .chipset = 1U << 30 },
-	// { .logical = PAD_CFG0_LOGICAL_RESET_PLTRST, .chipset = 2U << 30 },
-	// };
-
-	var resetsrc = map[uint8]string{
-		0: "PWROK",
-		1: "DEEP",
-		2: "PLTRST",
-	}
 	str, valid := resetsrc[dw0.GetResetConfig()]
 	if !valid {
 			// 3h = Reserved (implement as setting 0h)
@@ -65,32 +75,19 @@ func (PlatformSpecific) Rstsrc() {
 
 // Adds The Pad Termination (TERM) parameter from DW1 to the macro as a new argument
 // return: macro
-func (PlatformSpecific) Pull() {
-	macro := common.GetMacro()
+func (PlatformSpecific) Pull(macro *common.Macro) {
 	dw1 := macro.Register(PAD_CFG_DW1)
-	var pull = map[uint8]string{
-		PULL_NONE:   "NONE",
-		PULL_DN_5K:  "DN_5K",
-		PULL_DN_20K: "DN_20K",
-		PULL_UP_1K:  "UP_1K",
-		PULL_UP_2K:  "UP_2K",
-		PULL_UP_20K: "UP_20K",
-		PULL_UP_667: "UP_667",
-		PULL_NATIVE: "NATIVE",
-
-	}
 	terminationFieldValue := dw1.GetTermination()
 	str, valid := pull[terminationFieldValue]
 	if !valid {
+		// the parser reports the invalid value
 		str = strconv.Itoa(int(terminationFieldValue))
-		fmt.Println("Error", macro.PadIdGet(), " invalid TERM value = ", str)
 	}
 	macro.Separator().Add(str)
 }
 
 // Generate macro to cause peripheral IRQ when configured in GPIO input mode
-func ioApicRoute() bool {
-	macro := common.GetMacro()
+func ioApicRoute(macro *common.Macro) bool {
 	dw0 := macro.Register(PAD_CFG_DW0)
 	dw1 := macro.Register(PAD_CFG_DW1)
 	if dw0.GetGPIOInputRouteIOxAPIC() == 0 {
@@ -103,15 +100,14 @@ func ioApicRoute() bool {
 		macro.Add("_IOS(").Id().Pull().Rstsrc().Trig().Invert().IOSstate().IOTerm()
 	} else {
 		// PAD_CFG_GPI_APIC(pad, pull, rst, trig, inv)
-		macro.Add("(").Id().Pull().Rstsrc().Trig().Invert().Add("),")
+		macro.Add("(").Id().Pull().Rstsrc().Trig().Invert()
 	}
 	macro.Add("),")
 	return true
 }
 
 // Generate macro to cause NMI when configured in GPIO input mode
-func nmiRoute() bool {
-	macro := common.GetMacro()
+func nmiRoute(macro *common.Macro) bool {
 	if macro.Register(PAD_CFG_DW0).GetGPIOInputRouteNMI() == 0 {
 		return false
 	}
@@ -121,10 +117,9 @@ func nmiRoute() bool {
 }
 
 // Generate macro to cause SCI when configured in GPIO input mode
-func sciRoute() bool {
-	macro := common.GetMacro()
+func sciRoute(macro *common.Macro) bool {
 	dw0 := macro.Register(PAD_CFG_DW0)
-	dw1 := macro.Register(PAD_CFG_DW0)
+	dw1 := macro.Register(PAD_CFG_DW1)
 	if dw0.GetGPIOInputRouteSCI() == 0 {
 		return false
 	}
@@ -144,8 +139,7 @@ func sciRoute() bool {
 }
 
 // Generate macro to cause SMI when configured in GPIO input mode
-func smiRoute() bool {
-	macro := common.GetMacro()
+func smiRoute(macro *common.Macro) bool {
 	dw0 := macro.Register(PAD_CFG_DW0)
 	dw1 := macro.Register(PAD_CFG_DW1)
 	if dw0.GetGPIOInputRouteSMI() == 0 {
@@ -167,17 +161,16 @@ func smiRoute() bool {
 }
 
 // Generate macro for GPI port
-func (PlatformSpecific) GpiMacroAdd() {
-	macro := common.GetMacro()
+func (PlatformSpecific) GpiMacroAdd(macro *common.Macro) {
 	var ids []string
 	macro.Set("PAD_CFG_GPI")
-	for routeid, isRoute := range map[string]func() (bool) {
+	for routeid, isRoute := range map[string]func(macro *common.Macro) bool {
 		"IOAPIC": ioApicRoute,
 		"SCI":    sciRoute,
 		"SMI":    smiRoute,
 		"NMI":    nmiRoute,
 	} {
-		if isRoute() {
+		if isRoute(macro) {
 			ids = append(ids, routeid)
 		}
 	}
@@ -201,14 +194,14 @@ func (PlatformSpecific) GpiMacroAdd() {
 		}
 	case 1:
 		// GPI with IRQ route
-		if config.AreFieldsIgnored() {
+		if macro.Options.AreFieldsIgnored() {
 			macro.SetPadOwnership(common.PAD_OWN_ACPI)
 		}
 	case 2:
 		// PAD_CFG_GPI_DUAL_ROUTE(pad, pull, rst, trig, inv, route1, route2)
 		macro.Set("PAD_CFG_GPI_DUAL_ROUTE(").Id().Pull().Rstsrc().Trig().Invert()
 		macro.Add(", " + ids[0] + ", " + ids[1] + "),")
-		if config.AreFieldsIgnored() {
+		if macro.Options.AreFieldsIgnored() {
 			macro.SetPadOwnership(common.PAD_OWN_ACPI)
 		}
 	default:
@@ -220,8 +213,7 @@ func (PlatformSpecific) GpiMacroAdd() {
 
 
 // Adds PAD_CFG_GPO macro with arguments
-func (PlatformSpecific) GpoMacroAdd() {
-	macro := common.GetMacro()
+func (PlatformSpecific) GpoMacroAdd(macro *common.Macro) {
 	dw0 :=  macro.Register(PAD_CFG_DW0)
 	dw1 :=  macro.Register(PAD_CFG_DW1)
 	term := dw1.GetTermination()
@@ -252,8 +244,7 @@ func (PlatformSpecific) GpoMacroAdd() {
 }
 
 // Adds PAD_CFG_NF macro with arguments
-func (PlatformSpecific) NativeFunctionMacroAdd() {
-	macro := common.GetMacro()
+func (PlatformSpecific) NativeFunctionMacroAdd(macro *common.Macro) {
 	dw1 := macro.Register(PAD_CFG_DW1)
 	isIOStandbyStateUsed := dw1.GetIOStandbyState() != 0
 	isIOStandbyTerminationUsed := dw1.GetIOStandbyTermination() != 0
@@ -284,8 +275,7 @@ func (PlatformSpecific) NativeFunctionMacroAdd() {
 }
 
 // Adds PAD_NC macro
-func (PlatformSpecific) NoConnMacroAdd() {
-	macro := common.GetMacro()
+func (PlatformSpecific) NoConnMacroAdd(macro *common.Macro) {
 	dw1 := macro.Register(PAD_CFG_DW1)
 
 	if dw1.GetIOStandbyState() == common.TxDRxE {
@@ -312,17 +302,26 @@ func (PlatformSpecific) NoConnMacroAdd() {
 	macro.Add("HI_Z(").Id().Pull().Rstsrc().IOSstate().IOTerm().Add("),")
 }
 
+// ReadOnlyFieldsGet - returns the mask of read-only fields
+// number : configuration register number
+func (PlatformSpecific) ReadOnlyFieldsGet(number uint8) uint32 {
+	// There are no DW2/DW3 registers on this platform
+	var ro = [MAX_DW_NUM]uint32{PAD_CFG_DW0_RO_FIELDS, PAD_CFG_DW1_RO_FIELDS,
+			common.AllFields, common.AllFields}
+	return ro[number]
+}
+
 // GenMacro - generate pad macro
 // dw0 : DW0 config register value
 // dw1 : DW1 config register value
+// dw2 : DW2 config register value (not used on this platform)
 // return: string of macro
 //         error
-func (PlatformSpecific) GenMacro(id string, dw0 uint32, dw1 uint32, ownership uint8) string {
-	macro := common.GetInstanceMacro(PlatformSpecific{}, fields.InterfaceGet())
+func (PlatformSpecific) GenMacro(id string, dw0 uint32, dw1 uint32, dw2 uint32, ownership uint8,
+		lock uint8, opts *config.Options) string {
+	macro := common.NewMacro(PlatformSpecific{}, fields.InterfaceGet(opts), opts)
 	// use platform-specific interface in Macro struct
-	macro.PadIdSet(id).SetPadOwnership(ownership)
-	macro.Register(PAD_CFG_DW0).CntrMaskFieldsClear(common.AllFields)
-	macro.Register(PAD_CFG_DW0).CntrMaskFieldsClear(common.AllFields)
+	macro.PadIdSet(id).SetPadOwnership(ownership).SetPadLock(lock)
 	macro.Register(PAD_CFG_DW0).ValueSet(dw0).ReadOnlyFieldsSet(PAD_CFG_DW0_RO_FIELDS)
 	macro.Register(PAD_CFG_DW1).ValueSet(dw1).ReadOnlyFieldsSet(PAD_CFG_DW1_RO_FIELDS)
 	return macro.Generate()
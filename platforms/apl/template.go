@@ -1,6 +1,11 @@
 package apl
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
 
 // GroupNameExtract - This function extracts the group ID, if it exists in a row
 // line      : string from the configuration file
@@ -33,3 +38,30 @@ func (PlatformSpecific) KeywordCheck(line string) bool {
 	}
 	return false
 }
+
+// GpeGroupGet - returns the group name for the group number in the GPE0_DW1/2/3
+// fields of the GPIO_GPE_CFG register
+// number : group number
+// return
+//     bool   : true if the group number is known
+//     string : group identifier
+func (PlatformSpecific) GpeGroupGet(number uint8) (bool, string) {
+	// Not supported
+	return false, ""
+}
+
+// AcpiPinGet - returns the ACPI path of the GPIO controller and the pin number
+// of the pad. Each community is a separate controller (\_SB.GPO0 - North,
+// GPO1 - Northwest, GPO2 - West, GPO3 - Southwest) and the pins are numbered
+// in the community, so the position in the inteltool log is required.
+// pad : pad position
+// return
+//     string : ACPI path of the GPIO controller
+//     string : pin number
+//     bool   : false if the pin number is unknown
+func (PlatformSpecific) AcpiPinGet(pad common.PadPosition) (string, string, bool) {
+	if !pad.Dump || pad.Community < 0 || pad.Community > 3 {
+		return "", "", false
+	}
+	return fmt.Sprintf("\\_SB.GPO%d", pad.Community), fmt.Sprintf("%d", pad.Index), true
+}
@@ -0,0 +1,33 @@
+package apl_test
+
+import (
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/apl"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+func TestAcpiPinGet(t *testing.T) {
+	tests := []struct {
+		pad        common.PadPosition
+		controller string
+		pin        string
+		valid      bool
+	}{
+		{common.PadPosition{Id: "GPIO_0", Dump: true, Community: 0, Index: 0},
+			"\\_SB.GPO0", "0", true},
+		{common.PadPosition{Id: "GPIO_77", Dump: true, Community: 1, Index: 2, Global: 80},
+			"\\_SB.GPO1", "2", true},
+		// the communities are not known without the register dump
+		{common.PadPosition{Id: "GPIO_77", Community: 1, Index: 2}, "", "", false},
+		{common.PadPosition{Id: "GPIO_77", Dump: true, Community: -1, Index: 2},
+			"", "", false},
+	}
+	for _, test := range tests {
+		controller, pin, valid := apl.PlatformSpecific{}.AcpiPinGet(test.pad)
+		if controller != test.controller || pin != test.pin || valid != test.valid {
+			t.Errorf("%+v: got %s %s %v, want %s %s %v", test.pad, controller, pin, valid,
+				test.controller, test.pin, test.valid)
+		}
+	}
+}
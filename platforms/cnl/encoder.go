@@ -0,0 +1,43 @@
+package cnl
+
+import "strings"
+
+// Local packages
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// PullEncode - returns the Pad Termination (TERM) field value for the pull
+// configuration used in the macro
+// name : pull configuration from the macro, e.g. UP_20K
+func (PlatformSpecific) PullEncode(name string) (uint8, bool) {
+	for term, str := range pull {
+		if str == name {
+			return term, true
+		}
+	}
+	return 0, false
+}
+
+// PullDecode - returns the pull configuration name used in the macro for the
+// Pad Termination (TERM) field value
+// term : TERM field value
+func (PlatformSpecific) PullDecode(term uint8) (string, bool) {
+	str, valid := pull[term]
+	return str, valid
+}
+
+// RstSrcEncode - returns the Pad Reset Source Config (PADRSTCFG) field value
+// for the logical reset source used in the macro
+// id  : pad id string
+// rst : logical reset source
+func (PlatformSpecific) RstSrcEncode(id string, rst uint8) (uint8, bool) {
+	if strings.Contains(id, "GPD") {
+		// See RemmapRstSrc()
+		return rst, true
+	}
+	for rstcfg, logical := range remapping {
+		if logical == uint32(rst) << common.PadRstCfgShift {
+			return rstcfg, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,147 @@
+package cnl
+
+import "strconv"
+import "strings"
+
+// Local packages
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/fields"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/apl"
+
+const (
+	PAD_CFG_DW0_RO_FIELDS = (0x1 << 27) | (0x1 << 24) | (0x3 << 21) | (0xf << 16) | 0xfc
+	PAD_CFG_DW1_RO_FIELDS = 0xfdfc00ff
+)
+
+const (
+	PAD_CFG_DW0 = common.PAD_CFG_DW0
+	PAD_CFG_DW1 = common.PAD_CFG_DW1
+	MAX_DW_NUM  = common.MAX_DW_NUM
+)
+
+const (
+	PULL_NONE    = 0x0  // 0 000: none
+	PULL_DN_5K   = 0x2  // 0 010: 5k wpd
+	PULL_DN_20K  = 0x4  // 0 100: 20k wpd
+	PULL_UP_1K   = 0x9  // 1 001: 1k wpu
+	PULL_UP_5K   = 0xa  // 1 010: 5k wpu
+	PULL_UP_2K   = 0xb  // 1 011: 2k wpu
+	PULL_UP_20K  = 0xc  // 1 100: 20k wpu
+	PULL_UP_667  = 0xd  // 1 101: 1k & 2k wpu
+	PULL_NATIVE  = 0xf  // 1 111: Native controller selected by Pad Mode
+)
+
+// Pad Termination (TERM) field values
+var pull = map[uint8]string{
+	PULL_NONE:   "NONE",
+	PULL_DN_5K:  "DN_5K",
+	PULL_DN_20K: "DN_20K",
+	PULL_UP_1K:  "UP_1K",
+	PULL_UP_5K:  "UP_5K",
+	PULL_UP_2K:  "UP_2K",
+	PULL_UP_20K: "UP_20K",
+	PULL_UP_667: "UP_667",
+	PULL_NATIVE: "NATIVE",
+}
+
+// Pad Reset Source Config remapping: PADRSTCFG field value -> logical reset
+// See src/soc/intel/cannonlake/gpio.c:
+// static const struct reset_mapping rst_map[] = {
+// { .logical = PAD_CFG0_LOGICAL_RESET_RSMRST, .chipset = 0U << 30 },
+// { .logical = PAD_CFG0_LOGICAL_RESET_DEEP,   .chipset = 1U << 30 },
+// { .logical = PAD_CFG0_LOGICAL_RESET_PLTRST, .chipset = 2U << 30 },
+// };
+var remapping = map[uint8]uint32{
+	0: common.RST_RSMRST << common.PadRstCfgShift,
+	1: common.RST_DEEP   << common.PadRstCfgShift,
+	2: common.RST_PLTRST << common.PadRstCfgShift,
+}
+
+type InheritanceMacro interface {
+	GpiMacroAdd(macro *common.Macro)
+	GpoMacroAdd(macro *common.Macro)
+	NativeFunctionMacroAdd(macro *common.Macro)
+	NoConnMacroAdd(macro *common.Macro)
+}
+
+type PlatformSpecific struct {
+	InheritanceMacro
+}
+
+// RemmapRstSrc - remmap Pad Reset Source Config
+func (PlatformSpecific) RemmapRstSrc(macro *common.Macro) {
+	if strings.Contains(macro.PadIdGet(), "GPD") {
+		// See rst_map_com2[] for the GPD group in the Community 2:
+		// remmap is not required because it is the same as common.
+		return
+	}
+
+	dw0 := macro.Register(PAD_CFG_DW0)
+	resetsrc, valid := remapping[dw0.GetResetConfig()]
+	if valid {
+		ResetConfigFieldVal := (dw0.ValueGet() & 0x3fffffff) | resetsrc
+		dw0.ValueSet(ResetConfigFieldVal)
+	}
+	dw0.CntrMaskFieldsClear(common.PadRstCfgMask)
+}
+
+// Adds The Pad Termination (TERM) parameter from PAD_CFG_DW1 to the macro
+// as a new argument
+func (PlatformSpecific) Pull(macro *common.Macro) {
+	dw1 := macro.Register(PAD_CFG_DW1)
+	terminationFieldValue := dw1.GetTermination()
+	str, valid := pull[terminationFieldValue]
+	if !valid {
+		// the parser reports the invalid value
+		str = strconv.Itoa(int(terminationFieldValue))
+	}
+	macro.Separator().Add(str)
+}
+
+// Adds PAD_CFG_GPI macro with arguments
+func (platform PlatformSpecific) GpiMacroAdd(macro *common.Macro) {
+	platform.InheritanceMacro.GpiMacroAdd(macro)
+}
+
+// Adds PAD_CFG_GPO macro with arguments
+func (platform PlatformSpecific) GpoMacroAdd(macro *common.Macro) {
+	platform.InheritanceMacro.GpoMacroAdd(macro)
+}
+
+// Adds PAD_CFG_NF macro with arguments
+func (platform PlatformSpecific) NativeFunctionMacroAdd(macro *common.Macro) {
+	platform.InheritanceMacro.NativeFunctionMacroAdd(macro)
+}
+
+// Adds PAD_NC macro
+func (platform PlatformSpecific) NoConnMacroAdd(macro *common.Macro) {
+	platform.InheritanceMacro.NoConnMacroAdd(macro)
+}
+
+// ReadOnlyFieldsGet - returns the mask of read-only fields
+// number : configuration register number
+func (PlatformSpecific) ReadOnlyFieldsGet(number uint8) uint32 {
+	// There are no DW2/DW3 registers on this platform
+	var ro = [MAX_DW_NUM]uint32{PAD_CFG_DW0_RO_FIELDS, PAD_CFG_DW1_RO_FIELDS,
+			common.AllFields, common.AllFields}
+	return ro[number]
+}
+
+// GenMacro - generate pad macro
+// dw0 : DW0 config register value
+// dw1 : DW1 config register value
+// dw2 : DW2 config register value (not used on this platform)
+// return: string of macro
+//         error
+func (PlatformSpecific) GenMacro(id string, dw0 uint32, dw1 uint32, dw2 uint32, ownership uint8,
+		lock uint8, opts *config.Options) string {
+	// Cannon Lake uses the macros from the common block as Apollo Lake does,
+	// so we will inherit some platform-dependent functions from Apollo Lake.
+	macro := common.NewMacro(PlatformSpecific{InheritanceMacro : apl.PlatformSpecific{}},
+			fields.InterfaceGet(opts), opts)
+	macro.PadIdSet(id).SetPadOwnership(ownership).SetPadLock(lock)
+	macro.Register(PAD_CFG_DW0).ValueSet(dw0).ReadOnlyFieldsSet(PAD_CFG_DW0_RO_FIELDS)
+	macro.Register(PAD_CFG_DW1).ValueSet(dw1).ReadOnlyFieldsSet(PAD_CFG_DW1_RO_FIELDS)
+	return macro.Generate()
+}
@@ -0,0 +1,57 @@
+package cnl_test
+
+import (
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/apl"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/cnl"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+func TestGenMacro(t *testing.T) {
+	tests := []struct {
+		id    string
+		dw0   uint32
+		dw1   uint32
+		macro string
+	}{
+		{"GPP_A1", 0x40000800, 0x00003c00, "PAD_CFG_NF(GPP_A1, NATIVE, DEEP, NF2),"},
+		{"GPP_A2", 0x84000201, 0x00000000, "PAD_CFG_GPO(GPP_A2, 1, PLTRST),"},
+		{"GPP_A3", 0x44000200, 0x00003000, "PAD_CFG_TERM_GPO(GPP_A3, 0, UP_20K, DEEP),"},
+		{"GPP_A4", 0x40880100, 0x00003000,
+			"PAD_CFG_GPI_SCI(GPP_A4, UP_20K, DEEP, LEVEL, INVERT),"},
+		{"GPP_A5", 0x42880100, 0x00003000, "PAD_CFG_GPI_ACPI_SCI(GPP_A5, UP_20K, DEEP, INVERT),"},
+		{"GPP_A6", 0x82900100, 0x00000000,
+			"PAD_CFG_GPI_APIC(GPP_A6, NONE, PLTRST, EDGE_SINGLE, INVERT),"},
+		{"GPP_A7", 0x40000100, 0x00000000,
+			"PAD_CFG_GPI_TRIG_OWN(GPP_A7, NONE, DEEP, LEVEL, ACPI),"},
+		// PADRSTCFG 0 is RSMRST, but PWROK in the GPD group
+		{"GPP_A8", 0x04000201, 0x00000000, "PAD_CFG_GPO(GPP_A8, 1, RSMRST),"},
+		{"GPD3", 0x04000201, 0x00000000, "PAD_CFG_GPO(GPD3, 1, PWROK),"},
+		{"GPP_A9", 0x44000700, 0x00000000, "_PAD_CFG_STRUCT(GPP_A9, PAD_FUNC(NF1) | " +
+			"PAD_RESET(DEEP) | PAD_TRIG(OFF) | PAD_BUF(TX_RX_DISABLE), 0),"},
+	}
+	opts := &config.Options{}
+	opts.PlatformSet("cnl")
+	opts.FldStyleSet("none")
+	platform := cnl.PlatformSpecific{InheritanceMacro: apl.PlatformSpecific{}}
+	for _, test := range tests {
+		macro := platform.GenMacro(test.id, test.dw0, test.dw1, 0, common.PAD_OWN_ACPI,
+			common.PAD_LOCK_DEFAULT, opts)
+		if macro != test.macro {
+			t.Errorf("0x%08x 0x%08x: got %s, want %s", test.dw0, test.dw1, macro, test.macro)
+			continue
+		}
+		cfg, err := common.MacroEncode(platform, macro)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", macro, err)
+			continue
+		}
+		dw0 := cfg.Register(common.PAD_CFG_DW0).ValueGet()
+		dw1 := cfg.Register(common.PAD_CFG_DW1).ValueGet()
+		if dw0 != test.dw0 || dw1 != test.dw1 {
+			t.Errorf("%s: encoded 0x%08x 0x%08x", macro, dw0, dw1)
+		}
+	}
+}
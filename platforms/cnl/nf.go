@@ -0,0 +1,73 @@
+package cnl
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// nativeFunctions - Cannon Lake-LP native functions, see the "GPIO Signal
+// Multiplexing" table in the PCH datasheet. The pads that are not in the table
+// are not checked.
+var nativeFunctions = common.NativeFunctions{
+	"GPP_A0":  {"RCIN#"},
+	"GPP_A1":  {"LAD0", "ESPI_IO0"},
+	"GPP_A2":  {"LAD1", "ESPI_IO1"},
+	"GPP_A3":  {"LAD2", "ESPI_IO2"},
+	"GPP_A4":  {"LAD3", "ESPI_IO3"},
+	"GPP_A5":  {"LFRAME#", "ESPI_CS#"},
+	"GPP_A6":  {"SERIRQ"},
+	"GPP_A7":  {"PIRQA#", "GSPI0_CS1#"},
+	"GPP_A8":  {"CLKRUN#"},
+	"GPP_A9":  {"CLKOUT_LPC0", "ESPI_CLK"},
+	"GPP_A10": {"CLKOUT_LPC1"},
+	"GPP_A11": {"PME#", "GSPI1_CS1#"},
+	"GPP_A12": {"BM_BUSY#", "ISH_GP6", "SX_EXIT_HOLDOFF#"},
+	"GPP_A13": {"SUSWARN#/SUSPWRDNACK"},
+	"GPP_A14": {"SUS_STAT#", "ESPI_RESET#"},
+	"GPP_A15": {"SUSACK#"},
+
+	"GPP_C0":  {"SMBCLK"},
+	"GPP_C1":  {"SMBDATA"},
+	"GPP_C2":  {"SMBALERT#"},
+	"GPP_C3":  {"SML0CLK"},
+	"GPP_C4":  {"SML0DATA"},
+	"GPP_C5":  {"SML0ALERT#"},
+	"GPP_C6":  {"SML1CLK"},
+	"GPP_C7":  {"SML1DATA"},
+	"GPP_C8":  {"UART0_RXD"},
+	"GPP_C9":  {"UART0_TXD"},
+	"GPP_C10": {"UART0_RTS#"},
+	"GPP_C11": {"UART0_CTS#"},
+	"GPP_C12": {"UART1_RXD", "ISH_UART1_RXD"},
+	"GPP_C13": {"UART1_TXD", "ISH_UART1_TXD"},
+	"GPP_C14": {"UART1_RTS#", "ISH_UART1_RTS#"},
+	"GPP_C15": {"UART1_CTS#", "ISH_UART1_CTS#"},
+	"GPP_C16": {"I2C0_SDA"},
+	"GPP_C17": {"I2C0_SCL"},
+	"GPP_C18": {"I2C1_SDA"},
+	"GPP_C19": {"I2C1_SCL"},
+	"GPP_C20": {"UART2_RXD"},
+	"GPP_C21": {"UART2_TXD"},
+	"GPP_C22": {"UART2_RTS#"},
+	"GPP_C23": {"UART2_CTS#"},
+
+	"GPD0":  {"BATLOW#"},
+	"GPD1":  {"ACPRESENT"},
+	"GPD2":  {"LAN_WAKE#"},
+	"GPD3":  {"PWRBTN#"},
+	"GPD4":  {"SLP_S3#"},
+	"GPD5":  {"SLP_S4#"},
+	"GPD6":  {"SLP_A#"},
+	"GPD7":  {},
+	"GPD8":  {"SUSCLK"},
+	"GPD9":  {"SLP_WLAN#"},
+	"GPD10": {"SLP_S5#"},
+	"GPD11": {"LANPHYPC"},
+}
+
+// NativeFunctionGet - returns the signal name of the native function
+// id   : pad ID string
+// mode : pad mode (PMODE), 1 for NF1
+// return
+//     bool   : true if the pad is in the table
+//     string : signal name, empty if the pad mode has no function
+func (PlatformSpecific) NativeFunctionGet(id string, mode uint8) (bool, string) {
+	return nativeFunctions.Get(id, mode)
+}
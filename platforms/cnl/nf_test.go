@@ -0,0 +1,30 @@
+package cnl_test
+
+import (
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/cnl"
+
+func TestNativeFunctionGet(t *testing.T) {
+	tests := []struct {
+		id    string
+		mode  uint8
+		known bool
+		name  string
+	}{
+		{"GPP_A7", 2, true, "GSPI0_CS1#"},
+		{"GPP_C12", 2, true, "ISH_UART1_RXD"},
+		{"GPD11", 1, true, "LANPHYPC"},
+		{"GPP_C8", 2, true, ""},
+		{"GPD7", 1, true, ""},
+		{"GPP_B1", 1, false, ""},
+	}
+	for _, test := range tests {
+		known, name := cnl.PlatformSpecific{}.NativeFunctionGet(test.id, test.mode)
+		if known != test.known || name != test.name {
+			t.Errorf("%s NF%d: got %v %q, want %v %q", test.id, test.mode, known, name,
+				test.known, test.name)
+		}
+	}
+}
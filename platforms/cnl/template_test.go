@@ -0,0 +1,35 @@
+package cnl_test
+
+import (
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/cnl"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+func TestAcpiPinGet(t *testing.T) {
+	lp := map[string]bool{"GPP_A": true, "GPP_C": true, "GPD": true}
+	h := map[string]bool{"GPP_A": true, "GPP_K": true}
+	tests := []struct {
+		pad   common.PadPosition
+		pin   string
+		valid bool
+	}{
+		{common.PadPosition{Id: "GPP_A5", Group: "GPP_A", Number: 5, Groups: lp}, "5", true},
+		{common.PadPosition{Id: "GPP_C8", Group: "GPP_C", Number: 8, Groups: lp}, "264", true},
+		// the position in the dump is not used
+		{common.PadPosition{Id: "GPP_C8", Group: "GPP_C", Number: 8, Dump: true,
+			Global: 30, Groups: lp}, "264", true},
+		{common.PadPosition{Id: "GPD1", Group: "GPD", Number: 1, Groups: lp}, "", false},
+		// Cannon Point-H
+		{common.PadPosition{Id: "GPP_A5", Group: "GPP_A", Number: 5, Groups: h}, "", false},
+	}
+	for _, test := range tests {
+		controller, pin, valid := cnl.PlatformSpecific{}.AcpiPinGet(test.pad)
+		if valid != test.valid || valid && (pin != test.pin ||
+			controller != common.AcpiGpioController) {
+			t.Errorf("%s: got %s %s %v, want %s %v", test.pad.Id, controller, pin, valid,
+				test.pin, test.valid)
+		}
+	}
+}
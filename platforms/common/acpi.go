@@ -0,0 +1,45 @@
+package common
+
+import "fmt"
+
+// AcpiGpioController - ACPI path of the GPIO controller of the PCH
+const AcpiGpioController = "\\_SB.PCI0.GPIO"
+
+// PadPosition - pad ID and the position of the pad in the input, they are
+// used to get the ACPI pin number of the pad
+// Id        : pad ID
+// Group     : group of the pad, empty if the platform has no groups
+// Number    : number of the pad in the group
+// Dump      : true if the input is the register dump with all pads of the
+//             GPIO controller, Community, Index and Global are valid only
+//             in this case
+// Community : community number from the register dump, -1 if the dump has
+//             no community titles
+// Index     : index of the pad in the community, reserved pads are counted
+// Global    : index of the pad in the register dump, reserved pads are counted
+// Groups    : groups of all pads in the input, they are used to detect the
+//             PCH variant
+type PadPosition struct {
+	Id        string
+	Group     string
+	Number    uint
+	Dump      bool
+	Community int
+	Index     uint
+	Global    uint
+	Groups    map[string]bool
+}
+
+// AcpiGroupPinGet - returns the ACPI pin number for the platforms whose pad
+// groups start at the fixed pin numbers (gpio_base of the pad groups in the
+// Linux pinctrl-intel drivers)
+// bases : ACPI pin number of the first pad in each group
+// pad   : pad position
+// return false if the group has no ACPI pin numbers
+func AcpiGroupPinGet(bases map[string]uint, pad PadPosition) (string, bool) {
+	base, valid := bases[pad.Group]
+	if !valid {
+		return "", false
+	}
+	return fmt.Sprintf("%d", base+pad.Number), true
+}
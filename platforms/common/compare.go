@@ -0,0 +1,94 @@
+package common
+
+import "fmt"
+
+// bitField - bit field of the pad configuration register
+// name   : bit field name from the datasheet
+// key    : short bit field name used in the decoded pad configuration
+// desc   : bit field description
+// dw     : register number
+// getter : Register method that returns the bit field value
+// names  : names of the bit field values used in macros
+type bitField struct {
+	name   string
+	key    string
+	desc   string
+	dw     uint8
+	getter func(*Register) uint8
+	names  map[uint8]string
+}
+
+var bitFields = []bitField{
+	{"PADRSTCFG", "reset", "Pad Reset Config", PAD_CFG_DW0, (*Register).GetResetConfig, nil},
+	{"RXPADSTSEL", "rxpadstsel", "RX Pad State Select", PAD_CFG_DW0, (*Register).GetRXPadStateSelect, nil},
+	{"RXRAW1", "rxraw1", "RX Raw Override to 1", PAD_CFG_DW0, (*Register).GetRXRawOverrideStatus, nil},
+	{"RXEVCFG", "trig", "RX Level/Edge Configuration", PAD_CFG_DW0, (*Register).GetRXLevelEdgeConfiguration, trig},
+	{"RXINV", "rxinv", "RX Invert", PAD_CFG_DW0, (*Register).GetRxInvert, nil},
+	{"RXTXENCFG", "rxtxencfg", "RX/TX Enable Config", PAD_CFG_DW0, (*Register).GetRxTxEnableConfig, nil},
+	{"GPIROUTIOXAPIC", "ioapic", "GPIO Input Route IOxAPIC", PAD_CFG_DW0, (*Register).GetGPIOInputRouteIOxAPIC, nil},
+	{"GPIROUTSCI", "sci", "GPIO Input Route SCI", PAD_CFG_DW0, (*Register).GetGPIOInputRouteSCI, nil},
+	{"GPIROUTSMI", "smi", "GPIO Input Route SMI", PAD_CFG_DW0, (*Register).GetGPIOInputRouteSMI, nil},
+	{"GPIROUTNMI", "nmi", "GPIO Input Route NMI", PAD_CFG_DW0, (*Register).GetGPIOInputRouteNMI, nil},
+	{"PMODE", "mode", "Pad Mode", PAD_CFG_DW0, (*Register).GetPadMode, nil},
+	{"GPIORXTXDIS", "bufdis", "GPIO RX/TX Buffer Disable", PAD_CFG_DW0, (*Register).GetGPIORxTxDisableStatus, buffDisStat},
+	{"GPIORXSTATE", "rxstate", "GPIO RX State", PAD_CFG_DW0, (*Register).GetGPIORXState, nil},
+	{"GPIOTXSTATE", "txstate", "GPIO TX State", PAD_CFG_DW0, (*Register).GetGPIOTXState, nil},
+	{"PADTOL", "tol", "Pad Tolerance", PAD_CFG_DW1, (*Register).GetPadTol, nil},
+	{"IOSSTATE", "iosstate", "IO Standby State", PAD_CFG_DW1, (*Register).GetIOStandbyState, stateMacro},
+	{"TERM", "term", "Termination", PAD_CFG_DW1, (*Register).GetTermination, nil},
+	{"IOSTERM", "iosterm", "IO Standby Termination", PAD_CFG_DW1, (*Register).GetIOStandbyTermination, ioTermMacro},
+	{"INTSEL", "intsel", "Interrupt Select", PAD_CFG_DW1, (*Register).GetInterruptSelect, nil},
+	{"DEBEN", "deben", "Debounce Enable", PAD_CFG_DW2, (*Register).GetDebounceEnable, nil},
+	{"DEBOUNCE", "debounce", "Debounce Duration", PAD_CFG_DW2, (*Register).GetDebounceDuration, debounce},
+}
+
+// FieldDiff - difference between the bit fields of two pad configurations
+// Dw     : register number
+// Name   : bit field name
+// Values : bit field values from the first and second configuration
+type FieldDiff struct {
+	Dw     uint8
+	Name   string
+	Values [2]string
+}
+
+// fieldValueStr - returns the string with the bit field value and its name
+// used in the macros, e.g. 0x1 (DEEP)
+// platform : platform-specific interface
+// id       : pad id string
+// value    : bit field value
+func (field *bitField) fieldValueStr(platform EncoderSpecific, id string, value uint8) string {
+	if name, valid := field.decode(platform, id, value); valid {
+		return fmt.Sprintf("0x%x (%s)", value, name)
+	}
+	return fmt.Sprintf("0x%x", value)
+}
+
+// FieldsCompare - compares the bit fields of two pad configurations
+// platform : platform-specific interface to decode the values
+// id       : pad id string
+// first    : DW0-DW3 register values of the first configuration
+// second   : DW0-DW3 register values of the second configuration
+// ro       : read-only fields masks that should be ignored
+// return
+//     list of bit fields with different values
+func FieldsCompare(platform EncoderSpecific, id string, first [MAX_DW_NUM]uint32,
+	second [MAX_DW_NUM]uint32, ro [MAX_DW_NUM]uint32) []FieldDiff {
+	var diffs []FieldDiff
+	for i := range bitFields {
+		field := &bitFields[i]
+		reg1 := Register{value: first[field.dw] & ^ro[field.dw]}
+		reg2 := Register{value: second[field.dw] & ^ro[field.dw]}
+		val1 := field.getter(&reg1)
+		val2 := field.getter(&reg2)
+		if val1 != val2 {
+			diffs = append(diffs, FieldDiff{
+				Dw:     field.dw,
+				Name:   field.name,
+				Values: [2]string{field.fieldValueStr(platform, id, val1),
+					field.fieldValueStr(platform, id, val2)},
+			})
+		}
+	}
+	return diffs
+}
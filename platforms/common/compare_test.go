@@ -0,0 +1,69 @@
+package common_test
+
+import (
+	"reflect"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/snr"
+
+// snrReadOnlyGet - returns the read-only fields masks of Sunrise Point
+func snrReadOnlyGet() [common.MAX_DW_NUM]uint32 {
+	var ro [common.MAX_DW_NUM]uint32
+	for i := range ro {
+		ro[i] = snr.PlatformSpecific{}.ReadOnlyFieldsGet(uint8(i))
+	}
+	return ro
+}
+
+func TestFieldsCompare(t *testing.T) {
+	tests := []struct {
+		name   string
+		first  [common.MAX_DW_NUM]uint32
+		second [common.MAX_DW_NUM]uint32
+		diffs  []common.FieldDiff
+	}{
+		{"same", [common.MAX_DW_NUM]uint32{0x44000702, 0x3000},
+			[common.MAX_DW_NUM]uint32{0x44000702, 0x3000}, nil},
+		{"read-only INTSEL", [common.MAX_DW_NUM]uint32{0x44000702, 0x3000},
+			[common.MAX_DW_NUM]uint32{0x44000702, 0x3019}, nil},
+		{"mode", [common.MAX_DW_NUM]uint32{0x44000400}, [common.MAX_DW_NUM]uint32{0x44000800},
+			[]common.FieldDiff{
+				{common.PAD_CFG_DW0, "PMODE", [2]string{"0x1 (NF1)", "0x2 (NF2)"}},
+			}},
+		{"GPIO mode", [common.MAX_DW_NUM]uint32{0x44000100}, [common.MAX_DW_NUM]uint32{0x44001d00},
+			[]common.FieldDiff{
+				{common.PAD_CFG_DW0, "PMODE", [2]string{"0x0 (GPIO)", "0x7 (NF7)"}},
+			}},
+		{"reset", [common.MAX_DW_NUM]uint32{0x44000400}, [common.MAX_DW_NUM]uint32{0x84000400},
+			[]common.FieldDiff{
+				{common.PAD_CFG_DW0, "PADRSTCFG", [2]string{"0x1 (DEEP)", "0x2 (PLTRST)"}},
+			}},
+		{"reserved reset", [common.MAX_DW_NUM]uint32{0x44000400}, [common.MAX_DW_NUM]uint32{0xc4000400},
+			[]common.FieldDiff{
+				{common.PAD_CFG_DW0, "PADRSTCFG", [2]string{"0x1 (DEEP)", "0x3"}},
+			}},
+		{"termination", [common.MAX_DW_NUM]uint32{0x44000400, 0x3000},
+			[common.MAX_DW_NUM]uint32{0x44000400, 0x1000},
+			[]common.FieldDiff{
+				{common.PAD_CFG_DW1, "TERM", [2]string{"0xc (20K_PU)", "0x4 (20K_PD)"}},
+			}},
+		{"buffer and trigger", [common.MAX_DW_NUM]uint32{0x84000201},
+			[common.MAX_DW_NUM]uint32{0x80000100},
+			[]common.FieldDiff{
+				{common.PAD_CFG_DW0, "RXEVCFG", [2]string{"0x2 (OFF)", "0x0 (LEVEL)"}},
+				{common.PAD_CFG_DW0, "GPIORXTXDIS", [2]string{"0x2 (RX_DISABLE)",
+					"0x1 (TX_DISABLE)"}},
+				{common.PAD_CFG_DW0, "GPIOTXSTATE", [2]string{"0x1", "0x0"}},
+			}},
+	}
+	ro := snrReadOnlyGet()
+	for _, test := range tests {
+		diffs := common.FieldsCompare(snr.PlatformSpecific{}, "GPP_A1", test.first,
+			test.second, ro)
+		if !reflect.DeepEqual(diffs, test.diffs) {
+			t.Errorf("%s: got %v, want %v", test.name, diffs, test.diffs)
+		}
+	}
+}
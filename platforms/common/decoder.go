@@ -0,0 +1,143 @@
+package common
+
+import "fmt"
+
+// FieldInfo - decoded bit field of the pad configuration register
+// Dw      : register number
+// Name    : bit field name from the datasheet
+// Desc    : bit field description
+// Value   : bit field value
+// Decoded : name of the value used in the macros, empty if the value has no
+//           name
+type FieldInfo struct {
+	Dw      uint8
+	Name    string
+	Desc    string
+	Value   uint8
+	Decoded string
+}
+
+// RstCfgDecode - returns the logical reset source used in the macros for the
+// Pad Reset Source Config (PADRSTCFG) field value
+// platform : platform-specific interface
+// id       : pad id string
+// rstcfg   : PADRSTCFG field value
+// return false if the value is reserved on the platform
+func RstCfgDecode(platform EncoderSpecific, id string, rstcfg uint8) (string, bool) {
+	for rst, name := range resetsrc {
+		if value, valid := platform.RstSrcEncode(id, rst); valid && value == rstcfg {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// decode - returns the name of the bit field value used in the macros
+// platform : platform-specific interface
+// id       : pad id string
+// value    : bit field value
+// return false if the value has no name
+func (field *bitField) decode(platform EncoderSpecific, id string, value uint8) (string, bool) {
+	switch field.key {
+	case "mode":
+		if value == 0 {
+			return "GPIO", true
+		}
+		return fmt.Sprintf("NF%d", value), true
+
+	case "reset":
+		return RstCfgDecode(platform, id, value)
+
+	case "term":
+		return platform.PullDecode(value)
+
+	default:
+		name, valid := field.names[value]
+		return name, valid
+	}
+}
+
+// FieldsDescribe - decodes the bit fields of the pad configuration registers
+// in the order they are described in the datasheet. The bit fields of the
+// registers that the platform does not have are skipped.
+// platform : platform-specific interface
+// id       : pad id string
+// regs     : DW0-DW3 register values as they are set in the hardware
+// ro       : read-only fields masks of the platform
+// return
+//     list of the decoded bit fields
+func FieldsDescribe(platform EncoderSpecific, id string, regs [MAX_DW_NUM]uint32,
+		ro [MAX_DW_NUM]uint32) []FieldInfo {
+	var fields []FieldInfo
+	for i := range bitFields {
+		field := &bitFields[i]
+		if ro[field.dw] == AllFields {
+			// there is no such register on this platform
+			continue
+		}
+		reg := Register{value: regs[field.dw]}
+		value := field.getter(&reg)
+		name, _ := field.decode(platform, id, value)
+		fields = append(fields, FieldInfo{
+			Dw:      field.dw,
+			Name:    field.name,
+			Desc:    field.desc,
+			Value:   value,
+			Decoded: name,
+		})
+	}
+	return fields
+}
+
+// FieldsDecode - decodes the bit fields of the pad configuration registers.
+// The value names are the same as in the macros, e.g. "mode": "NF1",
+// "reset": "DEEP", "term": "UP_20K". Values without a name, e.g. the debounce
+// period 0 or a reserved reset source, are printed as decimal numbers, so each
+// key always has a string value. The bit fields of the registers that the
+// platform does not have are skipped.
+// platform : platform-specific interface
+// id       : pad id string
+// regs     : DW0-DW3 register values as they are set in the hardware
+// ro       : read-only fields masks of the platform
+// return
+//     map of the decoded bit fields with the short field name as a key
+func FieldsDecode(platform EncoderSpecific, id string, regs [MAX_DW_NUM]uint32,
+		ro [MAX_DW_NUM]uint32) map[string]string {
+	decoded := make(map[string]string)
+	for i := range bitFields {
+		field := &bitFields[i]
+		if ro[field.dw] == AllFields {
+			// there is no such register on this platform
+			continue
+		}
+		reg := Register{value: regs[field.dw]}
+		value := field.getter(&reg)
+		if name, valid := field.decode(platform, id, value); valid {
+			decoded[field.key] = name
+		} else {
+			decoded[field.key] = fmt.Sprintf("%d", value)
+		}
+	}
+	return decoded
+}
+
+// FieldsRawGet - returns the raw values of the bit fields of the pad
+// configuration registers. The bit fields of the registers that the platform
+// does not have are skipped.
+// regs : DW0-DW3 register values as they are set in the hardware
+// ro   : read-only fields masks of the platform
+// return
+//     map of the bit field values with the short field name as a key
+func FieldsRawGet(regs [MAX_DW_NUM]uint32, ro [MAX_DW_NUM]uint32) map[string]uint8 {
+	values := make(map[string]uint8)
+	for i := range bitFields {
+		field := &bitFields[i]
+		if ro[field.dw] == AllFields {
+			// there is no such register on this platform
+			continue
+		}
+		reg := Register{value: regs[field.dw]}
+		values[field.key] = field.getter(&reg)
+	}
+	return values
+}
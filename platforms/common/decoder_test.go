@@ -0,0 +1,87 @@
+package common_test
+
+import (
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/apl"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/cnl"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/snr"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/tgl"
+
+// tglPlatform - Tiger Lake with the inherited platforms as in the parser
+var tglPlatform = tgl.PlatformSpecific{
+	InheritanceMacro: cnl.PlatformSpecific{
+		InheritanceMacro: apl.PlatformSpecific{},
+	},
+}
+
+// tglReadOnlyGet - returns the read-only fields masks of Tiger Lake
+func tglReadOnlyGet() [common.MAX_DW_NUM]uint32 {
+	var ro [common.MAX_DW_NUM]uint32
+	for i := range ro {
+		ro[i] = tglPlatform.ReadOnlyFieldsGet(uint8(i))
+	}
+	return ro
+}
+
+func TestFieldsDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform common.EncoderSpecific
+		ro       [common.MAX_DW_NUM]uint32
+		regs     [common.MAX_DW_NUM]uint32
+		fields   map[string]string
+		raw      map[string]uint8
+	}{
+		{"snr NF1", snr.PlatformSpecific{}, snrReadOnlyGet(),
+			[common.MAX_DW_NUM]uint32{0x44000702, 0x00003000},
+			map[string]string{"mode": "NF1", "reset": "DEEP", "term": "20K_PU",
+				"trig": "OFF", "bufdis": "TX_RX_DISABLE", "rxstate": "1"},
+			map[string]uint8{"mode": 1, "reset": 1, "term": 0xc, "trig": 2, "bufdis": 3}},
+		{"snr reserved reset and term", snr.PlatformSpecific{}, snrReadOnlyGet(),
+			[common.MAX_DW_NUM]uint32{0xc0000100, 0x00001c00},
+			map[string]string{"mode": "GPIO", "reset": "3", "term": "7"},
+			map[string]uint8{"mode": 0, "reset": 3, "term": 7}},
+		{"tgl debounce", tglPlatform, tglReadOnlyGet(),
+			[common.MAX_DW_NUM]uint32{0x44000100, 0x00000000, 0x00000009},
+			map[string]string{"mode": "GPIO", "deben": "1", "debounce": "16_RTC"},
+			map[string]uint8{"deben": 1, "debounce": 4}},
+		{"tgl no debounce", tglPlatform, tglReadOnlyGet(),
+			[common.MAX_DW_NUM]uint32{0x44000100, 0x00000000, 0x00000000},
+			map[string]string{"deben": "0", "debounce": "0"},
+			map[string]uint8{"deben": 0, "debounce": 0}},
+	}
+	for _, test := range tests {
+		fields := common.FieldsDecode(test.platform, "GPP_B1", test.regs, test.ro)
+		for key, want := range test.fields {
+			if fields[key] != want {
+				t.Errorf("%s: %s is %q, want %q", test.name, key, fields[key], want)
+			}
+		}
+		raw := common.FieldsRawGet(test.regs, test.ro)
+		for key, want := range test.raw {
+			if value, valid := raw[key]; !valid || value != want {
+				t.Errorf("%s: raw %s is %d, want %d", test.name, key, value, want)
+			}
+		}
+		if len(raw) != len(fields) {
+			t.Errorf("%s: %d raw values for %d fields", test.name, len(raw), len(fields))
+		}
+	}
+}
+
+// TestFieldsDecodeRegisters - the fields of the registers that the platform
+// does not have are skipped
+func TestFieldsDecodeRegisters(t *testing.T) {
+	regs := [common.MAX_DW_NUM]uint32{0x44000100, 0x00000000, 0x00000009}
+	if _, valid := common.FieldsDecode(snr.PlatformSpecific{}, "GPP_B1", regs,
+		snrReadOnlyGet())["debounce"]; valid {
+		t.Errorf("snr: debounce is decoded, but the platform has no DW2")
+	}
+	if _, valid := common.FieldsDecode(tglPlatform, "GPP_B1", regs,
+		tglReadOnlyGet())["debounce"]; !valid {
+		t.Errorf("tgl: debounce is not decoded")
+	}
+}
@@ -0,0 +1,649 @@
+package common
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// PadCfgOwnGpioDriver - PAD_CFG_OWN_GPIO(DRIVER) flag in the DW1 of the coreboot
+// pad_config structure. It is not a register bit field, coreboot uses it to set
+// the host software ownership of the pad.
+const PadCfgOwnGpioDriver uint32 = 0x1 << 4
+
+// EncoderSpecific - platform-specific interface for the macro encoder and
+// the bit fields decoder
+type EncoderSpecific interface {
+	PullEncode(pull string) (uint8, bool)
+	PullDecode(term uint8) (string, bool)
+	RstSrcEncode(id string, rst uint8) (uint8, bool)
+	NativeFunctionGet(id string, mode uint8) (bool, string)
+}
+
+// PadConfig - pad configuration encoded from the coreboot macro
+// Id        : pad id string
+// Reg       : DW0-DW3 configuration registers
+// Ownership : host software ownership
+type PadConfig struct {
+	Id        string
+	Reg       [MAX_DW_NUM]Register
+	Ownership uint8
+}
+
+// returns <Register> data configuration structure
+// number : register number
+func (pad *PadConfig) Register(number uint8) *Register {
+	return &pad.Reg[number]
+}
+
+// macroDef - definition of the pad configuration macro, see coreboot
+// src/soc/intel/common/block/include/intelblocks/gpio_defs.h
+// args     : names of the macro arguments
+// defaults : bit fields that are hidden inside the macro
+type macroDef struct {
+	args     []string
+	defaults map[string]string
+}
+
+var macroDefs = map[string][]macroDef{
+	"PAD_CFG_NF": {{
+		args: []string{"pad", "pull", "rst", "func"},
+	}},
+	"PAD_CFG_NF_1V8": {{
+		args:     []string{"pad", "pull", "rst", "func"},
+		defaults: map[string]string{"tol": "1V8"},
+	}},
+	"PAD_CFG_NF_IOSSTATE": {{
+		args: []string{"pad", "pull", "rst", "func", "iosstate"},
+	}},
+	"PAD_CFG_NF_IOSTANDBY_IGNORE": {{
+		args:     []string{"pad", "pull", "rst", "func"},
+		defaults: map[string]string{"iosstate": "IGNORE"},
+	}},
+	"PAD_CFG_NF_IOSSTATE_IOSTERM": {{
+		args: []string{"pad", "pull", "rst", "func", "iosstate", "iosterm"},
+	}},
+	"PAD_CFG_GPO": {{
+		args:     []string{"pad", "val", "rst"},
+		defaults: map[string]string{"trig": "OFF", "bufdis": "RX_DISABLE"},
+	}},
+	"PAD_CFG_GPO_GPIO_DRIVER": {{
+		args: []string{"pad", "val", "rst", "pull"},
+		defaults: map[string]string{"trig": "OFF", "bufdis": "RX_DISABLE",
+			"own": "DRIVER"},
+	}},
+	"PAD_CFG_TERM_GPO": {{
+		args:     []string{"pad", "val", "pull", "rst"},
+		defaults: map[string]string{"trig": "OFF", "bufdis": "RX_DISABLE"},
+	}},
+	"PAD_CFG_GPO_IOSSTATE_IOSTERM": {{
+		args:     []string{"pad", "val", "rst", "pull", "iosstate", "iosterm"},
+		defaults: map[string]string{"trig": "OFF", "bufdis": "RX_DISABLE"},
+	}},
+	"PAD_CFG_GPI": {{
+		args:     []string{"pad", "pull", "rst"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE"},
+	}},
+	"PAD_CFG_GPI_GPIO_DRIVER": {{
+		args:     []string{"pad", "pull", "rst"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE", "own": "DRIVER"},
+	}},
+	"PAD_CFG_GPI_INT": {{
+		args:     []string{"pad", "pull", "rst", "trig"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE", "own": "DRIVER"},
+	}},
+	"PAD_CFG_GPI_TRIG_OWN": {{
+		args:     []string{"pad", "pull", "rst", "trig", "own"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE"},
+	}},
+	"PAD_CFG_GPI_TRIG_IOSSTATE_OWN": {{
+		args:     []string{"pad", "pull", "rst", "trig", "iosstate", "own"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE"},
+	}},
+	"PAD_CFG_GPI_TRIG_IOS_OWN": {{
+		args:     []string{"pad", "pull", "rst", "trig", "iosstate", "iosterm", "own"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE"},
+	}},
+	"PAD_CFG_GPI_APIC": {
+		// Sunrise: PAD_CFG_GPI_APIC(pad, pull, rst)
+		{
+			args: []string{"pad", "pull", "rst"},
+			defaults: map[string]string{"bufdis": "TX_DISABLE", "route": "IOAPIC",
+				"trig": "LEVEL", "inv": "NONE"},
+		},
+		{
+			args:     []string{"pad", "pull", "rst", "trig", "inv"},
+			defaults: map[string]string{"bufdis": "TX_DISABLE", "route": "IOAPIC"},
+		},
+	},
+	"PAD_CFG_GPI_APIC_INVERT": {{
+		args: []string{"pad", "pull", "rst"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE", "route": "IOAPIC",
+			"trig": "LEVEL", "inv": "INVERT"},
+	}},
+	"PAD_CFG_GPI_APIC_LOW": {{
+		args: []string{"pad", "pull", "rst"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE", "route": "IOAPIC",
+			"trig": "LEVEL", "inv": "INVERT"},
+	}},
+	"PAD_CFG_GPI_APIC_HIGH": {{
+		args: []string{"pad", "pull", "rst"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE", "route": "IOAPIC",
+			"trig": "LEVEL", "inv": "NONE"},
+	}},
+	"PAD_CFG_GPI_APIC_IOS": {{
+		args:     []string{"pad", "pull", "rst", "trig", "inv", "iosstate", "iosterm"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE", "route": "IOAPIC"},
+	}},
+	"PAD_CFG_GPI_SCI": {{
+		args:     []string{"pad", "pull", "rst", "trig", "inv"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE", "route": "SCI"},
+	}},
+	"PAD_CFG_GPI_SCI_IOS": {{
+		args:     []string{"pad", "pull", "rst", "trig", "inv", "iosstate", "iosterm"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE", "route": "SCI"},
+	}},
+	"PAD_CFG_GPI_SCI_LOW": {{
+		args:     []string{"pad", "pull", "rst", "trig"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE", "route": "SCI", "inv": "INVERT"},
+	}},
+	"PAD_CFG_GPI_SCI_HIGH": {{
+		args:     []string{"pad", "pull", "rst", "trig"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE", "route": "SCI", "inv": "NONE"},
+	}},
+	"PAD_CFG_GPI_ACPI_SCI": {{
+		args: []string{"pad", "pull", "rst", "inv"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE", "route": "SCI",
+			"trig": "EDGE_SINGLE"},
+	}},
+	"PAD_CFG_GPI_SMI": {{
+		args:     []string{"pad", "pull", "rst", "trig", "inv"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE", "route": "SMI"},
+	}},
+	"PAD_CFG_GPI_SMI_IOS": {{
+		args:     []string{"pad", "pull", "rst", "trig", "inv", "iosstate", "iosterm"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE", "route": "SMI"},
+	}},
+	"PAD_CFG_GPI_SMI_LOW": {{
+		args:     []string{"pad", "pull", "rst", "trig"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE", "route": "SMI", "inv": "INVERT"},
+	}},
+	"PAD_CFG_GPI_SMI_HIGH": {{
+		args:     []string{"pad", "pull", "rst", "trig"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE", "route": "SMI", "inv": "NONE"},
+	}},
+	"PAD_CFG_GPI_ACPI_SMI": {{
+		args: []string{"pad", "pull", "rst", "inv"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE", "route": "SMI",
+			"trig": "EDGE_SINGLE"},
+	}},
+	"PAD_CFG_GPI_NMI": {{
+		args:     []string{"pad", "pull", "rst", "trig", "inv"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE", "route": "NMI"},
+	}},
+	"PAD_CFG_GPI_DUAL_ROUTE": {{
+		args:     []string{"pad", "pull", "rst", "trig", "inv", "route", "route"},
+		defaults: map[string]string{"bufdis": "TX_DISABLE"},
+	}},
+	"PAD_CFG_GPIO_BIDIRECT": {{
+		args:     []string{"pad", "val", "pull", "rst", "trig", "own"},
+		defaults: map[string]string{"bufdis": "NO_DISABLE"},
+	}},
+	"PAD_CFG_GPIO_BIDIRECT_IOS": {{
+		args:     []string{"pad", "val", "pull", "rst", "trig", "iosstate", "iosterm", "own"},
+		defaults: map[string]string{"bufdis": "NO_DISABLE"},
+	}},
+	"PAD_CFG_GPIO_HI_Z": {{
+		args:     []string{"pad", "pull", "rst", "iosstate", "iosterm"},
+		defaults: map[string]string{"bufdis": "TX_RX_DISABLE"},
+	}},
+	"PAD_CFG_GPIO_DRIVER_HI_Z": {{
+		args:     []string{"pad", "pull", "rst", "iosstate", "iosterm"},
+		defaults: map[string]string{"bufdis": "TX_RX_DISABLE", "own": "DRIVER"},
+	}},
+	"PAD_NC": {{
+		args: []string{"pad", "pull"},
+		defaults: map[string]string{"rst": "DEEP", "trig": "OFF",
+			"bufdis": "TX_RX_DISABLE", "iosstate": "TxDRxE"},
+	}},
+}
+
+// bitfieldArgs - bit field macros used in _PAD_CFG_STRUCT() and the names of the
+// corresponding arguments
+var bitfieldArgs = map[string]string{
+	"PAD_FUNC":         "func",
+	"PAD_RESET":        "rst",
+	"PAD_TRIG":         "trig",
+	"PAD_IRQ_ROUTE":    "route",
+	"PAD_RX_POL":       "inv",
+	"PAD_BUF":          "bufdis",
+	"PAD_PULL":         "pull",
+	"PAD_IOSSTATE":     "iosstate",
+	"PAD_IOSTERM":      "iosterm",
+	"PAD_CFG_OWN_GPIO": "own",
+}
+
+var routeMasks = map[string]uint32{
+	"IOAPIC": InputRouteIOxApicMask,
+	"SCI":    InputRouteSCIMask,
+	"SMI":    InputRouteSMIMask,
+	"NMI":    InputRouteNMIMask,
+}
+
+// keyGet - returns the key of the table element with the specified name
+// table : table with names of the bit field values
+// name  : name of the bit field value
+func keyGet(table map[uint8]string, name string) (uint8, bool) {
+	for key, value := range table {
+		if value == name {
+			return key, true
+		}
+	}
+	return 0, false
+}
+
+// argEncode - sets the bit field that corresponds to the macro argument
+// platform : platform-specific encoder interface
+// arg      : argument name
+// value    : argument value from the macro
+func (pad *PadConfig) argEncode(platform EncoderSpecific, arg string, value string) error {
+	dw0 := pad.Register(PAD_CFG_DW0)
+	dw1 := pad.Register(PAD_CFG_DW1)
+	var tables = map[string]map[uint8]string{
+		"rst":      resetsrc,
+		"trig":     trig,
+		"bufdis":   buffDisStat,
+		"iosstate": stateMacro,
+		"iosterm":  ioTermMacro,
+	}
+	var fields = map[string]*struct {
+		reg   *Register
+		mask  uint32
+		shift uint8
+	}{
+		"rst":      {dw0, PadRstCfgMask, PadRstCfgShift},
+		"trig":     {dw0, RxLevelEdgeConfigurationMask, RxLevelEdgeConfigurationShift},
+		"bufdis":   {dw0, RxTxBufDisableMask, RxTxBufDisableShift},
+		"iosstate": {dw1, IOStandbyStateMask, IOStandbyStateShift},
+		"iosterm":  {dw1, IOStandbyTerminationMask, IOStandbyTerminationShift},
+	}
+
+	if table, valid := tables[arg]; valid {
+		key, valid := keyGet(table, value)
+		if !valid {
+			return fmt.Errorf("%s: invalid %s value %s", pad.Id, arg, value)
+		}
+		field := fields[arg]
+		field.reg.setFieldVal(field.mask, field.shift, key)
+		return nil
+	}
+
+	switch arg {
+	case "pad":
+		pad.Id = value
+
+	case "pull":
+		term, valid := platform.PullEncode(value)
+		if !valid {
+			return fmt.Errorf("%s: invalid pull value %s", pad.Id, value)
+		}
+		dw1.setFieldVal(TermMask, TermShift, term)
+
+	case "func":
+		if value == "GPIO" {
+			dw0.setFieldVal(PadModeMask, PadModeShift, 0)
+			return nil
+		}
+		nfnum, err := strconv.Atoi(strings.TrimPrefix(value, "NF"))
+		if !strings.HasPrefix(value, "NF") || err != nil || nfnum < 1 || nfnum > 7 {
+			return fmt.Errorf("%s: invalid pad mode %s", pad.Id, value)
+		}
+		dw0.setFieldVal(PadModeMask, PadModeShift, uint8(nfnum))
+
+	case "val":
+		val, err := strconv.ParseUint(value, 0, 32)
+		if err != nil {
+			return fmt.Errorf("%s: invalid GPO value %s", pad.Id, value)
+		}
+		if val != 0 {
+			val = 1
+		}
+		dw0.setFieldVal(TxStateMask, 0, uint8(val))
+
+	case "inv":
+		switch value {
+		case "NONE":
+			dw0.setFieldVal(RxInvertMask, RxInvertShift, 0)
+		case "INVERT", "YES":
+			dw0.setFieldVal(RxInvertMask, RxInvertShift, 1)
+		default:
+			return fmt.Errorf("%s: invalid RX polarity %s", pad.Id, value)
+		}
+
+	case "route":
+		mask, valid := routeMasks[value]
+		if !valid {
+			return fmt.Errorf("%s: invalid IRQ route %s", pad.Id, value)
+		}
+		dw0.ValueSet(dw0.ValueGet() | mask)
+
+	case "own":
+		switch value {
+		case "ACPI":
+			pad.Ownership = PAD_OWN_ACPI
+		case "DRIVER":
+			pad.Ownership = PAD_OWN_DRIVER
+		default:
+			return fmt.Errorf("%s: invalid pad ownership %s", pad.Id, value)
+		}
+
+	case "tol":
+		dw1.setFieldVal(PadTolMask, PadTolShift, 1)
+
+	default:
+		return fmt.Errorf("%s: unknown argument %s", pad.Id, arg)
+	}
+	return nil
+}
+
+// fieldsEncode - sets the register bit fields from the bit field macros
+// in _PAD_CFG_STRUCT(), e.g. PAD_FUNC(NF1) | PAD_RESET(DEEP) | (1 << 1)
+// platform : platform-specific encoder interface
+// number   : register number
+// fields   : string with bit field macros
+func (pad *PadConfig) fieldsEncode(platform EncoderSpecific, number uint8,
+	fields string) error {
+	reg := pad.Register(number)
+	for _, field := range strings.Split(fields, "|") {
+		field = strings.TrimSpace(field)
+		if field == "PAD_CFG1_TOL_1V8" {
+			if err := pad.argEncode(platform, "tol", ""); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if field == "PAD_CFG2_DEBEN" {
+			reg.setFieldVal(DebounceEnableMask, 0, 1)
+			continue
+		}
+
+		if strings.HasPrefix(field, "PAD_CFG2_DEBOUNCE_") {
+			duration, valid := keyGet(debounce, strings.TrimPrefix(field, "PAD_CFG2_DEBOUNCE_"))
+			if !valid {
+				return fmt.Errorf("%s: invalid debounce duration %s", pad.Id, field)
+			}
+			reg.setFieldVal(DebounceDurationMask, DebounceDurationShift, duration)
+			continue
+		}
+
+		if open := strings.Index(field, "("); open > 0 && strings.HasSuffix(field, ")") {
+			arg, valid := bitfieldArgs[strings.TrimSpace(field[:open])]
+			if !valid {
+				return fmt.Errorf("%s: unknown bit field macro %s", pad.Id, field)
+			}
+			value := strings.TrimSpace(field[open+1 : len(field)-1])
+			if err := pad.argEncode(platform, arg, value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// raw value, e.g. 0x44000702, 1 or (1 << 29)
+		var bit uint
+		if _, err := fmt.Sscanf(field, "(1 << %d)", &bit); err == nil && bit < 32 {
+			reg.ValueSet(reg.ValueGet() | (1 << bit))
+			continue
+		}
+		val, err := strconv.ParseUint(field, 0, 32)
+		if err != nil {
+			return fmt.Errorf("%s: invalid bit field %s", pad.Id, field)
+		}
+		reg.ValueSet(reg.ValueGet() | uint32(val))
+	}
+	return nil
+}
+
+// commentsRemove - removes C comments from the line
+func commentsRemove(line string) string {
+	for {
+		start := strings.Index(line, "/*")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(line[start:], "*/")
+		if end < 0 {
+			line = line[:start]
+			break
+		}
+		line = line[:start] + " " + line[start+end+2:]
+	}
+	if start := strings.Index(line, "//"); start >= 0 {
+		line = line[:start]
+	}
+	return line
+}
+
+// macroSplit - splits the macro into name and arguments
+// line : string with the macro
+// return
+//     macro name
+//     arguments
+//     error
+func macroSplit(line string) (string, []string, error) {
+	line = strings.TrimSpace(commentsRemove(line))
+	open := strings.Index(line, "(")
+	if open <= 0 {
+		return "", nil, fmt.Errorf("pad configuration macro not found")
+	}
+	name := strings.TrimSpace(line[:open])
+
+	var args []string
+	depth := 0
+	start := open + 1
+	for i := open; i < len(line); i++ {
+		switch line[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return name, append(args, strings.TrimSpace(line[start:i])), nil
+			}
+		case ',':
+			if depth == 1 {
+				args = append(args, strings.TrimSpace(line[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return "", nil, fmt.Errorf("%s: unbalanced brackets", name)
+}
+
+// IsMacroLine - returns true if the line contains the pad configuration macro.
+// The other lines of gpio.h with the pad IDs, such as #define or the function
+// calls, are not the pad configuration.
+// line : string from gpio.h
+func IsMacroLine(line string) bool {
+	line = strings.TrimSpace(commentsRemove(line))
+	return strings.HasPrefix(line, "PAD_") || strings.HasPrefix(line, "_PAD_CFG_STRUCT")
+}
+
+// MacroEncode - converts the coreboot pad configuration macro into the DW0-DW2
+// register values that the pad ends up with after coreboot configures it
+// platform : platform-specific encoder interface
+// line     : string with macro, e.g. PAD_CFG_NF(GPP_A1, 20K_PU, DEEP, NF1),
+// return
+//     pad configuration
+//     error
+func MacroEncode(platform EncoderSpecific, line string) (*PadConfig, error) {
+	name, args, err := macroSplit(line)
+	if err != nil {
+		return nil, err
+	}
+
+	pad := &PadConfig{}
+	if name == "_PAD_CFG_STRUCT" || name == "_PAD_CFG_STRUCT_3" {
+		// _PAD_CFG_STRUCT(GPP_A0, 0x44000702, 0x00000000)
+		// _PAD_CFG_STRUCT(GPP_A0, PAD_FUNC(NF1) | PAD_RESET(DEEP), PAD_PULL(NONE))
+		// _PAD_CFG_STRUCT_3(GPP_A0, 0x44000702, 0x00000000, 0x00000007)
+		dwnum := 2
+		if name == "_PAD_CFG_STRUCT_3" {
+			dwnum = 3
+		}
+		if len(args) != dwnum+1 {
+			return nil, fmt.Errorf("%s: wrong number of arguments", name)
+		}
+		pad.Id = args[0]
+		for i := 0; i < dwnum; i++ {
+			if err := pad.fieldsEncode(platform, uint8(i), args[i+1]); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		defs, valid := macroDefs[name]
+		if !valid {
+			return nil, fmt.Errorf("unknown macro %s", name)
+		}
+		var def *macroDef
+		for i := range defs {
+			if len(defs[i].args) == len(args) {
+				def = &defs[i]
+			}
+		}
+		if def == nil {
+			return nil, fmt.Errorf("%s: wrong number of arguments", name)
+		}
+		pad.Id = args[0]
+		for arg, value := range def.defaults {
+			if err := pad.argEncode(platform, arg, value); err != nil {
+				return nil, err
+			}
+		}
+		for i, arg := range def.args {
+			if err := pad.argEncode(platform, arg, args[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	dw0 := pad.Register(PAD_CFG_DW0)
+	dw1 := pad.Register(PAD_CFG_DW1)
+	if dw1.ValueGet()&PadCfgOwnGpioDriver != 0 {
+		pad.Ownership = PAD_OWN_DRIVER
+	}
+	// Interrupt Select (INTSEL) is read-only
+	dw1.ValueSet(dw1.ValueGet() & ^InterruptSelectMask)
+
+	// The macro contains a logical reset source that coreboot converts to the
+	// platform-specific value of the PADRSTCFG field
+	rst, valid := platform.RstSrcEncode(pad.Id, dw0.GetResetConfig())
+	if !valid {
+		return nil, fmt.Errorf("%s: invalid pad reset config %s", pad.Id,
+			resetsrc[dw0.GetResetConfig()])
+	}
+	dw0.setFieldVal(PadRstCfgMask, PadRstCfgShift, rst)
+	return pad, nil
+}
+
+// maskGet - returns the mask and the shift of the bit field
+func (field *bitField) maskGet() (uint32, uint8) {
+	reg := Register{}
+	field.getter(&reg)
+	return reg.mask, uint8(bits.TrailingZeros32(reg.mask))
+}
+
+// encode - returns the bit field value that corresponds to the value name
+// used in the decoded pad configuration or to the number
+// platform : platform-specific encoder interface
+// id       : pad id string
+// value    : value name or number
+func (field *bitField) encode(platform EncoderSpecific, id string, value string) (uint8, error) {
+	switch field.key {
+	case "mode":
+		pad := PadConfig{Id: id}
+		if err := pad.argEncode(platform, "func", value); err == nil {
+			return pad.Register(PAD_CFG_DW0).GetPadMode(), nil
+		}
+
+	case "reset":
+		if rst, valid := keyGet(resetsrc, value); valid {
+			rstcfg, valid := platform.RstSrcEncode(id, rst)
+			if !valid {
+				return 0, fmt.Errorf("%s: invalid pad reset config %s", id, value)
+			}
+			return rstcfg, nil
+		}
+
+	case "term":
+		if term, valid := platform.PullEncode(value); valid {
+			return term, nil
+		}
+
+	default:
+		if key, valid := keyGet(field.names, value); valid {
+			return key, nil
+		}
+	}
+
+	mask, shift := field.maskGet()
+	num, err := strconv.ParseUint(value, 0, 8)
+	if err != nil || uint32(num) > mask>>shift {
+		return 0, fmt.Errorf("%s: invalid %s value %s", id, field.key, value)
+	}
+	return uint8(num), nil
+}
+
+// FieldsEncode - converts the named bit fields into the DW0-DW3 register
+// values. The field names and the value names are the same as in the decoded
+// pad configuration, e.g. mode=NF2 reset=PLTRST term=20K_PU iosstate=TxDRxE.
+// A number can be used instead of the value name. The bit fields that are not
+// set are 0, own=DRIVER sets the host software ownership. The read-only bit
+// fields, e.g. INTSEL, can not be set.
+// platform : platform-specific encoder interface
+// id       : pad id string
+// fields   : list of the bit fields in the <name>=<value> format
+// ro       : read-only fields masks of the platform
+// return
+//     pad configuration
+//     error
+func FieldsEncode(platform EncoderSpecific, id string, fields []string,
+		ro [MAX_DW_NUM]uint32) (*PadConfig, error) {
+	pad := &PadConfig{Id: id}
+	for _, field := range fields {
+		eq := strings.Index(field, "=")
+		if eq <= 0 {
+			return nil, fmt.Errorf("%s: %s is not in the <name>=<value> format", id, field)
+		}
+		key, value := strings.TrimSpace(field[:eq]), strings.TrimSpace(field[eq+1:])
+		if key == "own" {
+			if err := pad.argEncode(platform, key, value); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		var info *bitField
+		for i := range bitFields {
+			if bitFields[i].key == key {
+				info = &bitFields[i]
+			}
+		}
+		if info == nil {
+			return nil, fmt.Errorf("%s: unknown bit field %s", id, key)
+		}
+		mask, shift := info.maskGet()
+		if ro[info.dw] == AllFields {
+			return nil, fmt.Errorf("%s: there is no DW%d register on this platform", id, info.dw)
+		}
+		if mask&ro[info.dw] != 0 {
+			return nil, fmt.Errorf("%s: %s is read-only on this platform", id, key)
+		}
+		num, err := info.encode(platform, id, value)
+		if err != nil {
+			return nil, err
+		}
+		pad.Register(info.dw).setFieldVal(mask, shift, num)
+	}
+	return pad, nil
+}
@@ -0,0 +1,171 @@
+package common_test
+
+import (
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/snr"
+
+// encodeTests - macros and the Sunrise Point register values that coreboot
+// sets for them
+var encodeTests = []struct {
+	macro     string
+	dw0       uint32
+	dw1       uint32
+	ownership uint8
+}{
+	{"PAD_CFG_NF(GPP_A1, 20K_PU, DEEP, NF1),", 0x40000400, 0x00003000, common.PAD_OWN_ACPI},
+	{"PAD_CFG_NF_1V8(GPP_C1, NONE, DEEP, NF2),", 0x40000800, 0x02000000, common.PAD_OWN_ACPI},
+	{"PAD_CFG_GPO(GPP_A2, 1, PLTRST),", 0x84000201, 0x00000000, common.PAD_OWN_ACPI},
+	{"PAD_CFG_GPI_SCI(GPP_A3, NONE, PLTRST, LEVEL, INVERT),", 0x80880100, 0x00000000,
+		common.PAD_OWN_ACPI},
+	{"PAD_CFG_GPI_APIC(GPP_B3, NONE, PLTRST, EDGE_SINGLE, INVERT),", 0x82900100,
+		0x00000000, common.PAD_OWN_ACPI},
+	{"PAD_CFG_GPI_TRIG_OWN(GPP_B4, 20K_PD, PLTRST, OFF, DRIVER),", 0x84000100,
+		0x00001000, common.PAD_OWN_DRIVER},
+	{"PAD_NC(GPP_A4, NONE),", 0x44000300, 0x00024000, common.PAD_OWN_ACPI},
+	{"_PAD_CFG_STRUCT(GPP_C0, 0x44000702, 0x00003000),", 0x44000702, 0x00003000,
+		common.PAD_OWN_ACPI},
+	{"_PAD_CFG_STRUCT(GPP_C0, PAD_FUNC(NF1) | PAD_RESET(DEEP), PAD_PULL(20K_PU)),",
+		0x40000400, 0x00003000, common.PAD_OWN_ACPI},
+}
+
+func TestMacroEncode(t *testing.T) {
+	for _, test := range encodeTests {
+		cfg, err := common.MacroEncode(snr.PlatformSpecific{}, test.macro)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.macro, err)
+			continue
+		}
+		dw0 := cfg.Register(common.PAD_CFG_DW0).ValueGet()
+		dw1 := cfg.Register(common.PAD_CFG_DW1).ValueGet()
+		if dw0 != test.dw0 || dw1 != test.dw1 || cfg.Ownership != test.ownership {
+			t.Errorf("%s: got 0x%08x 0x%08x %d, want 0x%08x 0x%08x %d", test.macro,
+				dw0, dw1, cfg.Ownership, test.dw0, test.dw1, test.ownership)
+		}
+	}
+}
+
+func TestMacroEncodeErrors(t *testing.T) {
+	tests := []struct {
+		macro string
+		err   string
+	}{
+		{"PAD_CFG_FOO(GPP_A1),", "unknown macro PAD_CFG_FOO"},
+		{"PAD_CFG_NF(GPP_A1, 20K_PU, DEEP),", "PAD_CFG_NF: wrong number of arguments"},
+		{"PAD_CFG_NF(GPP_A1, 20K_PU, DEEP, NF1", "PAD_CFG_NF: unbalanced brackets"},
+		{"PAD_CFG_NF(GPP_A1, 30K_PU, DEEP, NF1),", "GPP_A1: invalid pull value 30K_PU"},
+		{"_PAD_CFG_STRUCT(GPP_C0, 0x44000702),", "_PAD_CFG_STRUCT: wrong number of arguments"},
+	}
+	for _, test := range tests {
+		_, err := common.MacroEncode(snr.PlatformSpecific{}, test.macro)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("%s: got error %v, want %s", test.macro, err, test.err)
+		}
+	}
+}
+
+// macroGen - generates the Sunrise Point macro for the encoded registers
+func macroGen(cfg *common.PadConfig, opts *config.Options) string {
+	return snr.PlatformSpecific{}.GenMacro(cfg.Id,
+		cfg.Register(common.PAD_CFG_DW0).ValueGet(),
+		cfg.Register(common.PAD_CFG_DW1).ValueGet(), 0, cfg.Ownership,
+		common.PAD_LOCK_DEFAULT, opts)
+}
+
+// TestMacroRoundTrip - the macro generated from the encoded registers must
+// be encoded into the same registers. The macros that have no shorter form
+// must be generated unchanged.
+func TestMacroRoundTrip(t *testing.T) {
+	opts := &config.Options{}
+	opts.PlatformSet("snr")
+	opts.FldStyleSet("none")
+	platform := snr.PlatformSpecific{}
+	for _, test := range encodeTests {
+		cfg, err := common.MacroEncode(platform, test.macro)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.macro, err)
+			continue
+		}
+		macro := macroGen(cfg, opts)
+		again, err := common.MacroEncode(platform, macro)
+		if err != nil {
+			t.Errorf("%s: generated %s can not be encoded: %v", test.macro, macro, err)
+			continue
+		}
+		if again.Reg != cfg.Reg || again.Ownership != cfg.Ownership {
+			t.Errorf("%s: generated %s has other register values", test.macro, macro)
+		}
+	}
+
+	for _, macro := range []string{
+		"PAD_CFG_NF(GPP_A1, 20K_PU, DEEP, NF1),",
+		"PAD_CFG_GPO(GPP_A2, 1, PLTRST),",
+		"PAD_CFG_GPI_SCI(GPP_A3, NONE, PLTRST, LEVEL, INVERT),",
+		"PAD_CFG_GPI_TRIG_OWN(GPP_B4, 20K_PD, PLTRST, OFF, DRIVER),",
+		"PAD_NC(GPP_A4, NONE),",
+	} {
+		cfg, err := common.MacroEncode(platform, macro)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", macro, err)
+			continue
+		}
+		generated := macroGen(cfg, opts)
+		if generated != macro {
+			t.Errorf("%s: generated %s", macro, generated)
+		}
+	}
+}
+
+func TestFieldsEncode(t *testing.T) {
+	tests := []struct {
+		fields    []string
+		dw0       uint32
+		dw1       uint32
+		ownership uint8
+	}{
+		{[]string{"mode=NF1", "reset=DEEP", "term=20K_PU"}, 0x40000400, 0x00003000,
+			common.PAD_OWN_ACPI},
+		{[]string{"mode=GPIO", "reset=PLTRST", "trig=EDGE_SINGLE", "bufdis=TX_DISABLE",
+			"own=DRIVER"}, 0x82000100, 0x00000000, common.PAD_OWN_DRIVER},
+		{[]string{"mode=3", "rxinv=1", "term=0x4"}, 0x00800c00, 0x00001000, common.PAD_OWN_ACPI},
+	}
+	for _, test := range tests {
+		cfg, err := common.FieldsEncode(snr.PlatformSpecific{}, "GPP_A1", test.fields,
+			snrReadOnlyGet())
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", test.fields, err)
+			continue
+		}
+		dw0 := cfg.Register(common.PAD_CFG_DW0).ValueGet()
+		dw1 := cfg.Register(common.PAD_CFG_DW1).ValueGet()
+		if dw0 != test.dw0 || dw1 != test.dw1 || cfg.Ownership != test.ownership {
+			t.Errorf("%v: got 0x%08x 0x%08x %d, want 0x%08x 0x%08x %d", test.fields, dw0,
+				dw1, cfg.Ownership, test.dw0, test.dw1, test.ownership)
+		}
+	}
+}
+
+func TestFieldsEncodeErrors(t *testing.T) {
+	tests := []struct {
+		fields []string
+		err    string
+	}{
+		{[]string{"mode"}, "GPP_A1: mode is not in the <name>=<value> format"},
+		{[]string{"pmode=NF1"}, "GPP_A1: unknown bit field pmode"},
+		{[]string{"mode=NF9"}, "GPP_A1: invalid mode value NF9"},
+		{[]string{"bufdis=4"}, "GPP_A1: invalid bufdis value 4"},
+		{[]string{"mode=GPIO", "iosstate=TxDRxE"}, "GPP_A1: iosstate is read-only on this platform"},
+		{[]string{"intsel=14"}, "GPP_A1: intsel is read-only on this platform"},
+		{[]string{"deben=1"}, "GPP_A1: there is no DW2 register on this platform"},
+	}
+	for _, test := range tests {
+		_, err := common.FieldsEncode(snr.PlatformSpecific{}, "GPP_A1", test.fields,
+			snrReadOnlyGet())
+		if err == nil || err.Error() != test.err {
+			t.Errorf("%v: got error %v, want %s", test.fields, err, test.err)
+		}
+	}
+}
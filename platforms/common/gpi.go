@@ -0,0 +1,71 @@
+package common
+
+import "fmt"
+
+// Names of the GPI group registers with one bit per pad
+const (
+	GpiIs     = "GPI_IS"
+	GpiIe     = "GPI_IE"
+	GpiGpeSts = "GPI_GPE_STS"
+	GpiGpeEn  = "GPI_GPE_EN"
+	GpiSmiSts = "GPI_SMI_STS"
+	GpiSmiEn  = "GPI_SMI_EN"
+	GpiNmiSts = "GPI_NMI_STS"
+	GpiNmiEn  = "GPI_NMI_EN"
+)
+
+// GpiRegs - GPI group registers in the order they are printed by inteltool
+var GpiRegs = []string{
+	GpiIs, GpiIe, GpiGpeSts, GpiGpeEn, GpiSmiSts, GpiSmiEn, GpiNmiSts, GpiNmiEn,
+}
+
+// GpiEnableRegs - GPI enable registers. Unlike the status registers, they are
+// set by the firmware or by the OS and can be compared between two dumps.
+var GpiEnableRegs = []string{GpiIe, GpiGpeEn, GpiSmiEn, GpiNmiEn}
+
+// gpiRoutes - interrupt routes from DW0 and the corresponding enable registers
+var gpiRoutes = []struct {
+	route  string
+	getter func(*Register) uint8
+	enable string
+}{
+	{"SCI", (*Register).GetGPIOInputRouteSCI, GpiGpeEn},
+	{"SMI", (*Register).GetGPIOInputRouteSMI, GpiSmiEn},
+	{"NMI", (*Register).GetGPIOInputRouteNMI, GpiNmiEn},
+}
+
+// GpiRouteCheck - checks that the interrupt routes in DW0 agree with the GPI
+// enable bits of the pad group. The registers that are missing in the dump
+// are not checked, as well as the pads in native function mode, since the
+// routes in DW0 only apply to GPIO.
+// dw0       : DW0 register value
+// gpi       : GPI group registers bits of the pad
+// ownership : host software ownership
+// return
+//     list of the contradictions
+func GpiRouteCheck(dw0 uint32, gpi map[string]uint8, ownership uint8) []string {
+	var problems []string
+	reg := Register{value: dw0}
+	if reg.GetPadMode() != 0 {
+		return nil
+	}
+	for _, route := range gpiRoutes {
+		enable, valid := gpi[route.enable]
+		if !valid {
+			continue
+		}
+		if routed := route.getter(&reg) != 0; routed && enable == 0 {
+			problems = append(problems, fmt.Sprintf("%s-routed, but %s is clear",
+				route.route, route.enable))
+		} else if !routed && enable != 0 {
+			problems = append(problems, fmt.Sprintf("%s is set, but the pad is not %s-routed",
+				route.enable, route.route))
+		}
+	}
+	if ie, valid := gpi[GpiIe]; valid && ie != 0 && ownership == PAD_OWN_ACPI {
+		// GPI_IS is only updated in the GPIO driver mode
+		problems = append(problems, fmt.Sprintf("%s is set, but the pad is owned by ACPI",
+			GpiIe))
+	}
+	return problems
+}
@@ -0,0 +1,39 @@
+package common_test
+
+import (
+	"reflect"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+func TestGpiRouteCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		dw0       uint32
+		gpi       map[string]uint8
+		ownership uint8
+		problems  []string
+	}{
+		{"no registers", 0x80880100, nil, common.PAD_OWN_ACPI, nil},
+		{"SCI with GPE_EN", 0x80880100, map[string]uint8{common.GpiGpeEn: 1},
+			common.PAD_OWN_ACPI, nil},
+		{"SCI without GPE_EN", 0x80880100, map[string]uint8{common.GpiGpeEn: 0,
+			common.GpiSmiEn: 0}, common.PAD_OWN_ACPI,
+			[]string{"SCI-routed, but GPI_GPE_EN is clear"}},
+		{"SMI_EN without route", 0x80000100, map[string]uint8{common.GpiSmiEn: 1},
+			common.PAD_OWN_ACPI, []string{"GPI_SMI_EN is set, but the pad is not SMI-routed"}},
+		{"native function", 0x80080400, map[string]uint8{common.GpiGpeEn: 0},
+			common.PAD_OWN_ACPI, nil},
+		{"GPI_IE, ACPI", 0x80000100, map[string]uint8{common.GpiIe: 1},
+			common.PAD_OWN_ACPI, []string{"GPI_IE is set, but the pad is owned by ACPI"}},
+		{"GPI_IE, driver", 0x80000100, map[string]uint8{common.GpiIe: 1},
+			common.PAD_OWN_DRIVER, nil},
+	}
+	for _, test := range tests {
+		problems := common.GpiRouteCheck(test.dw0, test.gpi, test.ownership)
+		if !reflect.DeepEqual(problems, test.problems) {
+			t.Errorf("%s: got %q, want %q", test.name, problems, test.problems)
+		}
+	}
+}
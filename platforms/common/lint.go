@@ -0,0 +1,175 @@
+package common
+
+// Severity levels of the lint rules
+const (
+	LintInfo    uint8 = 0
+	LintWarning uint8 = 1
+	LintError   uint8 = 2
+)
+
+var lintSeverity = map[uint8]string{
+	LintInfo:    "info",
+	LintWarning: "warning",
+	LintError:   "error",
+}
+
+// LintSeverityGet - returns the name of the severity level
+func LintSeverityGet(severity uint8) string {
+	return lintSeverity[severity]
+}
+
+// lintPad - pad configuration checked by the lint rules
+// platform  : platform-specific interface
+// id        : pad id string
+// dw0       : DW0 register
+// dw1       : DW1 register
+// ro        : read-only fields masks of the platform
+// ownership : host software ownership
+// gpi       : GPI group registers bits
+type lintPad struct {
+	platform  EncoderSpecific
+	id        string
+	dw0       *Register
+	dw1       *Register
+	ro        [MAX_DW_NUM]uint32
+	ownership uint8
+	gpi       map[string]uint8
+}
+
+// isGpio - returns true if the pad is in GPIO mode
+func (pad *lintPad) isGpio() bool {
+	return pad.dw0.GetPadMode() == 0
+}
+
+// isRxDisabled - returns true if the GPIO RX buffer is disabled
+func (pad *lintPad) isRxDisabled() bool {
+	return pad.dw0.GetGPIORxTxDisableStatus()&0x2 != 0
+}
+
+// isTxDisabled - returns true if the GPIO TX buffer is disabled
+func (pad *lintPad) isTxDisabled() bool {
+	return pad.dw0.GetGPIORxTxDisableStatus()&0x1 != 0
+}
+
+// routesGet - returns the number of the interrupt routes of the pad
+func (pad *lintPad) routesGet() int {
+	return int(pad.dw0.GetGPIOInputRouteIOxAPIC() + pad.dw0.GetGPIOInputRouteSCI() +
+		pad.dw0.GetGPIOInputRouteSMI() + pad.dw0.GetGPIOInputRouteNMI())
+}
+
+// LintRule - semantic check of the pad configuration
+// Id       : rule ID, used to suppress the rule
+// Severity : LintInfo, LintWarning or LintError
+// Desc     : description of the problem
+// check    : returns true if the pad configuration has the problem
+type LintRule struct {
+	Id       string
+	Severity uint8
+	Desc     string
+	check    func(pad *lintPad) bool
+}
+
+// LintRules - catalogue of the lint rules
+var LintRules = []LintRule{
+	{"gpo-rx-route", LintWarning, "GPIO output with RX buffer disabled has an interrupt route",
+		func(pad *lintPad) bool {
+			return pad.isGpio() && pad.isRxDisabled() && pad.routesGet() != 0
+		}},
+	{"gpo-trig", LintInfo, "GPIO output has RX level/edge configuration other than OFF, " +
+		"PAD_CFG_GPO sets TRIG(OFF)",
+		func(pad *lintPad) bool {
+			return pad.isGpio() && pad.isRxDisabled() && !pad.isTxDisabled() &&
+				pad.dw0.GetRXLevelEdgeConfiguration() != TRIG_OFF
+		}},
+	{"gpi-multi-route", LintWarning, "GPIO input has more than two interrupt routes, " +
+		"there is no macro for such configuration",
+		func(pad *lintPad) bool {
+			return pad.isGpio() && pad.routesGet() > 2
+		}},
+	{"edge-no-route", LintWarning, "GPIO input is edge triggered, but has no interrupt route",
+		func(pad *lintPad) bool {
+			trig := pad.dw0.GetRXLevelEdgeConfiguration()
+			return pad.isGpio() && !pad.isRxDisabled() && pad.routesGet() == 0 &&
+				pad.ownership == PAD_OWN_ACPI && (trig == TRIG_EDGE_SINGLE ||
+				trig == TRIG_EDGE_BOTH)
+		}},
+	{"nmi-driver-owned", LintError, "NMI route is enabled on the pad owned by the GPIO driver",
+		func(pad *lintPad) bool {
+			return pad.dw0.GetGPIOInputRouteNMI() != 0 && pad.ownership == PAD_OWN_DRIVER
+		}},
+	{"gpi-route-mismatch", LintWarning, "interrupt route in DW0 contradicts the GPI " +
+		"enable bit of the group",
+		func(pad *lintPad) bool {
+			return len(GpiRouteCheck(pad.dw0.ValueGet(), pad.gpi, pad.ownership)) != 0
+		}},
+	{"nf-undefined", LintWarning, "pad mode has no native function on this platform",
+		func(pad *lintPad) bool {
+			mode := pad.dw0.GetPadMode()
+			known, name := pad.platform.NativeFunctionGet(pad.id, mode)
+			return mode != 0 && known && name == ""
+		}},
+	{"nf-buf-disable", LintInfo, "native function pad has GPIO RX/TX buffer disabled, " +
+		"PAD_CFG_NF does not set it",
+		func(pad *lintPad) bool {
+			return !pad.isGpio() && pad.dw0.GetGPIORxTxDisableStatus() != 0
+		}},
+	{"nc-reset", LintInfo, "not connected pad has reset source other than DEEP or " +
+		"RX level/edge configuration other than OFF, PAD_NC sets DEEP and TRIG(OFF)",
+		func(pad *lintPad) bool {
+			deep, _ := pad.platform.RstSrcEncode(pad.id, RST_DEEP)
+			return pad.isGpio() && pad.isRxDisabled() && pad.isTxDisabled() &&
+				pad.dw1.GetIOStandbyState() == TxDRxE &&
+				(pad.dw0.GetResetConfig() != deep ||
+					pad.dw0.GetRXLevelEdgeConfiguration() != TRIG_OFF)
+		}},
+	{"reset-reserved", LintError, "pad reset config value is reserved on this platform",
+		func(pad *lintPad) bool {
+			_, valid := RstCfgDecode(pad.platform, pad.id, pad.dw0.GetResetConfig())
+			return !valid
+		}},
+	{"ro-field-set", LintWarning, "pull, IO standby or tolerance is set, but the field is " +
+		"read-only on this platform",
+		func(pad *lintPad) bool {
+			ro := pad.ro[PAD_CFG_DW1]
+			for _, mask := range []uint32{TermMask, IOStandbyStateMask,
+				IOStandbyTerminationMask, PadTolMask} {
+				if ro&mask == mask && pad.dw1.ValueGet()&mask != 0 {
+					return true
+				}
+			}
+			return false
+		}},
+	{"tol-1v8-output", LintWarning, "1.8V tolerance is enabled on the GPIO used as output",
+		func(pad *lintPad) bool {
+			return pad.isGpio() && !pad.isTxDisabled() && pad.dw1.GetPadTol() != 0
+		}},
+}
+
+// Lint - checks the pad configuration with the lint rules
+// platform  : platform-specific interface
+// id        : pad id string
+// regs      : DW0-DW3 register values as they are set in the hardware
+// ro        : read-only fields masks of the platform
+// ownership : host software ownership
+// gpi       : GPI group registers bits, nil if there are no such registers
+// return
+//     list of the rules that the pad configuration violates
+func Lint(platform EncoderSpecific, id string, regs [MAX_DW_NUM]uint32,
+	ro [MAX_DW_NUM]uint32, ownership uint8, gpi map[string]uint8) []*LintRule {
+	var violated []*LintRule
+	pad := lintPad{
+		platform:  platform,
+		id:        id,
+		dw0:       &Register{value: regs[PAD_CFG_DW0]},
+		dw1:       &Register{value: regs[PAD_CFG_DW1]},
+		ro:        ro,
+		ownership: ownership,
+		gpi:       gpi,
+	}
+	for i := range LintRules {
+		if LintRules[i].check(&pad) {
+			violated = append(violated, &LintRules[i])
+		}
+	}
+	return violated
+}
@@ -0,0 +1,76 @@
+package common_test
+
+import (
+	"reflect"
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/snr"
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name      string
+		dw0       uint32
+		dw1       uint32
+		ownership uint8
+		gpi       map[string]uint8
+		rules     []string
+	}{
+		{"native function", 0x44000400, 0x00003000, common.PAD_OWN_ACPI, nil, nil},
+		{"GPIO output", 0x84000201, 0x00000000, common.PAD_OWN_ACPI, nil, nil},
+		{"GPIO input", 0x80880100, 0x00000000, common.PAD_OWN_ACPI,
+			map[string]uint8{common.GpiGpeEn: 1}, nil},
+		{"output with route", 0x84080201, 0x00000000, common.PAD_OWN_ACPI, nil,
+			[]string{"gpo-rx-route"}},
+		{"output with trigger", 0x80000201, 0x00000000, common.PAD_OWN_ACPI, nil,
+			[]string{"gpo-trig"}},
+		{"three routes", 0x801c0100, 0x00000000, common.PAD_OWN_ACPI, nil,
+			[]string{"gpi-multi-route"}},
+		{"edge without route", 0x82000100, 0x00000000, common.PAD_OWN_ACPI, nil,
+			[]string{"edge-no-route"}},
+		{"edge without route, driver", 0x82000100, 0x00000000, common.PAD_OWN_DRIVER, nil,
+			nil},
+		{"NMI, driver", 0x80020100, 0x00000000, common.PAD_OWN_DRIVER, nil,
+			[]string{"nmi-driver-owned"}},
+		{"SCI without GPE_EN", 0x80880100, 0x00000000, common.PAD_OWN_ACPI,
+			map[string]uint8{common.GpiGpeEn: 0}, []string{"gpi-route-mismatch"}},
+		{"undefined mode", 0x44001c00, 0x00000000, common.PAD_OWN_ACPI, nil,
+			[]string{"nf-undefined"}},
+		{"native function with buffer disable", 0x44000700, 0x00000000,
+			common.PAD_OWN_ACPI, nil, []string{"nf-buf-disable"}},
+		{"not connected with PLTRST", 0x84000300, 0x00024000, common.PAD_OWN_ACPI, nil,
+			[]string{"nc-reset", "ro-field-set"}},
+		{"reserved reset", 0xc4000400, 0x00000000, common.PAD_OWN_ACPI, nil,
+			[]string{"reset-reserved"}},
+		{"1.8V output", 0x84000201, 0x02000000, common.PAD_OWN_ACPI, nil,
+			[]string{"tol-1v8-output"}},
+	}
+	ro := snrReadOnlyGet()
+	for _, test := range tests {
+		regs := [common.MAX_DW_NUM]uint32{test.dw0, test.dw1}
+		var rules []string
+		for _, rule := range common.Lint(snr.PlatformSpecific{}, "GPP_A1", regs, ro,
+			test.ownership, test.gpi) {
+			rules = append(rules, rule.Id)
+		}
+		if !reflect.DeepEqual(rules, test.rules) {
+			t.Errorf("%s: got %v, want %v", test.name, rules, test.rules)
+		}
+	}
+}
+
+// TestLintRules - the rule IDs are used to suppress the rules, so they must
+// be unique
+func TestLintRules(t *testing.T) {
+	ids := make(map[string]bool)
+	for _, rule := range common.LintRules {
+		if ids[rule.Id] {
+			t.Errorf("rule %s is repeated", rule.Id)
+		}
+		ids[rule.Id] = true
+		if common.LintSeverityGet(rule.Severity) == "" {
+			t.Errorf("rule %s has unknown severity %d", rule.Id, rule.Severity)
+		}
+	}
+}
@@ -1,14 +1,15 @@
 package common
 
+import "fmt"
 import "strconv"
-import "sync"
 
-import "../../config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
 
 type Fields interface {
-	DecodeDW0()
-	DecodeDW1()
-	GenerateString()
+	DecodeDW0(macro *Macro)
+	DecodeDW1(macro *Macro)
+	DecodeDW2(macro *Macro)
+	GenerateString(macro *Macro)
 }
 
 const (
@@ -16,6 +17,16 @@ const (
 	PAD_OWN_DRIVER = 1
 )
 
+// Pad lock state from the PADCFGLOCK and PADCFGLOCKTX registers. The default
+// state means that the lock registers are not in the dump.
+const (
+	PAD_LOCK_DEFAULT = 0x0
+	PAD_LOCK_CONFIG  = 0x1
+	PAD_LOCK_TX      = 0x2
+	PAD_LOCK_FULL    = PAD_LOCK_CONFIG | PAD_LOCK_TX
+	PAD_UNLOCK       = 0x4
+)
+
 const (
 	TxLASTRxE     = 0x0
 	Tx0RxDCRx0    = 0x1
@@ -51,43 +62,128 @@ const (
 	RST_RSMRST = 3
 )
 
+// Names of the bit field values used as arguments in the pad configuration
+// macros. These tables are shared by the macro generator and the encoder.
+var resetsrc = map[uint8]string{
+	RST_PWROK:  "PWROK",
+	RST_DEEP:   "DEEP",
+	RST_PLTRST: "PLTRST",
+	RST_RSMRST: "RSMRST",
+}
+
+var trig = map[uint8]string{
+	TRIG_LEVEL:       "LEVEL",
+	TRIG_EDGE_SINGLE: "EDGE_SINGLE",
+	TRIG_OFF:         "OFF",
+	TRIG_EDGE_BOTH:   "EDGE_BOTH",
+}
+
+var buffDisStat = map[uint8]string{
+	0x0: "NO_DISABLE",    // both buffers are enabled
+	0x1: "TX_DISABLE",    // output buffer is disabled
+	0x2: "RX_DISABLE",    // input buffer is disabled
+	0x3: "TX_RX_DISABLE", // both buffers are disabled
+}
+
+var stateMacro = map[uint8]string{
+	TxLASTRxE:     "TxLASTRxE",
+	Tx0RxDCRx0:    "Tx0RxDCRx0",
+	Tx0RxDCRx1:    "Tx0RxDCRx1",
+	Tx1RxDCRx0:    "Tx1RxDCRx0",
+	Tx1RxDCRx1:    "Tx1RxDCRx1",
+	Tx0RxE:        "Tx0RxE",
+	Tx1RxE:        "Tx1RxE",
+	HIZCRx0:       "HIZCRx0",
+	HIZCRx1:       "HIZCRx1",
+	TxDRxE:        "TxDRxE",
+	StandbyIgnore: "IGNORE",
+}
+
+var ioTermMacro = map[uint8]string{
+	IOSTERM_SAME:    "SAME",
+	IOSTERM_DISPUPD: "DISPUPD",
+	IOSTERM_ENPD:    "ENPD",
+	IOSTERM_ENPU:    "ENPU",
+}
+
+// names of the pad lock states as in enum gpio_lock_action without the
+// GPIO_LOCK_ prefix
+var padLock = map[uint8]string{
+	PAD_LOCK_CONFIG: "CONFIG",
+	PAD_LOCK_TX:     "TX",
+	PAD_LOCK_FULL:   "FULL",
+	PAD_UNLOCK:      "UNLOCK",
+}
+
+// LockNameGet - returns the name of the pad lock state, an empty string if the
+// state is unknown
+func LockNameGet(lock uint8) string {
+	return padLock[lock]
+}
+
+// LockValueGet - returns the pad lock state by its name without the GPIO_LOCK_
+// prefix, e.g. FULL
+// return false if the name is unknown
+func LockValueGet(name string) (uint8, bool) {
+	for lock, lockName := range padLock {
+		if lockName == name {
+			return lock, true
+		}
+	}
+	return PAD_LOCK_DEFAULT, false
+}
+
+// PAD_CFG2_DEBOUNCE_x_RTC
+var debounce = map[uint8]string{
+	0x3: "8_RTC",
+	0x4: "16_RTC",
+	0x5: "32_RTC",
+	0x6: "64_RTC",
+	0x7: "128_RTC",
+	0x8: "256_RTC",
+	0x9: "512_RTC",
+	0xa: "1K_RTC",
+	0xb: "2K_RTC",
+	0xc: "4K_RTC",
+	0xd: "8K_RTC",
+	0xe: "16K_RTC",
+	0xf: "32K_RTC",
+}
+
 // PlatformSpecific - platform-specific interface
 type PlatformSpecific interface {
-	RemmapRstSrc()
-	Pull()
-	GpiMacroAdd()
-	GpoMacroAdd()
-	NativeFunctionMacroAdd()
-	NoConnMacroAdd()
+	RemmapRstSrc(macro *Macro)
+	Pull(macro *Macro)
+	GpiMacroAdd(macro *Macro)
+	GpoMacroAdd(macro *Macro)
+	NativeFunctionMacroAdd(macro *Macro)
+	NoConnMacroAdd(macro *Macro)
 }
 
 // Macro - contains macro information and methods
 // Platform : platform-specific interface
+// Options  : converter settings
 // padID    : pad ID string
 // str      : macro string entirely
 // Reg      : structure of configuration register values and their masks
+// lock     : pad lock state, PAD_LOCK_DEFAULT if it is unknown
 type Macro struct {
 	Platform  PlatformSpecific
+	Options   *config.Options
 	Reg       [MAX_DW_NUM]Register
 	padID     string
 	str       string
 	ownership uint8
+	lock      uint8
 	Fields
 }
 
-var	instanceMacro *Macro
-var	once           sync.Once
-
-// GetInstance returns singleton
-func GetInstanceMacro(p PlatformSpecific, f Fields) *Macro {
-	once.Do(func() {
-		instanceMacro = &Macro{ Platform : p, Fields : f }
-	})
-	return instanceMacro
-}
-
-func GetMacro() *Macro {
-	return GetInstanceMacro(nil, nil)
+// NewMacro - creates a new macro object for the pad
+// p    : platform-specific interface
+// f    : bit fields macros interface
+// opts : converter settings
+func NewMacro(p PlatformSpecific, f Fields, opts *config.Options) *Macro {
+	return &Macro{ Platform : p, Fields : f, Options : opts }
 }
 
 func (macro *Macro) PadIdGet() string {
@@ -108,6 +204,15 @@ func (macro *Macro) IsOwnershipDriver() bool {
 	return macro.ownership == PAD_OWN_DRIVER
 }
 
+func (macro *Macro) SetPadLock(lock uint8) *Macro {
+	macro.lock = lock
+	return macro
+}
+
+func (macro *Macro) LockGet() uint8 {
+	return macro.lock
+}
+
 // returns <Register> data configuration structure
 // number : register number
 func (macro *Macro) Register(number uint8) *Register {
@@ -157,19 +262,13 @@ func (macro *Macro) Separator() *Macro {
 // return: Macro
 func (macro *Macro) Rstsrc() *Macro {
 	dw0 := macro.Register(PAD_CFG_DW0)
-	var resetsrc = map[uint8]string {
-		0: "PWROK",
-		1: "DEEP",
-		2: "PLTRST",
-		3: "RSMRST",
-	}
 	return macro.Separator().Add(resetsrc[dw0.GetResetConfig()])
 }
 
 // Adds The Pad Termination (TERM) parameter from DW1 to the macro as a new argument
 // return: Macro
 func (macro *Macro) Pull() *Macro {
-	macro.Platform.Pull()
+	macro.Platform.Pull(macro)
 	return macro
 }
 
@@ -184,12 +283,6 @@ func (macro *Macro) Val() *Macro {
 // return: Macro
 func (macro *Macro) Trig() *Macro {
 	dw0 := macro.Register(PAD_CFG_DW0)
-	var trig = map[uint8]string{
-		0x0: "LEVEL",
-		0x1: "EDGE_SINGLE",
-		0x2: "OFF",
-		0x3: "EDGE_BOTH",
-	}
 	return macro.Separator().Add(trig[dw0.GetRXLevelEdgeConfiguration()])
 }
 
@@ -206,12 +299,6 @@ func (macro *Macro) Invert() *Macro {
 // Adds input/output buffer state
 // return: Macro
 func (macro *Macro) Bufdis() *Macro {
-	var buffDisStat = map[uint8]string{
-		0x0: "NO_DISABLE",    // both buffers are enabled
-		0x1: "TX_DISABLE",    // output buffer is disabled
-		0x2: "RX_DISABLE",    // input buffer is disabled
-		0x3: "TX_RX_DISABLE", // both buffers are disabled
-	}
 	state := macro.Register(PAD_CFG_DW0).GetGPIORxTxDisableStatus()
 	return macro.Separator().Add(buffDisStat[state])
 }
@@ -240,19 +327,6 @@ func (macro *Macro) Padfn() *Macro {
 // Add a line to the macro that defines IO Standby State
 // return: macro
 func (macro *Macro) IOSstate() *Macro {
-	var stateMacro = map[uint8]string{
-		TxLASTRxE:     "TxLASTRxE",
-		Tx0RxDCRx0:    "Tx0RxDCRx0",
-		Tx0RxDCRx1:    "Tx0RxDCRx1",
-		Tx1RxDCRx0:    "Tx1RxDCRx0",
-		Tx1RxDCRx1:    "Tx1RxDCRx1",
-		Tx0RxE:        "Tx0RxE",
-		Tx1RxE:        "Tx1RxE",
-		HIZCRx0:       "HIZCRx0",
-		HIZCRx1:       "HIZCRx1",
-		TxDRxE:        "TxDRxE",
-		StandbyIgnore: "IGNORE",
-	}
 	dw1 := macro.Register(PAD_CFG_DW1)
 	str, valid := stateMacro[dw1.GetIOStandbyState()]
 	if !valid {
@@ -265,19 +339,27 @@ func (macro *Macro) IOSstate() *Macro {
 // Add a line to the macro that defines IO Standby Termination
 // return: macro
 func (macro *Macro) IOTerm() *Macro {
-	var ioTermMacro = map[uint8]string{
-		IOSTERM_SAME:    "SAME",
-		IOSTERM_DISPUPD: "DISPUPD",
-		IOSTERM_ENPD:    "ENPD",
-		IOSTERM_ENPU:    "ENPU",
-	}
 	dw1 := macro.Register(PAD_CFG_DW1)
 	return macro.Separator().Add(ioTermMacro[dw1.GetIOStandbyTermination()])
 }
 
+// Adds the debounce duration (DEBOUNCE) from DW2 to the macro. Used only for
+// the bit field macros, so the separator is not added
+// return: macro
+func (macro *Macro) Debounce() *Macro {
+	dw2 := macro.Register(PAD_CFG_DW2)
+	str, valid := debounce[dw2.GetDebounceDuration()]
+	if !valid {
+		// reserved value
+		return macro.Add(fmt.Sprintf("(0x%x << 1)", dw2.GetDebounceDuration()))
+	}
+	return macro.Add("PAD_CFG2_DEBOUNCE_" + str)
+}
+
 // Check created macro
 func (macro *Macro) check() *Macro {
-	if !macro.Register(PAD_CFG_DW0).MaskCheck() {
+	if !macro.Register(PAD_CFG_DW0).MaskCheck() ||
+		!macro.Register(PAD_CFG_DW2).MaskCheck() {
 		return macro.GenerateFields()
 	}
 	return macro
@@ -286,7 +368,9 @@ func (macro *Macro) check() *Macro {
 // or - Set " | " if its needed
 func (macro *Macro) Or() *Macro {
 
-		if str := macro.Get(); str[len(str) - 1] == ')' {
+		// the previous bit field macro can be a name without brackets,
+		// e.g. PAD_CFG1_TOL_1V8 or PAD_CFG2_DEBEN
+		if str := macro.Get(); str[len(str) - 1] != ' ' && str[len(str) - 1] != '(' {
 			macro.Add(" | ")
 		}
 		return macro
@@ -295,7 +379,7 @@ func (macro *Macro) Or() *Macro {
 // AddToMacroIgnoredMask - Print info about ignored field mask
 // title - warning message
 func (macro *Macro) AddToMacroIgnoredMask() *Macro {
-	if config.InfoLevelGet() < 4 || config.IsFspStyleMacro() {
+	if macro.Options.InfoLevelGet() < 4 || macro.Options.IsFspStyleMacro() {
 		return macro
 	}
 	dw0 := macro.Register(PAD_CFG_DW0)
@@ -307,7 +391,7 @@ func (macro *Macro) AddToMacroIgnoredMask() *Macro {
 		dw0temp := dw0.ValueGet()
 		dw0.ValueSet(dw0Ignored)
 		macro.Add("\n\t/* DW0 : ")
-		macro.Fields.DecodeDW0()
+		macro.Fields.DecodeDW0(macro)
 		macro.Add(" - IGNORED */")
 		dw0.ValueSet(dw0temp)
 	}
@@ -315,10 +399,19 @@ func (macro *Macro) AddToMacroIgnoredMask() *Macro {
 		dw1temp	:= dw1.ValueGet()
 		dw1.ValueSet(dw1Ignored)
 		macro.Add("\n\t/* DW1 : ")
-		macro.Fields.DecodeDW1()
+		macro.Fields.DecodeDW1(macro)
 		macro.Add(" - IGNORED */")
 		dw1.ValueSet(dw1temp)
 	}
+	dw2 := macro.Register(PAD_CFG_DW2)
+	if dw2Ignored := dw2.IgnoredFieldsGet(); dw2Ignored != 0 {
+		dw2temp := dw2.ValueGet()
+		dw2.ValueSet(dw2Ignored)
+		macro.Add("\n\t/* DW2 : ")
+		macro.Fields.DecodeDW2(macro)
+		macro.Add(" - IGNORED */")
+		dw2.ValueSet(dw2temp)
+	}
 	return macro
 }
 
@@ -326,14 +419,16 @@ func (macro *Macro) AddToMacroIgnoredMask() *Macro {
 func (macro *Macro) GenerateFields() *Macro {
 	dw0 := macro.Register(PAD_CFG_DW0)
 	dw1 := macro.Register(PAD_CFG_DW1)
+	dw2 := macro.Register(PAD_CFG_DW2)
 
 	// Get mask of ignored bit fields.
 	dw0Ignored := dw0.IgnoredFieldsGet()
 	dw1Ignored := dw1.IgnoredFieldsGet()
+	dw2Ignored := dw2.IgnoredFieldsGet()
 
-	if config.InfoLevelGet() <= 1 {
+	if macro.Options.InfoLevelGet() <= 1 {
 		macro.Clear()
-	} else if config.InfoLevelGet() >= 3 {
+	} else if macro.Options.InfoLevelGet() >= 3 {
 		// Add string of reference macro as a comment
 		reference := macro.Get()
 		macro.Clear()
@@ -341,7 +436,7 @@ func (macro *Macro) GenerateFields() *Macro {
 		macro.AddToMacroIgnoredMask()
 		macro.Add("\n\t")
 	}
-	if config.AreFieldsIgnored() {
+	if macro.Options.AreFieldsIgnored() {
 		// Consider bit fields that should be ignored when regenerating
 		// advansed macros
 		var tempVal uint32 = dw0.ValueGet() & ^dw0Ignored
@@ -349,9 +444,12 @@ func (macro *Macro) GenerateFields() *Macro {
 
 		tempVal = dw1.ValueGet() & ^dw1Ignored
 		dw1.ValueSet(tempVal)
+
+		tempVal = dw2.ValueGet() & ^dw2Ignored
+		dw2.ValueSet(tempVal)
 	}
 
-	macro.Fields.GenerateString()
+	macro.Fields.GenerateString(macro)
 	return macro
 }
 
@@ -382,33 +480,33 @@ const (
 func (macro *Macro) Generate() string {
 	dw0 := macro.Register(PAD_CFG_DW0)
 
-	macro.Platform.RemmapRstSrc()
+	macro.Platform.RemmapRstSrc(macro)
 	macro.Set("PAD_CFG")
 	if dw0.GetPadMode() == 0 {
 		// GPIO
 		switch dw0.GetGPIORxTxDisableStatus() {
 		case txDisable:
-			macro.Platform.GpiMacroAdd() // GPI
+			macro.Platform.GpiMacroAdd(macro) // GPI
 
 		case rxDisable:
-			macro.Platform.GpoMacroAdd() // GPO
+			macro.Platform.GpoMacroAdd(macro) // GPO
 
 		case rxDisable | txDisable:
-			macro.Platform.NoConnMacroAdd() // NC
+			macro.Platform.NoConnMacroAdd(macro) // NC
 
 		default:
 			macro.Bidirection()
 		}
 	} else {
-		macro.Platform.NativeFunctionMacroAdd()
+		macro.Platform.NativeFunctionMacroAdd(macro)
 	}
 
-	if config.IsFieldsMacroUsed() {
+	if macro.Options.IsFieldsMacroUsed() {
 		// Clear control mask to generate advanced macro only
 		return macro.GenerateFields().Get()
 	}
 
-	if config.IsNonCheckingFlagUsed() {
+	if macro.Options.IsNonCheckingFlagUsed() {
 		macro.AddToMacroIgnoredMask()
 		return macro.Get()
 	}
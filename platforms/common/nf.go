@@ -0,0 +1,22 @@
+package common
+
+// NativeFunctions - signal names of the pad modes NF1, NF2, ... for each pad.
+// An empty name means that the pad mode has no function.
+type NativeFunctions map[string][]string
+
+// Get - returns the signal name of the native function
+// id   : pad ID string
+// mode : pad mode (PMODE), 1 for NF1
+// return
+//     true if the pad is in the table
+//     signal name, empty if the pad mode has no function or mode is GPIO
+func (table NativeFunctions) Get(id string, mode uint8) (bool, string) {
+	functions, valid := table[id]
+	if !valid {
+		return false, ""
+	}
+	if mode == 0 || int(mode) > len(functions) {
+		return true, ""
+	}
+	return true, functions[mode-1]
+}
@@ -0,0 +1,35 @@
+package common_test
+
+import (
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+func TestNativeFunctionsGet(t *testing.T) {
+	table := common.NativeFunctions{
+		"GPP_A1": {"LAD0", "", "ISH_GP6"},
+		"GPD7":   {},
+	}
+	tests := []struct {
+		id    string
+		mode  uint8
+		known bool
+		name  string
+	}{
+		{"GPP_A1", 1, true, "LAD0"},
+		{"GPP_A1", 2, true, ""},
+		{"GPP_A1", 3, true, "ISH_GP6"},
+		{"GPP_A1", 4, true, ""},
+		{"GPP_A1", 0, true, ""},
+		{"GPD7", 1, true, ""},
+		{"GPP_A2", 1, false, ""},
+	}
+	for _, test := range tests {
+		known, name := table.Get(test.id, test.mode)
+		if known != test.known || name != test.name {
+			t.Errorf("%s NF%d: got %v %q, want %v %q", test.id, test.mode, known, name,
+				test.known, test.name)
+		}
+	}
+}
@@ -50,7 +50,9 @@ const (
 const (
 	PAD_CFG_DW0 = 0
 	PAD_CFG_DW1 = 1
-	MAX_DW_NUM  = 2
+	PAD_CFG_DW2 = 2
+	PAD_CFG_DW3 = 3
+	MAX_DW_NUM  = 4
 )
 
 // Register - configuration data structure based on DW0/1 dw value
@@ -94,6 +96,15 @@ func (reg *Register) getFieldVal(mask uint32, shift uint8) uint8 {
 	return uint8((reg.value & mask) >> shift)
 }
 
+// setFieldVal - set the value of the bit field in the register
+// mask  : bit field mask
+// shift : bit field shift
+// val   : new bit field value
+func (reg *Register) setFieldVal(mask uint32, shift uint8, val uint8) *Register {
+	reg.value = (reg.value & ^mask) | ((uint32(val) << shift) & mask)
+	return reg
+}
+
 // CntrMaskFieldsClear - clear filed in control mask
 // fieldMask - mask of the field to be cleared
 func (reg *Register) CntrMaskFieldsClear(fieldMask uint32) {
@@ -260,3 +271,22 @@ func (reg *Register) GetTermination() uint8 {
 func (reg *Register) GetInterruptSelect() uint8 {
 	return reg.getFieldVal(InterruptSelectMask, 0)
 }
+
+// Bit field constants for PAD_CFG_DW2 register
+const (
+	DebounceDurationShift uint8  = 1
+	DebounceDurationMask  uint32 = 0xF << DebounceDurationShift
+
+	DebounceEnableMask uint32 = 0x1
+)
+
+// GetDebounceEnable - returns 1 if the debounce filter is enabled (DEBEN)
+func (reg *Register) GetDebounceEnable() uint8 {
+	return reg.getFieldVal(DebounceEnableMask, 0)
+}
+
+// GetDebounceDuration - returns the debounce duration (DEBOUNCE)
+// Debounce Duration = (2 ^ value) * RTC clock duration, 0h-2h = reserved
+func (reg *Register) GetDebounceDuration() uint8 {
+	return reg.getFieldVal(DebounceDurationMask, DebounceDurationShift)
+}
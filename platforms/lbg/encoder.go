@@ -0,0 +1,42 @@
+package lbg
+
+// Local packages
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// PullEncode - returns the Pad Termination (TERM) field value for the pull
+// configuration used in the macro
+// name : pull configuration from the macro
+func (platform PlatformSpecific) PullEncode(name string) (uint8, bool) {
+	return platform.InheritanceMacro.PullEncode(name)
+}
+
+// PullDecode - returns the pull configuration name used in the macro for the
+// Pad Termination (TERM) field value
+// term : TERM field value
+func (platform PlatformSpecific) PullDecode(term uint8) (string, bool) {
+	return platform.InheritanceMacro.PullDecode(term)
+}
+
+// RstSrcEncode - returns the Pad Reset Source Config (PADRSTCFG) field value
+// for the logical reset source used in the macro
+// id  : pad id string
+// rst : logical reset source
+func (PlatformSpecific) RstSrcEncode(id string, rst uint8) (uint8, bool) {
+	for rstcfg, logical := range remapping {
+		if logical == uint32(rst) << common.PadRstCfgShift {
+			return rstcfg, true
+		}
+	}
+	return 0, false
+}
+
+// NativeFunctionGet - returns the signal name of the native function
+// id   : pad ID string
+// mode : pad mode (PMODE), 1 for NF1
+// return
+//     bool   : true if the pad is in the table
+//     string : signal name, empty if the pad mode has no function
+func (PlatformSpecific) NativeFunctionGet(id string, mode uint8) (bool, string) {
+	// Not supported, the pads differ from Sunrise Point
+	return false, ""
+}
@@ -0,0 +1,50 @@
+package lbg_test
+
+import (
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/lbg"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/snr"
+
+func TestMacroEncode(t *testing.T) {
+	tests := []struct {
+		macro string
+		dw0   uint32
+		dw1   uint32
+	}{
+		{"PAD_CFG_NF(GPP_A1, 20K_PU, DEEP, NF1),", 0x40000400, 0x00003000},
+		{"PAD_CFG_GPO(GPP_A2, 1, PLTRST),", 0x84000201, 0x00000000},
+		// RSMRST is PADRSTCFG 0 on Lewisburg
+		{"PAD_CFG_GPO(GPP_A2, 1, RSMRST),", 0x04000201, 0x00000000},
+		{"PAD_CFG_GPI_SCI(GPP_A3, NONE, PLTRST, LEVEL, INVERT),", 0x80880100, 0x00000000},
+		{"PAD_NC(GPP_A4, NONE),", 0x44000300, 0x00024000},
+	}
+	platform := lbg.PlatformSpecific{
+		InheritanceMacro:    snr.PlatformSpecific{},
+		InheritanceTemplate: snr.PlatformSpecific{},
+	}
+	opts := &config.Options{}
+	opts.PlatformSet("lbg")
+	opts.FldStyleSet("none")
+	for _, test := range tests {
+		cfg, err := common.MacroEncode(platform, test.macro)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.macro, err)
+			continue
+		}
+		dw0 := cfg.Register(common.PAD_CFG_DW0).ValueGet()
+		dw1 := cfg.Register(common.PAD_CFG_DW1).ValueGet()
+		if dw0 != test.dw0 || dw1 != test.dw1 {
+			t.Errorf("%s: got 0x%08x 0x%08x, want 0x%08x 0x%08x", test.macro, dw0, dw1,
+				test.dw0, test.dw1)
+		}
+		macro := platform.GenMacro(cfg.Id, dw0, dw1, 0, cfg.Ownership,
+			common.PAD_LOCK_DEFAULT, opts)
+		if macro != test.macro {
+			t.Errorf("%s: generated %s", test.macro, macro)
+		}
+	}
+}
@@ -1,12 +1,10 @@
 package lbg
 
-import "fmt"
-
 // Local packages
-import "../../config"
-import "../../fields"
-import "../common"
-import "../snr"
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/fields"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/snr"
 
 const (
 	PAD_CFG_DW0_RO_FIELDS = (0x1 << 27) | (0x1 << 24) | (0x3 << 21) | (0xf << 16) | 0xfc
@@ -20,11 +18,13 @@ const (
 )
 
 type InheritanceMacro interface {
-	Pull()
-	GpiMacroAdd()
-	GpoMacroAdd()
-	NativeFunctionMacroAdd()
-	NoConnMacroAdd()
+	Pull(macro *common.Macro)
+	GpiMacroAdd(macro *common.Macro)
+	GpoMacroAdd(macro *common.Macro)
+	NativeFunctionMacroAdd(macro *common.Macro)
+	NoConnMacroAdd(macro *common.Macro)
+	PullEncode(pull string) (uint8, bool)
+	PullDecode(term uint8) (string, bool)
 }
 
 type PlatformSpecific struct {
@@ -32,70 +32,73 @@ type PlatformSpecific struct {
 	InheritanceTemplate
 }
 
+// Pad Reset Source Config remapping: PADRSTCFG field value -> logical reset
+var remapping = map[uint8]uint32{
+	0: common.RST_RSMRST << common.PadRstCfgShift,
+	1: common.RST_DEEP   << common.PadRstCfgShift,
+	2: common.RST_PLTRST << common.PadRstCfgShift,
+}
+
 // RemmapRstSrc - remmap Pad Reset Source Config
-func (PlatformSpecific) RemmapRstSrc() {
-	macro := common.GetMacro()
-	if config.TemplateGet() != config.TempInteltool {
-		// Use reset source remapping only if the input file is inteltool.log dump
-		return
-	}
+func (PlatformSpecific) RemmapRstSrc(macro *common.Macro) {
 	dw0 := macro.Register(PAD_CFG_DW0)
-	var remapping = map[uint8]uint32{
-		0: common.RST_RSMRST << common.PadRstCfgShift,
-		1: common.RST_DEEP   << common.PadRstCfgShift,
-		2: common.RST_PLTRST << common.PadRstCfgShift,
-	}
 	resetsrc, valid := remapping[dw0.GetResetConfig()]
 	if valid {
 		// dw0.SetResetConfig(resetsrc)
-		ResetConfigFieldVal := (dw0.ValueGet() & 0x3fffffff) | remapping[dw0.GetResetConfig()]
+		ResetConfigFieldVal := (dw0.ValueGet() & 0x3fffffff) | resetsrc
 		dw0.ValueSet(ResetConfigFieldVal)
-	} else {
-		fmt.Println("Invalid Pad Reset Config [ 0x", resetsrc ," ] for ", macro.PadIdGet())
 	}
 	dw0.CntrMaskFieldsClear(common.PadRstCfgMask)
 }
 
 // Adds The Pad Termination (TERM) parameter from PAD_CFG_DW1 to the macro
 // as a new argument
-func (platform PlatformSpecific) Pull() {
-	platform.InheritanceMacro.Pull()
+func (platform PlatformSpecific) Pull(macro *common.Macro) {
+	platform.InheritanceMacro.Pull(macro)
 }
 
 // Adds PAD_CFG_GPI macro with arguments
-func (platform PlatformSpecific) GpiMacroAdd() {
-	platform.InheritanceMacro.GpiMacroAdd()
+func (platform PlatformSpecific) GpiMacroAdd(macro *common.Macro) {
+	platform.InheritanceMacro.GpiMacroAdd(macro)
 }
 
 // Adds PAD_CFG_GPO macro with arguments
-func (platform PlatformSpecific) GpoMacroAdd() {
-	platform.InheritanceMacro.GpoMacroAdd()
+func (platform PlatformSpecific) GpoMacroAdd(macro *common.Macro) {
+	platform.InheritanceMacro.GpoMacroAdd(macro)
 }
 
 // Adds PAD_CFG_NF macro with arguments
-func (platform PlatformSpecific) NativeFunctionMacroAdd() {
-	platform.InheritanceMacro.NativeFunctionMacroAdd()
+func (platform PlatformSpecific) NativeFunctionMacroAdd(macro *common.Macro) {
+	platform.InheritanceMacro.NativeFunctionMacroAdd(macro)
 }
 
 // Adds PAD_NC macro
-func (platform PlatformSpecific) NoConnMacroAdd() {
-	platform.InheritanceMacro.NoConnMacroAdd()
+func (platform PlatformSpecific) NoConnMacroAdd(macro *common.Macro) {
+	platform.InheritanceMacro.NoConnMacroAdd(macro)
+}
+
+// ReadOnlyFieldsGet - returns the mask of read-only fields
+// number : configuration register number
+func (PlatformSpecific) ReadOnlyFieldsGet(number uint8) uint32 {
+	// There are no DW2/DW3 registers on this platform
+	var ro = [MAX_DW_NUM]uint32{PAD_CFG_DW0_RO_FIELDS, PAD_CFG_DW1_RO_FIELDS,
+			common.AllFields, common.AllFields}
+	return ro[number]
 }
 
 // GenMacro - generate pad macro
 // dw0 : DW0 config register value
 // dw1 : DW1 config register value
+// dw2 : DW2 config register value (not used on this platform)
 // return: string of macro
 //         error
-func (platform PlatformSpecific) GenMacro(id string, dw0 uint32, dw1 uint32, ownership uint8) string {
+func (platform PlatformSpecific) GenMacro(id string, dw0 uint32, dw1 uint32, dw2 uint32, ownership uint8,
+		lock uint8, opts *config.Options) string {
 	// The GPIO controller architecture in Lewisburg and Sunrise are very similar,
 	// so we will inherit some platform-dependent functions from Sunrise.
-	macro := common.GetInstanceMacro(PlatformSpecific{InheritanceMacro : snr.PlatformSpecific{}},
-			fields.InterfaceGet())
-	macro.Clear()
-	macro.Register(PAD_CFG_DW0).CntrMaskFieldsClear(common.AllFields)
-	macro.Register(PAD_CFG_DW0).CntrMaskFieldsClear(common.AllFields)
-	macro.PadIdSet(id).SetPadOwnership(ownership)
+	macro := common.NewMacro(PlatformSpecific{InheritanceMacro : snr.PlatformSpecific{}},
+			fields.InterfaceGet(opts), opts)
+	macro.PadIdSet(id).SetPadOwnership(ownership).SetPadLock(lock)
 	macro.Register(PAD_CFG_DW0).ValueSet(dw0).ReadOnlyFieldsSet(PAD_CFG_DW0_RO_FIELDS)
 	macro.Register(PAD_CFG_DW1).ValueSet(dw1).ReadOnlyFieldsSet(PAD_CFG_DW1_RO_FIELDS)
 	return macro.Generate()
@@ -1,8 +1,12 @@
 package lbg
 
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
 type InheritanceTemplate interface {
 	GroupNameExtract(line string) (bool, string)
 	KeywordCheck(line string) bool
+	GpeGroupGet(number uint8) (bool, string)
+	AcpiPinGet(pad common.PadPosition) (string, string, bool)
 }
 
 // GroupNameExtract - This function extracts the group ID, if it exists in a row
@@ -20,3 +24,24 @@ func (platform PlatformSpecific) GroupNameExtract(line string) (bool, string) {
 func (platform PlatformSpecific) KeywordCheck(line string) bool {
 	return platform.InheritanceTemplate.KeywordCheck(line)
 }
+
+// GpeGroupGet - returns the group name for the group number in the GPE0_DW0/1/2
+// fields of the MISCCFG register
+// number : group number
+// return
+//     bool   : true if the group number is known
+//     string : group identifier
+func (platform PlatformSpecific) GpeGroupGet(number uint8) (bool, string) {
+	return platform.InheritanceTemplate.GpeGroupGet(number)
+}
+
+// AcpiPinGet - returns the ACPI path of the GPIO controller and the pin number
+// of the pad
+// pad : pad position
+// return
+//     string : ACPI path of the GPIO controller
+//     string : pin number
+//     bool   : false if the pin number is unknown
+func (platform PlatformSpecific) AcpiPinGet(pad common.PadPosition) (string, string, bool) {
+	return platform.InheritanceTemplate.AcpiPinGet(pad)
+}
@@ -0,0 +1,58 @@
+package snr
+
+import "strings"
+
+// Local packages
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// pullAlias - pull configuration names from the common block gpio_defs.h
+// that can also be used for Sunrise
+var pullAlias = map[string]string{
+	"DN_5K":  "5K_PD",
+	"DN_20K": "20K_PD",
+	"UP_1K":  "1K_PU",
+	"UP_5K":  "5K_PU",
+	"UP_2K":  "2K_PU",
+	"UP_20K": "20K_PU",
+	"UP_667": "667_PU",
+}
+
+// PullEncode - returns the Pad Termination (TERM) field value for the pull
+// configuration used in the macro
+// name : pull configuration from the macro, e.g. 20K_PU
+func (PlatformSpecific) PullEncode(name string) (uint8, bool) {
+	if alias, valid := pullAlias[name]; valid {
+		name = alias
+	}
+	for term, str := range pull {
+		if str == name {
+			return term, true
+		}
+	}
+	return 0, false
+}
+
+// PullDecode - returns the pull configuration name used in the macro for the
+// Pad Termination (TERM) field value
+// term : TERM field value
+func (PlatformSpecific) PullDecode(term uint8) (string, bool) {
+	str, valid := pull[term]
+	return str, valid
+}
+
+// RstSrcEncode - returns the Pad Reset Source Config (PADRSTCFG) field value
+// for the logical reset source used in the macro
+// id  : pad id string
+// rst : logical reset source
+func (PlatformSpecific) RstSrcEncode(id string, rst uint8) (uint8, bool) {
+	if strings.Contains(id, "GPD") {
+		// See RemmapRstSrc()
+		return rst, true
+	}
+	for rstcfg, logical := range remapping {
+		if logical == uint32(rst) << common.PadRstCfgShift {
+			return rstcfg, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,52 @@
+package snr_test
+
+import (
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/snr"
+
+func TestMacroEncode(t *testing.T) {
+	tests := []struct {
+		macro     string
+		dw0       uint32
+		dw1       uint32
+		generated string
+	}{
+		{"PAD_CFG_NF(GPP_A1, UP_20K, DEEP, NF1),", 0x40000400, 0x00003000,
+			"PAD_CFG_NF(GPP_A1, 20K_PU, DEEP, NF1),"},
+		{"PAD_CFG_GPO(GPP_A2, 1, RSMRST),", 0x04000201, 0x00000000,
+			"PAD_CFG_GPO(GPP_A2, 1, RSMRST),"},
+		// GPD pads use the reset source without remapping
+		{"PAD_CFG_GPO(GPD2, 1, RSMRST),", 0xc4000201, 0x00000000,
+			"PAD_CFG_GPO(GPD2, 1, RSMRST),"},
+		{"PAD_CFG_GPI_SCI(GPP_A3, DN_20K, PLTRST, LEVEL, INVERT),", 0x80880100, 0x00001000,
+			"PAD_CFG_GPI_SCI(GPP_A3, 20K_PD, PLTRST, LEVEL, INVERT),"},
+		{"PAD_CFG_GPI_APIC(GPP_B3, NONE, PLTRST, EDGE_SINGLE, INVERT),", 0x82900100,
+			0x00000000, "PAD_CFG_GPI_APIC_IOS(GPP_B3, NONE, PLTRST, EDGE_SINGLE, INVERT, " +
+				"TxLASTRxE, SAME),"},
+	}
+	opts := &config.Options{}
+	opts.PlatformSet("snr")
+	opts.FldStyleSet("none")
+	for _, test := range tests {
+		cfg, err := common.MacroEncode(snr.PlatformSpecific{}, test.macro)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.macro, err)
+			continue
+		}
+		dw0 := cfg.Register(common.PAD_CFG_DW0).ValueGet()
+		dw1 := cfg.Register(common.PAD_CFG_DW1).ValueGet()
+		if dw0 != test.dw0 || dw1 != test.dw1 {
+			t.Errorf("%s: got 0x%08x 0x%08x, want 0x%08x 0x%08x", test.macro, dw0, dw1,
+				test.dw0, test.dw1)
+		}
+		macro := snr.PlatformSpecific{}.GenMacro(cfg.Id, dw0, dw1, 0, cfg.Ownership,
+			common.PAD_LOCK_DEFAULT, opts)
+		if macro != test.generated {
+			t.Errorf("%s: generated %s, want %s", test.macro, macro, test.generated)
+		}
+	}
+}
@@ -1,12 +1,11 @@
 package snr
 
 import "strings"
-import "fmt"
 
 // Local packages
-import "../common"
-import "../../config"
-import "../../fields"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/fields"
 
 const (
 	PAD_CFG_DW0_RO_FIELDS = (0x1 << 27) | (0x1 << 24) | (0x3 << 21) | (0xf << 16) | 0xfc
@@ -19,15 +18,30 @@ const (
 	MAX_DW_NUM  = common.MAX_DW_NUM
 )
 
+// Pad Reset Source Config remapping: PADRSTCFG field value -> logical reset
+var remapping = map[uint8]uint32{
+	0: common.RST_RSMRST << common.PadRstCfgShift,
+	1: common.RST_DEEP   << common.PadRstCfgShift,
+	2: common.RST_PLTRST << common.PadRstCfgShift,
+}
+
+// Pad Termination (TERM) field values
+var pull = map[uint8]string{
+	0x0: "NONE",
+	0x2: "5K_PD",
+	0x4: "20K_PD",
+	0x9: "1K_PU",
+	0xa: "5K_PU",
+	0xb: "2K_PU",
+	0xc: "20K_PU",
+	0xd: "667_PU",
+	0xf: "NATIVE",
+}
+
 type PlatformSpecific struct {}
 
 // RemmapRstSrc - remmap Pad Reset Source Config
-func (PlatformSpecific) RemmapRstSrc() {
-	macro := common.GetMacro()
-	if config.TemplateGet() != config.TempInteltool {
-		// Use reset source remapping only if the input file is inteltool.log dump
-		return
-	}
+func (PlatformSpecific) RemmapRstSrc(macro *common.Macro) {
 	if strings.Contains(macro.PadIdGet(), "GPD") {
 		// See reset map for the Sunrise GPD Group in the Community 2:
 		// https://github.com/coreboot/coreboot/blob/master/src/soc/intel/skylake/gpio.c#L15
@@ -36,52 +50,29 @@ func (PlatformSpecific) RemmapRstSrc() {
 	}
 
 	dw0 := macro.Register(PAD_CFG_DW0)
-	var remapping = map[uint8]uint32{
-		0: common.RST_RSMRST << common.PadRstCfgShift,
-		1: common.RST_DEEP   << common.PadRstCfgShift,
-		2: common.RST_PLTRST << common.PadRstCfgShift,
-	}
 	resetsrc, valid := remapping[dw0.GetResetConfig()]
 	if valid {
 		// dw0.SetResetConfig(resetsrc)
-		ResetConfigFieldVal := (dw0.ValueGet() & 0x3fffffff) | remapping[dw0.GetResetConfig()]
+		ResetConfigFieldVal := (dw0.ValueGet() & 0x3fffffff) | resetsrc
 		dw0.ValueSet(ResetConfigFieldVal)
-	} else {
-		fmt.Println("Invalid Pad Reset Config [ 0x", resetsrc ," ] for ", macro.PadIdGet())
 	}
 	dw0.CntrMaskFieldsClear(common.PadRstCfgMask)
 }
 
 // Adds The Pad Termination (TERM) parameter from PAD_CFG_DW1 to the macro
 // as a new argument
-func (PlatformSpecific) Pull() {
-	macro := common.GetMacro()
+func (PlatformSpecific) Pull(macro *common.Macro) {
 	dw1 := macro.Register(PAD_CFG_DW1)
-	var pull = map[uint8]string{
-		0x0: "NONE",
-		0x2: "5K_PD",
-		0x4: "20K_PD",
-		0x9: "1K_PU",
-		0xa: "5K_PU",
-		0xb: "2K_PU",
-		0xc: "20K_PU",
-		0xd: "667_PU",
-		0xf: "NATIVE",
-	}
 	str, valid := pull[dw1.GetTermination()]
 	if !valid {
+		// the parser reports the invalid value
 		str = "INVALID"
-		fmt.Println("Error",
-				macro.PadIdGet(),
-				" invalid TERM value = ",
-				int(dw1.GetTermination()))
 	}
 	macro.Separator().Add(str)
 }
 
 // Generate macro to cause peripheral IRQ when configured in GPIO input mode
-func ioApicRoute() bool {
-	macro := common.GetMacro()
+func ioApicRoute(macro *common.Macro) bool {
 	dw0 := macro.Register(PAD_CFG_DW0)
 	if dw0.GetGPIOInputRouteIOxAPIC() == 0 {
 		return false
@@ -104,8 +95,7 @@ func ioApicRoute() bool {
 }
 
 // Generate macro to cause NMI when configured in GPIO input mode
-func nmiRoute() bool {
-	macro := common.GetMacro()
+func nmiRoute(macro *common.Macro) bool {
 	if macro.Register(PAD_CFG_DW0).GetGPIOInputRouteNMI() == 0 {
 		return false
 	}
@@ -115,8 +105,7 @@ func nmiRoute() bool {
 }
 
 // Generate macro to cause SCI when configured in GPIO input mode
-func sciRoute() bool {
-	macro := common.GetMacro()
+func sciRoute(macro *common.Macro) bool {
 	dw0 := macro.Register(PAD_CFG_DW0)
 	if dw0.GetGPIOInputRouteSCI() == 0 {
 		return false
@@ -137,8 +126,7 @@ func sciRoute() bool {
 }
 
 // Generate macro to cause SMI when configured in GPIO input mode
-func smiRoute() bool {
-	macro := common.GetMacro()
+func smiRoute(macro *common.Macro) bool {
 	dw0 := macro.Register(PAD_CFG_DW0)
 	if dw0.GetGPIOInputRouteSMI() == 0 {
 		return false
@@ -157,17 +145,16 @@ func smiRoute() bool {
 }
 
 // Adds PAD_CFG_GPI macro with arguments
-func (PlatformSpecific) GpiMacroAdd() {
-	macro := common.GetMacro()
+func (PlatformSpecific) GpiMacroAdd(macro *common.Macro) {
 	var ids []string
 	macro.Set("PAD_CFG_GPI")
-	for routeid, isRoute := range map[string]func() (bool) {
+	for routeid, isRoute := range map[string]func(macro *common.Macro) bool {
 		"IOAPIC": ioApicRoute,
 		"SCI":    sciRoute,
 		"SMI":    smiRoute,
 		"NMI":    nmiRoute,
 	} {
-		if isRoute() {
+		if isRoute(macro) {
 			ids = append(ids, routeid)
 		}
 	}
@@ -178,7 +165,7 @@ func (PlatformSpecific) GpiMacroAdd() {
 		macro.Add("_TRIG_OWN").Add("(").Id().Pull().Rstsrc().Trig().Own().Add("),")
 	case 1:
 		// GPI with IRQ route
-		if config.AreFieldsIgnored() {
+		if macro.Options.AreFieldsIgnored() {
 			// Set Host Software Ownership to ACPI mode
 			macro.SetPadOwnership(common.PAD_OWN_ACPI)
 		}
@@ -187,7 +174,7 @@ func (PlatformSpecific) GpiMacroAdd() {
 		// PAD_CFG_GPI_DUAL_ROUTE(pad, pull, rst, trig, inv, route1, route2)
 		macro.Set("PAD_CFG_GPI_DUAL_ROUTE(").Id().Pull().Rstsrc().Trig().Invert()
 		macro.Add(", " + ids[0] + ", " + ids[1] + "),")
-		if config.AreFieldsIgnored() {
+		if macro.Options.AreFieldsIgnored() {
 			// Set Host Software Ownership to ACPI mode
 			macro.SetPadOwnership(common.PAD_OWN_ACPI)
 		}
@@ -199,8 +186,7 @@ func (PlatformSpecific) GpiMacroAdd() {
 }
 
 // Adds PAD_CFG_GPO macro with arguments
-func (PlatformSpecific) GpoMacroAdd() {
-	macro := common.GetMacro()
+func (PlatformSpecific) GpoMacroAdd(macro *common.Macro) {
 	dw0 := macro.Register(PAD_CFG_DW0)
 	term := macro.Register(PAD_CFG_DW1).GetTermination()
 
@@ -230,8 +216,7 @@ func (PlatformSpecific) GpoMacroAdd() {
 }
 
 // Adds PAD_CFG_NF macro with arguments
-func (PlatformSpecific) NativeFunctionMacroAdd() {
-	macro := common.GetMacro()
+func (PlatformSpecific) NativeFunctionMacroAdd(macro *common.Macro) {
 	// e.g. PAD_CFG_NF(GPP_D23, NONE, DEEP, NF1)
 	macro.Set("PAD_CFG_NF")
 	if macro.Register(PAD_CFG_DW1).GetPadTol() != 0 {
@@ -241,8 +226,7 @@ func (PlatformSpecific) NativeFunctionMacroAdd() {
 }
 
 // Adds PAD_NC macro
-func (PlatformSpecific) NoConnMacroAdd() {
-	macro := common.GetMacro()
+func (PlatformSpecific) NoConnMacroAdd(macro *common.Macro) {
 	// #define PAD_NC(pad, pull)
 	// _PAD_CFG_STRUCT(pad,
 	//     PAD_FUNC(GPIO) | PAD_RESET(DEEP) | PAD_TRIG(OFF) | PAD_BUF(TX_RX_DISABLE),
@@ -261,17 +245,25 @@ func (PlatformSpecific) NoConnMacroAdd() {
 	macro.Set("PAD_NC").Add("(").Id().Pull().Add("),")
 }
 
+// ReadOnlyFieldsGet - returns the mask of read-only fields
+// number : configuration register number
+func (PlatformSpecific) ReadOnlyFieldsGet(number uint8) uint32 {
+	// There are no DW2/DW3 registers on this platform
+	var ro = [MAX_DW_NUM]uint32{PAD_CFG_DW0_RO_FIELDS, PAD_CFG_DW1_RO_FIELDS,
+			common.AllFields, common.AllFields}
+	return ro[number]
+}
+
 // GenMacro - generate pad macro
 // dw0 : DW0 config register value
 // dw1 : DW1 config register value
+// dw2 : DW2 config register value (not used on this platform)
 // return: string of macro
 //         error
-func (PlatformSpecific) GenMacro(id string, dw0 uint32, dw1 uint32, ownership uint8) string {
-	macro := common.GetInstanceMacro(PlatformSpecific{}, fields.InterfaceGet())
-	macro.Clear()
-	macro.Register(PAD_CFG_DW0).CntrMaskFieldsClear(common.AllFields)
-	macro.Register(PAD_CFG_DW0).CntrMaskFieldsClear(common.AllFields)
-	macro.PadIdSet(id).SetPadOwnership(ownership)
+func (PlatformSpecific) GenMacro(id string, dw0 uint32, dw1 uint32, dw2 uint32, ownership uint8,
+		lock uint8, opts *config.Options) string {
+	macro := common.NewMacro(PlatformSpecific{}, fields.InterfaceGet(opts), opts)
+	macro.PadIdSet(id).SetPadOwnership(ownership).SetPadLock(lock)
 	macro.Register(PAD_CFG_DW0).ValueSet(dw0).ReadOnlyFieldsSet(PAD_CFG_DW0_RO_FIELDS)
 	macro.Register(PAD_CFG_DW1).ValueSet(dw1).ReadOnlyFieldsSet(PAD_CFG_DW1_RO_FIELDS)
 	return macro.Generate()
@@ -0,0 +1,190 @@
+package snr
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// nativeFunctions - Sunrise Point-LP native functions, see the "GPIO Signal
+// Multiplexing" table in the PCH datasheet. The pads that are not in the table
+// are not checked.
+var nativeFunctions = common.NativeFunctions{
+	"GPP_A0":  {"RCIN#"},
+	"GPP_A1":  {"LAD0", "ESPI_IO0"},
+	"GPP_A2":  {"LAD1", "ESPI_IO1"},
+	"GPP_A3":  {"LAD2", "ESPI_IO2"},
+	"GPP_A4":  {"LAD3", "ESPI_IO3"},
+	"GPP_A5":  {"LFRAME#", "ESPI_CS#"},
+	"GPP_A6":  {"SERIRQ"},
+	"GPP_A7":  {"PIRQA#"},
+	"GPP_A8":  {"CLKRUN#"},
+	"GPP_A9":  {"CLKOUT_LPC0", "ESPI_CLK"},
+	"GPP_A10": {"CLKOUT_LPC1"},
+	"GPP_A11": {"PME#"},
+	"GPP_A12": {"BM_BUSY#", "ISH_GP6", "SX_EXIT_HOLDOFF#"},
+	"GPP_A13": {"SUSWARN#/SUSPWRDNACK"},
+	"GPP_A14": {"SUS_STAT#", "ESPI_RESET#"},
+	"GPP_A15": {"SUSACK#"},
+	"GPP_A16": {"SD_1P8_SEL"},
+	"GPP_A17": {"SD_PWR_EN#", "ISH_GP7"},
+	"GPP_A18": {"ISH_GP0"},
+	"GPP_A19": {"ISH_GP1"},
+	"GPP_A20": {"ISH_GP2"},
+	"GPP_A21": {"ISH_GP3"},
+	"GPP_A22": {"ISH_GP4"},
+	"GPP_A23": {"ISH_GP5"},
+
+	"GPP_B0":  {"CORE_VID0"},
+	"GPP_B1":  {"CORE_VID1"},
+	"GPP_B2":  {"VRALERT#"},
+	"GPP_B3":  {"CPU_GP2"},
+	"GPP_B4":  {"CPU_GP3"},
+	"GPP_B5":  {"SRCCLKREQ0#"},
+	"GPP_B6":  {"SRCCLKREQ1#"},
+	"GPP_B7":  {"SRCCLKREQ2#"},
+	"GPP_B8":  {"SRCCLKREQ3#"},
+	"GPP_B9":  {"SRCCLKREQ4#"},
+	"GPP_B10": {"SRCCLKREQ5#"},
+	"GPP_B11": {"EXT_PWR_GATE#"},
+	"GPP_B12": {"SLP_S0#"},
+	"GPP_B13": {"PLTRST#"},
+	"GPP_B14": {"SPKR"},
+	"GPP_B15": {"GSPI0_CS#"},
+	"GPP_B16": {"GSPI0_CLK"},
+	"GPP_B17": {"GSPI0_MISO"},
+	"GPP_B18": {"GSPI0_MOSI"},
+	"GPP_B19": {"GSPI1_CS#"},
+	"GPP_B20": {"GSPI1_CLK"},
+	"GPP_B21": {"GSPI1_MISO"},
+	"GPP_B22": {"GSPI1_MOSI"},
+	"GPP_B23": {"SML1ALERT#", "PCHHOT#"},
+
+	"GPP_C0":  {"SMBCLK"},
+	"GPP_C1":  {"SMBDATA"},
+	"GPP_C2":  {"SMBALERT#"},
+	"GPP_C3":  {"SML0CLK"},
+	"GPP_C4":  {"SML0DATA"},
+	"GPP_C5":  {"SML0ALERT#"},
+	"GPP_C6":  {"SML1CLK"},
+	"GPP_C7":  {"SML1DATA"},
+	"GPP_C8":  {"UART0_RXD"},
+	"GPP_C9":  {"UART0_TXD"},
+	"GPP_C10": {"UART0_RTS#"},
+	"GPP_C11": {"UART0_CTS#"},
+	"GPP_C12": {"UART1_RXD", "ISH_UART1_RXD"},
+	"GPP_C13": {"UART1_TXD", "ISH_UART1_TXD"},
+	"GPP_C14": {"UART1_RTS#", "ISH_UART1_RTS#"},
+	"GPP_C15": {"UART1_CTS#", "ISH_UART1_CTS#"},
+	"GPP_C16": {"I2C0_SDA"},
+	"GPP_C17": {"I2C0_SCL"},
+	"GPP_C18": {"I2C1_SDA"},
+	"GPP_C19": {"I2C1_SCL"},
+	"GPP_C20": {"UART2_RXD"},
+	"GPP_C21": {"UART2_TXD"},
+	"GPP_C22": {"UART2_RTS#"},
+	"GPP_C23": {"UART2_CTS#"},
+
+	"GPP_D0":  {"SPI1_CS#"},
+	"GPP_D1":  {"SPI1_CLK"},
+	"GPP_D2":  {"SPI1_MISO_IO1"},
+	"GPP_D3":  {"SPI1_MOSI_IO0"},
+	"GPP_D4":  {"FLASHTRIG"},
+	"GPP_D5":  {"ISH_I2C0_SDA"},
+	"GPP_D6":  {"ISH_I2C0_SCL"},
+	"GPP_D7":  {"ISH_I2C1_SDA"},
+	"GPP_D8":  {"ISH_I2C1_SCL"},
+	"GPP_D9":  {"ISH_SPI_CS#"},
+	"GPP_D10": {"ISH_SPI_CLK"},
+	"GPP_D11": {"ISH_SPI_MISO"},
+	"GPP_D12": {"ISH_SPI_MOSI"},
+	"GPP_D13": {"ISH_UART0_RXD", "SML0BDATA"},
+	"GPP_D14": {"ISH_UART0_TXD", "SML0BCLK"},
+	"GPP_D15": {"ISH_UART0_RTS#"},
+	"GPP_D16": {"ISH_UART0_CTS#", "SML0BALERT#"},
+	"GPP_D17": {"DMIC_CLK1"},
+	"GPP_D18": {"DMIC_DATA1"},
+	"GPP_D19": {"DMIC_CLK0"},
+	"GPP_D20": {"DMIC_DATA0"},
+	"GPP_D21": {"SPI1_IO2"},
+	"GPP_D22": {"SPI1_IO3"},
+	"GPP_D23": {"I2S_MCLK"},
+
+	"GPP_E0":  {"SATAXPCIE0", "SATAGP0"},
+	"GPP_E1":  {"SATAXPCIE1", "SATAGP1"},
+	"GPP_E2":  {"SATAXPCIE2", "SATAGP2"},
+	"GPP_E3":  {"CPU_GP0"},
+	"GPP_E4":  {"SATA_DEVSLP0"},
+	"GPP_E5":  {"SATA_DEVSLP1"},
+	"GPP_E6":  {"SATA_DEVSLP2"},
+	"GPP_E7":  {"CPU_GP1"},
+	"GPP_E8":  {"SATALED#"},
+	"GPP_E9":  {"USB2_OC0#"},
+	"GPP_E10": {"USB2_OC1#"},
+	"GPP_E11": {"USB2_OC2#"},
+	"GPP_E12": {"USB2_OC3#"},
+	"GPP_E13": {"DDPB_HPD0"},
+	"GPP_E14": {"DDPC_HPD1"},
+	"GPP_E15": {"DDPD_HPD2"},
+	"GPP_E16": {"DDPE_HPD3"},
+	"GPP_E17": {"EDP_HPD"},
+	"GPP_E18": {"DDPB_CTRLCLK"},
+	"GPP_E19": {"DDPB_CTRLDATA"},
+	"GPP_E20": {"DDPC_CTRLCLK"},
+	"GPP_E21": {"DDPC_CTRLDATA"},
+	"GPP_E22": {"DDPD_CTRLCLK"},
+	"GPP_E23": {"DDPD_CTRLDATA"},
+
+	"GPP_F0":  {"I2S2_SCLK"},
+	"GPP_F1":  {"I2S2_SFRM"},
+	"GPP_F2":  {"I2S2_TXD"},
+	"GPP_F3":  {"I2S2_RXD"},
+	"GPP_F4":  {"I2C2_SDA"},
+	"GPP_F5":  {"I2C2_SCL"},
+	"GPP_F6":  {"I2C3_SDA"},
+	"GPP_F7":  {"I2C3_SCL"},
+	"GPP_F8":  {"I2C4_SDA"},
+	"GPP_F9":  {"I2C4_SCL"},
+	"GPP_F10": {"I2C5_SDA", "ISH_I2C2_SDA"},
+	"GPP_F11": {"I2C5_SCL", "ISH_I2C2_SCL"},
+	"GPP_F12": {"EMMC_CMD"},
+	"GPP_F13": {"EMMC_DATA0"},
+	"GPP_F14": {"EMMC_DATA1"},
+	"GPP_F15": {"EMMC_DATA2"},
+	"GPP_F16": {"EMMC_DATA3"},
+	"GPP_F17": {"EMMC_DATA4"},
+	"GPP_F18": {"EMMC_DATA5"},
+	"GPP_F19": {"EMMC_DATA6"},
+	"GPP_F20": {"EMMC_DATA7"},
+	"GPP_F21": {"EMMC_RCLK"},
+	"GPP_F22": {"EMMC_CLK"},
+	"GPP_F23": {},
+
+	"GPP_G0": {"SD_CMD"},
+	"GPP_G1": {"SD_DATA0"},
+	"GPP_G2": {"SD_DATA1"},
+	"GPP_G3": {"SD_DATA2"},
+	"GPP_G4": {"SD_DATA3"},
+	"GPP_G5": {"SD_CD#"},
+	"GPP_G6": {"SD_CLK"},
+	"GPP_G7": {"SD_WP"},
+
+	"GPD0":  {"BATLOW#"},
+	"GPD1":  {"ACPRESENT"},
+	"GPD2":  {"LAN_WAKE#"},
+	"GPD3":  {"PWRBTN#"},
+	"GPD4":  {"SLP_S3#"},
+	"GPD5":  {"SLP_S4#"},
+	"GPD6":  {"SLP_A#"},
+	"GPD7":  {},
+	"GPD8":  {"SUSCLK"},
+	"GPD9":  {"SLP_WLAN#"},
+	"GPD10": {"SLP_S5#"},
+	"GPD11": {"LANPHYPC"},
+}
+
+// NativeFunctionGet - returns the signal name of the native function
+// id   : pad ID string
+// mode : pad mode (PMODE), 1 for NF1
+// return
+//     bool   : true if the pad is in the table
+//     string : signal name, empty if the pad mode has no function
+func (PlatformSpecific) NativeFunctionGet(id string, mode uint8) (bool, string) {
+	return nativeFunctions.Get(id, mode)
+}
@@ -0,0 +1,34 @@
+package snr_test
+
+import (
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/snr"
+
+func TestNativeFunctionGet(t *testing.T) {
+	tests := []struct {
+		id    string
+		mode  uint8
+		known bool
+		name  string
+	}{
+		{"GPP_A1", 1, true, "LAD0"},
+		{"GPP_A1", 2, true, "ESPI_IO0"},
+		{"GPP_A12", 3, true, "SX_EXIT_HOLDOFF#"},
+		// the pad mode has no function
+		{"GPP_A1", 3, true, ""},
+		{"GPD7", 1, true, ""},
+		// GPIO mode
+		{"GPP_A1", 0, true, ""},
+		// the pad is not in the table
+		{"GPP_Z1", 1, false, ""},
+	}
+	for _, test := range tests {
+		known, name := snr.PlatformSpecific{}.NativeFunctionGet(test.id, test.mode)
+		if known != test.known || name != test.name {
+			t.Errorf("%s NF%d: got %v %q, want %v %q", test.id, test.mode, known, name,
+				test.known, test.name)
+		}
+	}
+}
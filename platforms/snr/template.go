@@ -1,6 +1,24 @@
 package snr
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// gpeGroups - Sunrise Point group numbers for the GPE0 routing in the MISCCFG register
+var gpeGroups = map[uint8]string{
+	0x0: "GPP_A",
+	0x1: "GPP_B",
+	0x2: "GPP_C",
+	0x3: "GPP_D",
+	0x4: "GPP_E",
+	0x5: "GPP_F",
+	0x6: "GPP_G",
+	0x7: "GPP_H",
+	0x8: "GPP_I",
+}
 
 // GroupNameExtract - This function extracts the group ID, if it exists in a row
 // line      : string from the configuration file
@@ -35,3 +53,30 @@ func (PlatformSpecific) KeywordCheck(line string) bool {
 	}
 	return false
 }
+
+// GpeGroupGet - returns the group name for the group number in the GPE0_DW0/1/2
+// fields of the MISCCFG register, as defined in coreboot soc/intel/skylake/include/soc/gpio_defs.h
+// number : group number
+// return
+//     bool   : true if the group number is known
+//     string : group identifier
+func (PlatformSpecific) GpeGroupGet(number uint8) (bool, string) {
+	group, valid := gpeGroups[number]
+	return valid, group
+}
+
+// AcpiPinGet - returns the ACPI path of the GPIO controller and the pin number
+// of the pad. The pins are numbered in the order of the register dump without
+// gaps, as the pads in coreboot soc/intel/skylake/include/soc/gpio_soc_defs.h,
+// so the pad ID is used as the pin number if the input is not the dump.
+// pad : pad position
+// return
+//     string : ACPI path of the GPIO controller
+//     string : pin number
+//     bool   : false if the pin number is unknown
+func (PlatformSpecific) AcpiPinGet(pad common.PadPosition) (string, string, bool) {
+	if !pad.Dump {
+		return common.AcpiGpioController, pad.Id, true
+	}
+	return common.AcpiGpioController, fmt.Sprintf("%d", pad.Global), true
+}
@@ -0,0 +1,23 @@
+package tgl
+
+// PullEncode - returns the Pad Termination (TERM) field value for the pull
+// configuration used in the macro
+// name : pull configuration from the macro
+func (platform PlatformSpecific) PullEncode(name string) (uint8, bool) {
+	return platform.InheritanceMacro.PullEncode(name)
+}
+
+// PullDecode - returns the pull configuration name used in the macro for the
+// Pad Termination (TERM) field value
+// term : TERM field value
+func (platform PlatformSpecific) PullDecode(term uint8) (string, bool) {
+	return platform.InheritanceMacro.PullDecode(term)
+}
+
+// RstSrcEncode - returns the Pad Reset Source Config (PADRSTCFG) field value
+// for the logical reset source used in the macro
+// id  : pad id string
+// rst : logical reset source
+func (platform PlatformSpecific) RstSrcEncode(id string, rst uint8) (uint8, bool) {
+	return platform.InheritanceMacro.RstSrcEncode(id, rst)
+}
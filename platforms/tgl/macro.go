@@ -0,0 +1,97 @@
+package tgl
+
+// Local packages
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/fields"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+const (
+	PAD_CFG_DW0_RO_FIELDS = (0x1 << 27) | (0x1 << 24) | (0x3 << 21) | (0xf << 16) | 0xfc
+	PAD_CFG_DW1_RO_FIELDS = 0xfdfc00ff
+	PAD_CFG_DW2_RO_FIELDS = 0xffffffe0
+	PAD_CFG_DW3_RO_FIELDS = 0xffffffff
+)
+
+const (
+	PAD_CFG_DW0 = common.PAD_CFG_DW0
+	PAD_CFG_DW1 = common.PAD_CFG_DW1
+	PAD_CFG_DW2 = common.PAD_CFG_DW2
+	MAX_DW_NUM  = common.MAX_DW_NUM
+)
+
+type InheritanceMacro interface {
+	RemmapRstSrc(macro *common.Macro)
+	Pull(macro *common.Macro)
+	GpiMacroAdd(macro *common.Macro)
+	GpoMacroAdd(macro *common.Macro)
+	NativeFunctionMacroAdd(macro *common.Macro)
+	NoConnMacroAdd(macro *common.Macro)
+	PullEncode(pull string) (uint8, bool)
+	PullDecode(term uint8) (string, bool)
+	RstSrcEncode(id string, rst uint8) (uint8, bool)
+}
+
+type PlatformSpecific struct {
+	InheritanceMacro
+}
+
+// RemmapRstSrc - remmap Pad Reset Source Config
+func (platform PlatformSpecific) RemmapRstSrc(macro *common.Macro) {
+	// See src/soc/intel/tigerlake/gpio.c: rst_map[] and rst_map_com2[] for
+	// the GPD group are the same as in Cannon Lake
+	platform.InheritanceMacro.RemmapRstSrc(macro)
+}
+
+// Adds The Pad Termination (TERM) parameter from PAD_CFG_DW1 to the macro
+// as a new argument
+func (platform PlatformSpecific) Pull(macro *common.Macro) {
+	platform.InheritanceMacro.Pull(macro)
+}
+
+// Adds PAD_CFG_GPI macro with arguments
+func (platform PlatformSpecific) GpiMacroAdd(macro *common.Macro) {
+	platform.InheritanceMacro.GpiMacroAdd(macro)
+}
+
+// Adds PAD_CFG_GPO macro with arguments
+func (platform PlatformSpecific) GpoMacroAdd(macro *common.Macro) {
+	platform.InheritanceMacro.GpoMacroAdd(macro)
+}
+
+// Adds PAD_CFG_NF macro with arguments
+func (platform PlatformSpecific) NativeFunctionMacroAdd(macro *common.Macro) {
+	platform.InheritanceMacro.NativeFunctionMacroAdd(macro)
+}
+
+// Adds PAD_NC macro
+func (platform PlatformSpecific) NoConnMacroAdd(macro *common.Macro) {
+	platform.InheritanceMacro.NoConnMacroAdd(macro)
+}
+
+// ReadOnlyFieldsGet - returns the mask of read-only fields
+// number : configuration register number
+func (PlatformSpecific) ReadOnlyFieldsGet(number uint8) uint32 {
+	var ro = [MAX_DW_NUM]uint32{PAD_CFG_DW0_RO_FIELDS, PAD_CFG_DW1_RO_FIELDS,
+			PAD_CFG_DW2_RO_FIELDS, PAD_CFG_DW3_RO_FIELDS}
+	return ro[number]
+}
+
+// GenMacro - generate pad macro
+// dw0 : DW0 config register value
+// dw1 : DW1 config register value
+// dw2 : DW2 config register value
+// return: string of macro
+//         error
+func (platform PlatformSpecific) GenMacro(id string, dw0 uint32, dw1 uint32, dw2 uint32, ownership uint8,
+		lock uint8, opts *config.Options) string {
+	// The GPIO controller architecture in Tiger Lake and Cannon Lake are very
+	// similar, so we will inherit some platform-dependent functions from Cannon
+	// Lake. Tiger Lake also has the DW2 register with the debounce settings.
+	macro := common.NewMacro(PlatformSpecific{InheritanceMacro : platform.InheritanceMacro},
+			fields.InterfaceGet(opts), opts)
+	macro.PadIdSet(id).SetPadOwnership(ownership).SetPadLock(lock)
+	macro.Register(PAD_CFG_DW0).ValueSet(dw0).ReadOnlyFieldsSet(PAD_CFG_DW0_RO_FIELDS)
+	macro.Register(PAD_CFG_DW1).ValueSet(dw1).ReadOnlyFieldsSet(PAD_CFG_DW1_RO_FIELDS)
+	macro.Register(PAD_CFG_DW2).ValueSet(dw2).ReadOnlyFieldsSet(PAD_CFG_DW2_RO_FIELDS)
+	return macro.Generate()
+}
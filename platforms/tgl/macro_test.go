@@ -0,0 +1,70 @@
+package tgl_test
+
+import (
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/config"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/apl"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/cnl"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/tgl"
+
+// debounceMacro - PAD_CFG_GPI_TRIG_OWN(GPP_B1, NONE, PLTRST, OFF, ACPI) in the
+// bit field form, the fields are taken from DW0/DW1
+const debounceMacro = "_PAD_CFG_STRUCT_3(GPP_B1, PAD_FUNC(GPIO) | PAD_RESET(PLTRST) | " +
+	"PAD_TRIG(OFF) | PAD_BUF(TX_DISABLE), 0, "
+
+func TestGenMacro(t *testing.T) {
+	tests := []struct {
+		dw0   uint32
+		dw1   uint32
+		dw2   uint32
+		macro string
+	}{
+		{0x40000400, 0x00003000, 0x0, "PAD_CFG_NF(GPP_B1, UP_20K, DEEP, NF1),"},
+		{0x44000200, 0x00003000, 0x0, "PAD_CFG_TERM_GPO(GPP_B1, 0, UP_20K, DEEP),"},
+		{0x84000100, 0x00000000, 0x0, "PAD_CFG_GPI_TRIG_OWN(GPP_B1, NONE, PLTRST, OFF, ACPI),"},
+		// the debounce settings from DW2 can only be set with _PAD_CFG_STRUCT_3
+		{0x84000100, 0x00000000, 0x7,
+			debounceMacro + "PAD_CFG2_DEBEN | PAD_CFG2_DEBOUNCE_8_RTC),"},
+		{0x84000100, 0x00000000, 0x1f,
+			debounceMacro + "PAD_CFG2_DEBEN | PAD_CFG2_DEBOUNCE_32K_RTC),"},
+		{0x84000100, 0x00000000, 0x6, debounceMacro + "PAD_CFG2_DEBOUNCE_8_RTC),"},
+	}
+	opts := &config.Options{}
+	opts.PlatformSet("tgl")
+	opts.FldStyleSet("none")
+	platform := tgl.PlatformSpecific{
+		InheritanceMacro: cnl.PlatformSpecific{InheritanceMacro: apl.PlatformSpecific{}},
+	}
+	for _, test := range tests {
+		macro := platform.GenMacro("GPP_B1", test.dw0, test.dw1, test.dw2,
+			common.PAD_OWN_ACPI, common.PAD_LOCK_DEFAULT, opts)
+		if macro != test.macro {
+			t.Errorf("0x%08x 0x%08x 0x%08x: got %s, want %s", test.dw0, test.dw1, test.dw2,
+				macro, test.macro)
+			continue
+		}
+		cfg, err := common.MacroEncode(platform, macro)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", macro, err)
+			continue
+		}
+		dw0 := cfg.Register(common.PAD_CFG_DW0).ValueGet()
+		dw1 := cfg.Register(common.PAD_CFG_DW1).ValueGet()
+		dw2 := cfg.Register(common.PAD_CFG_DW2).ValueGet()
+		if dw0 != test.dw0 || dw1 != test.dw1 || dw2 != test.dw2 {
+			t.Errorf("%s: encoded 0x%08x 0x%08x 0x%08x", macro, dw0, dw1, dw2)
+		}
+	}
+
+	for _, macro := range []string{
+		"_PAD_CFG_STRUCT_3(GPP_B1, 0x84000100, 0),",
+		"_PAD_CFG_STRUCT_3(GPP_B1, 0x84000100, 0, PAD_CFG2_DEBOUNCE_4_RTC),",
+	} {
+		if _, err := common.MacroEncode(platform, macro); err == nil {
+			t.Errorf("%s: no error", macro)
+		}
+	}
+}
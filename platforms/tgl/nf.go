@@ -0,0 +1,81 @@
+package tgl
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/common"
+
+// nativeFunctions - Tiger Lake-LP native functions, see the "GPIO Signal
+// Multiplexing" table in the PCH datasheet. The pads that are not in the table
+// are not checked.
+var nativeFunctions = common.NativeFunctions{
+	"GPP_B0":  {"CORE_VID0"},
+	"GPP_B1":  {"CORE_VID1"},
+	"GPP_B2":  {"VRALERT#"},
+	"GPP_B3":  {"CPU_GP2"},
+	"GPP_B4":  {"CPU_GP3"},
+	"GPP_B5":  {"SRCCLKREQ0#"},
+	"GPP_B6":  {"SRCCLKREQ1#"},
+	"GPP_B7":  {"SRCCLKREQ2#"},
+	"GPP_B8":  {"SRCCLKREQ3#"},
+	"GPP_B9":  {"SRCCLKREQ4#"},
+	"GPP_B10": {"SRCCLKREQ5#"},
+	"GPP_B11": {"PMCALERT#"},
+	"GPP_B12": {"SLP_S0#"},
+	"GPP_B13": {"PLTRST#"},
+	"GPP_B14": {"SPKR"},
+	"GPP_B15": {"GSPI0_CS0#"},
+	"GPP_B16": {"GSPI0_CLK"},
+	"GPP_B17": {"GSPI0_MISO"},
+	"GPP_B18": {"GSPI0_MOSI"},
+	"GPP_B19": {"GSPI1_CS0#"},
+	"GPP_B20": {"GSPI1_CLK"},
+	"GPP_B21": {"GSPI1_MISO"},
+	"GPP_B22": {"GSPI1_MOSI"},
+	"GPP_B23": {"SML1ALERT#", "PCHHOT#"},
+
+	"GPP_C0":  {"SMBCLK"},
+	"GPP_C1":  {"SMBDATA"},
+	"GPP_C2":  {"SMBALERT#"},
+	"GPP_C3":  {"SML0CLK"},
+	"GPP_C4":  {"SML0DATA"},
+	"GPP_C5":  {"SML0ALERT#"},
+	"GPP_C6":  {"SML1CLK"},
+	"GPP_C7":  {"SML1DATA"},
+	"GPP_C8":  {"UART0_RXD"},
+	"GPP_C9":  {"UART0_TXD"},
+	"GPP_C10": {"UART0_RTS#"},
+	"GPP_C11": {"UART0_CTS#"},
+	"GPP_C12": {"UART1_RXD", "ISH_UART1_RXD"},
+	"GPP_C13": {"UART1_TXD", "ISH_UART1_TXD"},
+	"GPP_C14": {"UART1_RTS#", "ISH_UART1_RTS#"},
+	"GPP_C15": {"UART1_CTS#", "ISH_UART1_CTS#"},
+	"GPP_C16": {"I2C0_SDA"},
+	"GPP_C17": {"I2C0_SCL"},
+	"GPP_C18": {"I2C1_SDA"},
+	"GPP_C19": {"I2C1_SCL"},
+	"GPP_C20": {"UART2_RXD"},
+	"GPP_C21": {"UART2_TXD"},
+	"GPP_C22": {"UART2_RTS#"},
+	"GPP_C23": {"UART2_CTS#"},
+
+	"GPD0":  {"BATLOW#"},
+	"GPD1":  {"ACPRESENT"},
+	"GPD2":  {"LAN_WAKE#"},
+	"GPD3":  {"PWRBTN#"},
+	"GPD4":  {"SLP_S3#"},
+	"GPD5":  {"SLP_S4#"},
+	"GPD6":  {"SLP_A#"},
+	"GPD7":  {},
+	"GPD8":  {"SUSCLK"},
+	"GPD9":  {"SLP_WLAN#"},
+	"GPD10": {"SLP_S5#"},
+	"GPD11": {"LANPHYPC"},
+}
+
+// NativeFunctionGet - returns the signal name of the native function
+// id   : pad ID string
+// mode : pad mode (PMODE), 1 for NF1
+// return
+//     bool   : true if the pad is in the table
+//     string : signal name, empty if the pad mode has no function
+func (PlatformSpecific) NativeFunctionGet(id string, mode uint8) (bool, string) {
+	return nativeFunctions.Get(id, mode)
+}
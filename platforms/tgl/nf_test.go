@@ -0,0 +1,30 @@
+package tgl_test
+
+import (
+	"testing"
+)
+
+import "review.coreboot.org/coreboot.git/util/intelp2m/platforms/tgl"
+
+func TestNativeFunctionGet(t *testing.T) {
+	tests := []struct {
+		id    string
+		mode  uint8
+		known bool
+		name  string
+	}{
+		{"GPP_B1", 1, true, "CORE_VID1"},
+		{"GPP_B23", 2, true, "PCHHOT#"},
+		{"GPP_C0", 1, true, "SMBCLK"},
+		{"GPP_B23", 3, true, ""},
+		{"GPD7", 1, true, ""},
+		{"GPP_A1", 1, false, ""},
+	}
+	for _, test := range tests {
+		known, name := tgl.PlatformSpecific{}.NativeFunctionGet(test.id, test.mode)
+		if known != test.known || name != test.name {
+			t.Errorf("%s NF%d: got %v %q, want %v %q", test.id, test.mode, known, name,
+				test.known, test.name)
+		}
+	}
+}